@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"gateService/internal/application/service"
+	"gateService/internal/infrastructure/config"
+	"gateService/internal/infrastructure/database"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/mq/nsqpool"
+	"os"
+)
+
+// runCatalogCommand 执行视频目录的导入导出子命令
+// 用法:
+//
+//	gateService catalog import -file catalog.csv [-format csv] [-dry-run] -uploader 1
+//	gateService catalog export -file catalog.json [-format json]
+func runCatalogCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "用法: catalog import|export [参数]")
+		os.Exit(2)
+	}
+
+	var err error
+	switch args[0] {
+	case "import":
+		err = runCatalogImport(args[1:])
+	case "export":
+		err = runCatalogExport(args[1:])
+	default:
+		err = fmt.Errorf("未知的子命令: %s", args[0])
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func runCatalogImport(args []string) error {
+	flags := flag.NewFlagSet("catalog import", flag.ExitOnError)
+	configPath := flags.String("config", "../configs/config.yaml", "配置文件路径")
+	file := flags.String("file", "", "导入文件路径")
+	format := flags.String("format", "", "文件格式,csv或json,默认根据扩展名判断")
+	dryRun := flags.Bool("dry-run", false, "只生成差异报告,不写入数据库")
+	uploader := flags.Int("uploader", 0, "新建视频的上传者用户ID")
+	flags.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("请通过-file指定导入文件")
+	}
+
+	catalogService, closeFn, err := newCatalogService(*configPath)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	response, err := catalogService.ImportCatalog(context.Background(), &dto.CatalogImportRequest{
+		UserID:   *uploader,
+		Format:   *format,
+		DryRun:   *dryRun,
+		FilePath: *file,
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(response); err != nil {
+		return err
+	}
+
+	if response.Report.Invalid > 0 || response.Report.Conflicts > 0 {
+		return fmt.Errorf("存在%d条无效记录和%d条冲突记录", response.Report.Invalid, response.Report.Conflicts)
+	}
+	return nil
+}
+
+func runCatalogExport(args []string) error {
+	flags := flag.NewFlagSet("catalog export", flag.ExitOnError)
+	configPath := flags.String("config", "../configs/config.yaml", "配置文件路径")
+	file := flags.String("file", "", "导出文件路径,默认输出到标准输出")
+	format := flags.String("format", "json", "文件格式,csv或json")
+	flags.Parse(args)
+
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("不支持的文件格式: %s", *format)
+	}
+
+	catalogService, closeFn, err := newCatalogService(*configPath)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	output := os.Stdout
+	if *file != "" {
+		output, err = os.Create(*file)
+		if err != nil {
+			return fmt.Errorf("创建导出文件失败: %v", err)
+		}
+		defer output.Close()
+	}
+
+	return catalogService.ExportCatalog(context.Background(), &dto.CatalogExportRequest{Format: *format}, output)
+}
+
+// newCatalogService 只初始化目录导入导出所需的数据库连接、消息生产者和服务,不启动HTTP服务和消费者
+// 导入完成后发布目录变更消息,运行中的实例据此更新检索索引
+func newCatalogService(configPath string) (*service.CatalogServiceImpl, func(), error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	producerPool, err := nsqpool.NewProducerPool(&nsqpool.ProducerOptions{
+		NSQDAddress: cfg.GetNSQDAddr(),
+		PoolSize:    1,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("初始化NSQ生产者池失败: %v", err)
+	}
+
+	db := database.NewDB(cfg)
+	rdb := database.NewRDB(cfg)
+	videoRepository := database.NewVideoRepositoryImpl(db.GetDB(), rdb.GetRDB())
+
+	return service.NewCatalogServiceImpl(videoRepository, producerPool), func() {
+		producerPool.Close()
+		rdb.Close()
+		db.Close()
+	}, nil
+}
@@ -16,6 +16,12 @@ func main() {
 	// 初始化日志
 	logger.InitLogger("development") // 或 "production"
 
+	// 视频目录导入导出子命令,执行完成后直接退出
+	if len(os.Args) > 1 && os.Args[1] == "catalog" {
+		runCatalogCommand(os.Args[2:])
+		return
+	}
+
 	// 设置错误处理配置
 	errors.SetErrorConfig(errors.ErrorConfig{
 		Env:            "development",
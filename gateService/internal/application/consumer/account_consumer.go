@@ -5,21 +5,32 @@ import (
 	"fmt"
 	"gateService/internal/domain/repository"
 	"gateService/internal/infrastructure/config"
+	"gateService/pkg/logger"
 	"gateService/pkg/mq/nsqpool"
 	"log"
 	"strconv"
+	"time"
+
+	"go.uber.org/zap"
 )
 
 type AccountConsumer struct {
-	jwtConfig           *config.JWTConfig
-	userRepository      repository.UserRepository
-	accountConsumerPool *nsqpool.ConsumerPool
+	jwtConfig             *config.JWTConfig
+	exportConfig          *config.ExportConfig
+	userRepository        repository.UserRepository
+	tokenRepository       repository.TokenRepository
+	accountDataRepository repository.AccountDataRepository
+	accountConsumerPool   *nsqpool.ConsumerPool
+	exportConsumerPool    *nsqpool.ConsumerPool
 }
 
-func NewAccountConsumer(jwtConfig *config.JWTConfig, userRepository repository.UserRepository) *AccountConsumer {
+func NewAccountConsumer(jwtConfig *config.JWTConfig, exportConfig *config.ExportConfig, userRepository repository.UserRepository, tokenRepository repository.TokenRepository, accountDataRepository repository.AccountDataRepository) *AccountConsumer {
 	return &AccountConsumer{
-		jwtConfig:      jwtConfig,
-		userRepository: userRepository,
+		jwtConfig:             jwtConfig,
+		exportConfig:          exportConfig,
+		userRepository:        userRepository,
+		tokenRepository:       tokenRepository,
+		accountDataRepository: accountDataRepository,
 	}
 }
 
@@ -29,26 +40,50 @@ func (c *AccountConsumer) DeleteTestAccount(ctx context.Context, msg []byte) err
 		return fmt.Errorf("转换用户ID失败: %v", err)
 	}
 
+	// 体验账号已转为正式账号时忽略延迟删除消息
+	converted, _, err := c.userRepository.CheckInRedis(ctx, "test_account:converted:"+string(msg))
+	if err != nil {
+		return fmt.Errorf("检查体验账号是否已转正失败: %v", err)
+	}
+	if converted {
+		return nil
+	}
+
 	tx, err := c.userRepository.BeginTx(ctx)
 	if err != nil {
 		return fmt.Errorf("开始事务失败: %v", err)
 	}
 	defer tx.Rollback()
 
-	err = c.userRepository.DeleteUser(ctx, tx, userID)
+	// 以数据库中的邮箱为准再次确认,转正与删除并发时只有一方能成功
+	deleted, err := c.userRepository.DeleteTestAccount(ctx, tx, userID)
 	if err != nil {
 		return fmt.Errorf("删除用户失败: %v", err)
 	}
+	if !deleted {
+		logger.Log.Info("体验账号已转为正式账号或已删除,跳过删除", zap.Int("userID", userID))
+		return nil
+	}
 
 	err = c.userRepository.SetInRedis(ctx, "test_account:deleted:"+string(msg), 0, c.jwtConfig.AccessToken.ExpireTime)
 	if err != nil {
 		return fmt.Errorf("设置用户ID为0失败: %v", err)
 	}
 
+	err = c.tokenRepository.RevokeUserTokens(ctx, userID, time.Now(), c.jwtConfig.RefreshToken.ExpireTime)
+	if err != nil {
+		return fmt.Errorf("吊销用户令牌失败: %v", err)
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("提交事务失败: %v", err)
 	}
 
+	// 提交后再删除角色缓存,避免提交前的查询重新缓存原角色;账号已删除,失败只记录日志
+	if err := c.userRepository.DeleteUserRoleCache(ctx, userID); err != nil {
+		logger.Log.Warn("删除用户角色缓存失败", zap.Int("userID", userID), zap.Error(err))
+	}
+
 	return nil
 }
 
@@ -71,8 +106,28 @@ func (c *AccountConsumer) Start() {
 	if err != nil {
 		log.Fatalf("启动体验账号消费者池失败: %v", err)
 	}
+
+	exportConsumerPool, err := nsqpool.NewConsumerPool(
+		&nsqpool.ConsumerOptions{
+			Topic:    "user_export_queue",
+			Channel:  "user_export_channel",
+			PoolSize: 1,
+		},
+	)
+	if err != nil {
+		log.Fatalf("创建数据导出消费者池失败: %v", err)
+	}
+	c.exportConsumerPool = exportConsumerPool
+
+	exportConsumerPool.RegisterCallback(c.ExportUserData)
+
+	err = exportConsumerPool.Start()
+	if err != nil {
+		log.Fatalf("启动数据导出消费者池失败: %v", err)
+	}
 }
 
 func (c *AccountConsumer) Stop() {
 	c.accountConsumerPool.Stop()
+	c.exportConsumerPool.Stop()
 }
@@ -0,0 +1,139 @@
+package consumer
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ExportUserData 执行用户数据导出任务,将各类数据写入ZIP中的JSON文件
+// 导出失败时记录到任务状态中由用户重新发起,不重新投递消息
+func (c *AccountConsumer) ExportUserData(ctx context.Context, msg []byte) error {
+	jobID := string(msg)
+	job, err := c.accountDataRepository.GetExportJob(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("获取导出任务失败: %v", err)
+	}
+	// 任务已过期或已执行完成时忽略重复投递
+	if job == nil || job.Status == entity.DataExportDone || job.Status == entity.DataExportFailed {
+		return nil
+	}
+
+	job.Status = entity.DataExportRunning
+	if err := c.accountDataRepository.SaveExportJob(ctx, job, c.exportConfig.TTL); err != nil {
+		return fmt.Errorf("更新导出任务状态失败: %v", err)
+	}
+
+	fileName, err := c.writeExportFile(ctx, job)
+	if err != nil {
+		logger.Log.Error("导出用户数据失败", zap.String("jobID", jobID), zap.Int("userID", job.UserID), zap.Error(err))
+		job.Status = entity.DataExportFailed
+		job.Error = "导出失败,请稍后重试"
+	} else {
+		job.Status = entity.DataExportDone
+		job.FileName = fileName
+	}
+	job.FinishedAt = time.Now().Format("2006-01-02 15:04:05")
+
+	if err := c.accountDataRepository.SaveExportJob(ctx, job, c.exportConfig.TTL); err != nil {
+		return fmt.Errorf("更新导出任务状态失败: %v", err)
+	}
+
+	c.cleanupExpiredExports()
+	return nil
+}
+
+// writeExportFile 读取用户数据并写入导出目录,先写临时文件再重命名,避免下载到不完整的文件
+// 返回:
+// - string: 导出文件名
+// - error: 错误信息
+func (c *AccountConsumer) writeExportFile(ctx context.Context, job *entity.DataExportJob) (string, error) {
+	userInfo, err := c.userRepository.GetUserByID(ctx, job.UserID)
+	if err != nil {
+		return "", fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	if userInfo.Status != 1 {
+		return "", fmt.Errorf("用户已注销")
+	}
+
+	sections, err := c.accountDataRepository.ExportUserData(ctx, job.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(c.exportConfig.Path, 0700); err != nil {
+		return "", fmt.Errorf("创建导出目录失败: %v", err)
+	}
+
+	fileName := job.JobID + ".zip"
+	tmpPath := filepath.Join(c.exportConfig.Path, fileName+".tmp")
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", fmt.Errorf("创建导出文件失败: %v", err)
+	}
+	defer os.Remove(tmpPath)
+
+	if err := writeExportZip(file, sections); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("写入导出文件失败: %v", err)
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(c.exportConfig.Path, fileName)); err != nil {
+		return "", fmt.Errorf("保存导出文件失败: %v", err)
+	}
+	return fileName, nil
+}
+
+// writeExportZip 将每类数据写为ZIP中的一个JSON文件
+func writeExportZip(file *os.File, sections []*entity.UserDataSection) error {
+	zw := zip.NewWriter(file)
+	for _, section := range sections {
+		w, err := zw.Create(section.Name + ".json")
+		if err != nil {
+			return fmt.Errorf("写入%s失败: %v", section.Name, err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(section.Rows); err != nil {
+			return fmt.Errorf("写入%s失败: %v", section.Name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("写入导出文件失败: %v", err)
+	}
+	return nil
+}
+
+// cleanupExpiredExports 删除超过保留时间的导出文件,此时对应任务已从Redis中过期
+func (c *AccountConsumer) cleanupExpiredExports() {
+	entries, err := os.ReadDir(c.exportConfig.Path)
+	if err != nil {
+		return
+	}
+
+	deadline := time.Now().Add(-c.exportConfig.TTL)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".zip") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.ModTime().After(deadline) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.exportConfig.Path, e.Name())); err != nil {
+			logger.Log.Warn("删除过期导出文件失败", zap.String("file", e.Name()), zap.Error(err))
+		}
+	}
+}
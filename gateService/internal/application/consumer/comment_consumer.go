@@ -2,6 +2,7 @@ package consumer
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"gateService/internal/domain/entity"
@@ -9,7 +10,6 @@ import (
 	"gateService/internal/infrastructure/middleware/websocket"
 	"gateService/pkg/mq/nsqpool"
 	"log"
-	"strconv"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -20,6 +20,7 @@ type CommentConsumer struct {
 	postRepository        repository.PostRepository        // 帖子持久化存储接口
 	postCommentRepository repository.PostCommentRepository // 评论持久化存储接口
 	userRepository        repository.UserRepository
+	blockRepository       repository.BlockRepository
 	commentConsumerPool   *nsqpool.ConsumerPool // NSQ消费者池实例
 	likeConsumerPool      *nsqpool.ConsumerPool // NSQ消费者池实例
 	websocketManager      *websocket.Manager
@@ -27,11 +28,12 @@ type CommentConsumer struct {
 
 // NewCommentConsumer 构造函数，创建新的评论消费者实例
 // 参数: postCommentRepository - 评论存储仓库实现
-func NewCommentConsumer(postRepository repository.PostRepository, postCommentRepository repository.PostCommentRepository, userRepository repository.UserRepository, websocketManager *websocket.Manager) *CommentConsumer {
+func NewCommentConsumer(postRepository repository.PostRepository, postCommentRepository repository.PostCommentRepository, userRepository repository.UserRepository, blockRepository repository.BlockRepository, websocketManager *websocket.Manager) *CommentConsumer {
 	return &CommentConsumer{
 		postRepository:        postRepository,
 		postCommentRepository: postCommentRepository,
 		userRepository:        userRepository,
+		blockRepository:       blockRepository,
 		websocketManager:      websocketManager,
 	}
 }
@@ -106,19 +108,14 @@ func (c *CommentConsumer) persistComment(ctx context.Context, msg []byte) error
 		notification := &entity.UserNotification{
 			UserID:           *postComment.ToUserID,
 			FromUserID:       postComment.UserID,
-			NotificationType: 2,
+			NotificationType: entity.NotificationTypeCommentReply,
 			Content:          postComment.Content,
 			PostID:           &postComment.PostID,
 			CommentID:        &postComment.CommentID,
 		}
-		err = c.userRepository.CreateUserNotification(ctx, tx, notification)
+		err = c.notify(ctx, tx, notification)
 		if err != nil {
-			return fmt.Errorf("创建用户通知失败: %v", err)
-		}
-
-		err = c.sendNotification(ctx, notification)
-		if err != nil {
-			return fmt.Errorf("发送通知失败: %v", err)
+			return err
 		}
 	}
 
@@ -192,19 +189,14 @@ func (c *CommentConsumer) updateCommentLike(ctx context.Context, msg []byte) err
 		notification := &entity.UserNotification{
 			UserID:           toUserID,
 			FromUserID:       commentLike.UserID,
-			NotificationType: 1,
+			NotificationType: entity.NotificationTypeCommentLike,
 			Content:          "点赞了你的评论",
 			CommentID:        &commentLike.CommentID,
 		}
 
-		err = c.userRepository.CreateUserNotification(ctx, tx, notification)
-		if err != nil {
-			return fmt.Errorf("创建用户通知失败: %v", err)
-		}
-
-		err = c.sendNotification(ctx, notification)
+		err = c.notify(ctx, tx, notification)
 		if err != nil {
-			return fmt.Errorf("发送通知失败: %v", err)
+			return err
 		}
 	}
 
@@ -218,44 +210,30 @@ func (c *CommentConsumer) updateCommentLike(ctx context.Context, msg []byte) err
 	return nil
 }
 
-func (c *CommentConsumer) sendNotification(ctx context.Context, notification *entity.UserNotification) error {
-	user, err := c.userRepository.GetUserByID(ctx, notification.FromUserID)
+// notify 在事务中创建用户通知并实时推送,接收者拉黑或静音了发送者时不产生通知
+func (c *CommentConsumer) notify(ctx context.Context, tx *sql.Tx, notification *entity.UserNotification) error {
+	suppressed, err := notificationSuppressed(ctx, c.blockRepository, notification.UserID, notification.FromUserID)
 	if err != nil {
-		return fmt.Errorf("获取对方用户信息失败: %v", err)
+		return err
 	}
-
-	notificationMsg := &NotificationMessage{
-		SendUserName: user.Username,
-		Content:      notification.Content,
+	if suppressed {
+		return nil
 	}
 
-	switch notification.NotificationType {
-	case 1:
-		notificationMsg.MsgType = "WS_COMMENT_LIKE"
-		notificationMsg.Title = user.Username + "点赞了你的评论"
-	case 2:
-		notificationMsg.MsgType = "WS_COMMENT_REPLY"
-		notificationMsg.Title = user.Username + "回复了你的评论"
-	case 3:
-		notificationMsg.MsgType = "WS_POST_FAVORITE"
-		notificationMsg.Title = user.Username + "收藏了你的帖子"
-	case 4:
-		notificationMsg.MsgType = "WS_POST_LIKE"
-		notificationMsg.Title = user.Username + "点赞了你的帖子"
-	case 5:
-		notificationMsg.MsgType = "WS_FOLLOW"
-		notificationMsg.Title = user.Username + "关注了你"
-	case 6:
-		notificationMsg.MsgType = "WS_SYSTEM"
-		notificationMsg.Title = "系统消息"
+	err = c.userRepository.CreateUserNotification(ctx, tx, notification)
+	if err != nil {
+		return fmt.Errorf("创建用户通知失败: %v", err)
 	}
 
-	notificationMsgJson, err := json.Marshal(notificationMsg)
+	err = c.sendNotification(ctx, notification)
 	if err != nil {
-		return fmt.Errorf("序列化通知消息失败: %v", err)
+		return fmt.Errorf("发送通知失败: %v", err)
 	}
+	return nil
+}
 
-	return c.websocketManager.SendMessage(strconv.Itoa(notification.UserID), notificationMsgJson)
+func (c *CommentConsumer) sendNotification(ctx context.Context, notification *entity.UserNotification) error {
+	return sendNotification(ctx, c.userRepository, c.websocketManager, notification)
 }
 
 // Start 启动评论消费者
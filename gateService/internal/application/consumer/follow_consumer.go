@@ -0,0 +1,115 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/middleware/websocket"
+	"gateService/pkg/mq/nsqpool"
+	"log"
+	"time"
+)
+
+// 同一关注关系在该时间内只通知一次,防止反复关注/取消关注刷通知
+const followNotifyInterval = 24 * time.Hour
+
+// FollowConsumer 负责处理关注消息的消费者,创建关注通知并实时推送
+type FollowConsumer struct {
+	followRepository repository.FollowRepository
+	blockRepository  repository.BlockRepository
+	userRepository   repository.UserRepository
+	consumerPool     *nsqpool.ConsumerPool
+	websocketManager *websocket.Manager
+}
+
+func NewFollowConsumer(followRepository repository.FollowRepository, blockRepository repository.BlockRepository, userRepository repository.UserRepository, websocketManager *websocket.Manager) *FollowConsumer {
+	return &FollowConsumer{
+		followRepository: followRepository,
+		blockRepository:  blockRepository,
+		userRepository:   userRepository,
+		websocketManager: websocketManager,
+	}
+}
+
+// notifyFollow 为新的关注关系创建用户通知并推送给被关注者
+func (c *FollowConsumer) notifyFollow(ctx context.Context, msg []byte) error {
+	var follow entity.UserFollow
+	if err := json.Unmarshal(msg, &follow); err != nil {
+		return fmt.Errorf("解析关注消息失败: %v", err)
+	}
+
+	// 消息处理前已取消关注的不再通知
+	following, err := c.followRepository.IsFollowing(ctx, follow.FollowerID, follow.FolloweeID)
+	if err != nil {
+		return fmt.Errorf("获取关注关系失败: %v", err)
+	}
+	if !following {
+		return nil
+	}
+
+	// 被关注者静音了关注者时不再通知
+	suppressed, err := notificationSuppressed(ctx, c.blockRepository, follow.FolloweeID, follow.FollowerID)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		return nil
+	}
+
+	first, err := c.followRepository.MarkFollowNotified(ctx, follow.FollowerID, follow.FolloweeID, followNotifyInterval)
+	if err != nil {
+		return fmt.Errorf("记录关注通知状态失败: %v", err)
+	}
+	if !first {
+		return nil
+	}
+
+	tx, err := c.userRepository.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	notification := &entity.UserNotification{
+		UserID:           follow.FolloweeID,
+		FromUserID:       follow.FollowerID,
+		NotificationType: entity.NotificationTypeFollow,
+		Content:          "关注了你",
+	}
+	if err := c.userRepository.CreateUserNotification(ctx, tx, notification); err != nil {
+		return fmt.Errorf("创建用户通知失败: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+
+	if err := sendNotification(ctx, c.userRepository, c.websocketManager, notification); err != nil {
+		return fmt.Errorf("发送通知失败: %v", err)
+	}
+	return nil
+}
+
+func (c *FollowConsumer) Start() {
+	consumerPool, err := nsqpool.NewConsumerPool(&nsqpool.ConsumerOptions{
+		Topic:    "follow_channel",
+		Channel:  "follow_channel",
+		PoolSize: 2,
+	})
+	if err != nil {
+		log.Fatalf("创建关注消费者池失败: %v\n", err)
+	}
+	c.consumerPool = consumerPool
+
+	consumerPool.RegisterCallback(c.notifyFollow)
+	err = consumerPool.Start()
+	if err != nil {
+		log.Fatalf("启动关注消费者池失败: %v\n", err)
+	}
+}
+
+func (c *FollowConsumer) Stop() {
+	c.consumerPool.Stop()
+}
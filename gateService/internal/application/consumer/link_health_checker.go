@@ -0,0 +1,264 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/pkg/linkprobe"
+	"gateService/pkg/logger"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// 检查周期,多个实例每个周期只有一个实例执行检查
+	linkCheckInterval = 10 * time.Minute
+	// 每轮检查的剧集数量上限,优先检查最近被观看的剧集,不足部分按顺序扫描缓存补齐
+	linkCheckBatchSize = 200
+	// 最近被观看剧集的统计窗口
+	linkCheckRecentWindow = 24 * time.Hour
+	// 每轮检查的最近被观看剧集数量上限
+	linkCheckRecentLimit = 100
+	// 探测的最大并发数
+	linkCheckConcurrency = 8
+	// 单次探测超时时间
+	linkProbeTimeout = 10 * time.Second
+	// 播放地址连续多少轮被确认失效后才移除,避免视频站短暂故障时误删
+	linkCheckFailThreshold = 3
+)
+
+// MessagePublisher 发布消息,由nsqpool.ProducerPool实现
+type MessagePublisher interface {
+	Publish(ctx context.Context, topic string, msg []byte) error
+}
+
+// LinkHealthChecker 定期探测缓存的播放地址是否可用
+// 连续多轮确认失效的播放源从Redis缓存和数据库保存的爬取结果中移除,一集的播放源全部失效时投递重新爬取任务,由VideoScrapeConsumer执行
+// 同时按来源站点累计探测结果,用于评估各来源站点的稳定性
+type LinkHealthChecker struct {
+	videoRepository    repository.VideoRepository
+	progressRepository repository.ProgressRepository
+	healthRepository   repository.LinkHealthRepository
+	producerPool       MessagePublisher
+	prober             *linkprobe.Prober
+	stop               chan struct{}
+}
+
+func NewLinkHealthChecker(videoRepository repository.VideoRepository, progressRepository repository.ProgressRepository, healthRepository repository.LinkHealthRepository, producerPool MessagePublisher) *LinkHealthChecker {
+	return &LinkHealthChecker{
+		videoRepository:    videoRepository,
+		progressRepository: progressRepository,
+		healthRepository:   healthRepository,
+		producerPool:       producerPool,
+		prober:             linkprobe.NewProber(linkProbeTimeout),
+		stop:               make(chan struct{}),
+	}
+}
+
+// probeTarget 表示一个待探测的播放源
+type probeTarget struct {
+	key    string
+	source *entity.VideoSource
+}
+
+// Check 执行一轮检查,其他实例已执行本轮检查时直接返回
+func (c *LinkHealthChecker) Check(ctx context.Context) error {
+	acquired, err := c.healthRepository.AcquireCheckRound(ctx, linkCheckInterval)
+	if err != nil || !acquired {
+		return err
+	}
+
+	keys, err := c.sampleKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	targets := make([]probeTarget, 0, len(keys))
+	for _, key := range keys {
+		sources, err := c.videoRepository.GetVideoSources(ctx, key)
+		if err != nil {
+			return fmt.Errorf("获取缓存播放源失败: %v", err)
+		}
+		for _, source := range sources {
+			targets = append(targets, probeTarget{key: key, source: source})
+		}
+	}
+
+	urls := make([]string, 0, len(targets))
+	for _, target := range targets {
+		urls = append(urls, target.source.URL)
+	}
+	results := c.prober.ProbeAll(ctx, urls, linkCheckConcurrency)
+
+	alive := make(map[string]int)
+	dead := make(map[string]int)
+	failed := make([]probeTarget, 0)
+	failedURLs := make([]string, 0)
+	recoveredURLs := make([]string, 0)
+	seen := make(map[string]bool, len(targets))
+	for i, result := range results {
+		// 本轮检查超时后,失败的探测可能是被中止导致的,不计入结果
+		if result.Err != nil && ctx.Err() != nil {
+			continue
+		}
+		target := targets[i]
+		origin := sourceOrigin(target.source)
+		if result.Alive() {
+			alive[origin]++
+			if !seen[target.source.URL] {
+				seen[target.source.URL] = true
+				recoveredURLs = append(recoveredURLs, target.source.URL)
+			}
+			continue
+		}
+		dead[origin]++
+		// 网络错误和401、403等无法确认地址失效,只计入来源站点的统计
+		if !result.Dead() {
+			continue
+		}
+		failed = append(failed, target)
+		if !seen[target.source.URL] {
+			seen[target.source.URL] = true
+			failedURLs = append(failedURLs, target.source.URL)
+		}
+	}
+
+	if err := c.healthRepository.RecordProbeResults(ctx, alive, dead); err != nil {
+		logger.Log.Warn("记录播放地址探测结果失败", zap.Error(err))
+	}
+
+	// 无法获取连续失败次数时本轮不移除播放源
+	streaks, err := c.healthRepository.UpdateFailureStreaks(ctx, failedURLs, recoveredURLs)
+	if err != nil {
+		logger.Log.Warn("更新播放地址连续失败次数失败", zap.Error(err))
+	}
+	deadURLs := make(map[string][]string)
+	for _, target := range failed {
+		if streaks[target.source.URL] >= linkCheckFailThreshold {
+			deadURLs[target.key] = append(deadURLs[target.key], target.source.URL)
+		}
+	}
+
+	rescraped := 0
+	for key, urls := range deadURLs {
+		remaining, err := c.videoRepository.RemoveVideoSources(ctx, key, urls)
+		if err != nil {
+			logger.Log.Warn("移除失效播放源失败", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		// 数据库中的爬取结果在Redis缓存过期后会被重新读取,需要同样移除
+		if videoID, episode, ok := entity.ParseVideoSourceKey(key); ok {
+			if err := c.videoRepository.RemoveStoredVideoSources(ctx, videoID, episode, urls); err != nil {
+				logger.Log.Warn("移除保存的失效播放源失败", zap.String("key", key), zap.Error(err))
+			}
+		}
+		if remaining > 0 {
+			continue
+		}
+		if c.publishRescrape(ctx, key) {
+			rescraped++
+		}
+	}
+
+	logger.Log.Info("播放地址健康检查完成",
+		zap.Int("episodes", len(keys)),
+		zap.Int("probed", len(results)),
+		zap.Int("deadEpisodes", len(deadURLs)),
+		zap.Int("rescraped", rescraped))
+	return nil
+}
+
+// sampleKeys 选取本轮检查的播放源缓存key,最近被观看的剧集排在前面
+func (c *LinkHealthChecker) sampleKeys(ctx context.Context) ([]string, error) {
+	episodes, err := c.progressRepository.ListRecentlyWatchedEpisodes(ctx, linkCheckRecentWindow, linkCheckRecentLimit)
+	if err != nil {
+		return nil, fmt.Errorf("获取最近观看的剧集失败: %v", err)
+	}
+
+	keys := make([]string, 0, linkCheckBatchSize)
+	seen := make(map[string]bool, linkCheckBatchSize)
+	for _, episode := range episodes {
+		key := entity.VideoSourceKey(episode.VideoID, episode.Episode)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	sampled, err := c.healthRepository.SampleVideoSourceKeys(ctx, linkCheckBatchSize-len(keys))
+	if err != nil {
+		return nil, fmt.Errorf("抽样播放源缓存失败: %v", err)
+	}
+	for _, key := range sampled {
+		if !seen[key] && len(keys) < linkCheckBatchSize {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// publishRescrape 投递重新爬取任务,投递失败时下一次播放会重新爬取,只记录日志
+func (c *LinkHealthChecker) publishRescrape(ctx context.Context, key string) bool {
+	videoID, episode, ok := entity.ParseVideoSourceKey(key)
+	if !ok {
+		return false
+	}
+
+	jobJson, err := json.Marshal(&entity.VideoScrapeJob{
+		VideoID: videoID,
+		Episode: episode,
+		Replace: true,
+		Reason:  "link_check",
+	})
+	if err == nil {
+		err = c.producerPool.Publish(ctx, videoScrapeTopic, jobJson)
+	}
+	if err != nil {
+		logger.Log.Warn("投递重新爬取任务失败", zap.String("key", key), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// sourceOrigin 返回播放源的来源站点,爬虫未返回来源时使用地址的域名
+func sourceOrigin(source *entity.VideoSource) string {
+	if source.Origin != "" {
+		return source.Origin
+	}
+	if u, err := url.Parse(source.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "unknown"
+}
+
+// checkLoop 定期执行检查,每轮检查最多执行半个周期
+func (c *LinkHealthChecker) checkLoop() {
+	ticker := time.NewTicker(linkCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), linkCheckInterval/2)
+			if err := c.Check(ctx); err != nil {
+				logger.Log.Error("播放地址健康检查失败", zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
+
+func (c *LinkHealthChecker) Start() {
+	go c.checkLoop()
+}
+
+func (c *LinkHealthChecker) Stop() {
+	close(c.stop)
+}
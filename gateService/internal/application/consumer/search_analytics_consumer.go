@@ -0,0 +1,101 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/pkg/logger"
+	"gateService/pkg/mq/nsqpool"
+	"log"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// 搜索衰减周期
+	searchDecayInterval = time.Hour
+	// 搜索热度半衰期
+	searchHalfLife = 24 * time.Hour
+)
+
+// SearchAnalyticsConsumer 负责汇总搜索行为消息,统计热门搜索词和无结果搜索词
+// 各实例共用同一通道,每条消息只被处理一次
+type SearchAnalyticsConsumer struct {
+	searchAnalyticsRepository repository.SearchAnalyticsRepository
+	consumerPool              *nsqpool.ConsumerPool
+	stop                      chan struct{}
+}
+
+func NewSearchAnalyticsConsumer(searchAnalyticsRepository repository.SearchAnalyticsRepository) *SearchAnalyticsConsumer {
+	return &SearchAnalyticsConsumer{
+		searchAnalyticsRepository: searchAnalyticsRepository,
+		stop:                      make(chan struct{}),
+	}
+}
+
+// recordSearch 记录一次搜索,无结果的搜索计入无结果统计
+// 搜索框的实时搜索会为同一次搜索的各个前缀各发布一条消息,只计入无结果统计,不计入热门搜索
+func (c *SearchAnalyticsConsumer) recordSearch(ctx context.Context, msg []byte) error {
+	var event entity.SearchQueryEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		return fmt.Errorf("解析搜索消息失败: %v", err)
+	}
+	if event.Query == "" {
+		return nil
+	}
+
+	zeroResult := event.ResultCount == 0
+	if event.Source == entity.SearchSourceBox && !zeroResult {
+		return nil
+	}
+
+	if err := c.searchAnalyticsRepository.RecordSearch(ctx, event.UserID, event.Query, zeroResult); err != nil {
+		return fmt.Errorf("记录搜索失败: %v", err)
+	}
+	return nil
+}
+
+// decayLoop 定期衰减搜索热度,各实例都会尝试执行,每个周期只有一个实例成功
+func (c *SearchAnalyticsConsumer) decayLoop() {
+	ticker := time.NewTicker(searchDecayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			if _, err := c.searchAnalyticsRepository.DecayScores(context.Background(), searchDecayInterval, searchHalfLife); err != nil {
+				logger.Log.Error("衰减搜索热度失败", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (c *SearchAnalyticsConsumer) Start() {
+	consumerPool, err := nsqpool.NewConsumerPool(&nsqpool.ConsumerOptions{
+		Topic:    "search_query_channel",
+		Channel:  "search_analytics",
+		PoolSize: 2,
+	})
+	if err != nil {
+		log.Fatalf("创建搜索统计消费者池失败: %v\n", err)
+	}
+	c.consumerPool = consumerPool
+
+	consumerPool.RegisterCallback(c.recordSearch)
+	err = consumerPool.Start()
+	if err != nil {
+		log.Fatalf("启动搜索统计消费者池失败: %v\n", err)
+	}
+
+	go c.decayLoop()
+}
+
+func (c *SearchAnalyticsConsumer) Stop() {
+	close(c.stop)
+	c.consumerPool.Stop()
+}
@@ -0,0 +1,195 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/search"
+	"gateService/pkg/logger"
+	"gateService/pkg/mq/nsqpool"
+	"log"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// 视频目录变更的消息队列主题
+	catalogChangeTopic = "catalog_change_channel"
+	// 全量构建索引时每次查询的视频数
+	searchIndexPageSize = 1000
+	// 定期全量重建索引的间隔,用于修复丢失的变更消息
+	searchIndexRebuildInterval = 30 * time.Minute
+	// 输入提示的刷新间隔,热度变化不需要实时反映
+	suggestRefreshInterval = 5 * time.Minute
+)
+
+// NSQ通道名只允许字母、数字、点、下划线和中划线
+var invalidChannelChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// SearchIndexConsumer 负责维护进程内的视频检索索引和输入提示
+// 启动时由MySQL全量构建检索索引,之后根据目录变更消息增量更新
+// 每个实例都维护自己的索引,因此使用以主机名区分的临时通道,保证每个实例都能收到全部变更
+// 输入提示依赖播放量等热度数据,不随目录变更更新,而是定期全量刷新
+type SearchIndexConsumer struct {
+	videoRepository repository.VideoRepository
+	videoIndex      *search.VideoIndex
+	suggestIndex    *search.SuggestIndex
+	consumerPool    *nsqpool.ConsumerPool
+
+	mu   sync.Mutex // 保证全量构建和增量更新串行执行,避免构建期间的变更被旧数据覆盖
+	stop chan struct{}
+}
+
+func NewSearchIndexConsumer(videoRepository repository.VideoRepository, videoIndex *search.VideoIndex, suggestIndex *search.SuggestIndex) *SearchIndexConsumer {
+	return &SearchIndexConsumer{
+		videoRepository: videoRepository,
+		videoIndex:      videoIndex,
+		suggestIndex:    suggestIndex,
+		stop:            make(chan struct{}),
+	}
+}
+
+// rebuild 分页读取全部视频并重建索引
+func (c *SearchIndexConsumer) rebuild(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs := make([]*entity.VideoSearchDocument, 0)
+	afterID := 0
+	for {
+		page, err := c.videoRepository.ListSearchDocuments(ctx, afterID, searchIndexPageSize)
+		if err != nil {
+			return fmt.Errorf("获取检索文档失败: %v", err)
+		}
+		docs = append(docs, page...)
+		if len(page) < searchIndexPageSize {
+			break
+		}
+		afterID = page[len(page)-1].VideoID
+	}
+
+	c.videoIndex.Rebuild(docs)
+	return nil
+}
+
+// refreshSuggestions 分页读取全部视频名称和热度并重建输入提示
+func (c *SearchIndexConsumer) refreshSuggestions(ctx context.Context) error {
+	videos := make([]*entity.VideoSuggestion, 0)
+	afterID := 0
+	for {
+		page, err := c.videoRepository.ListVideoSuggestions(ctx, afterID, searchIndexPageSize)
+		if err != nil {
+			return fmt.Errorf("获取输入提示数据失败: %v", err)
+		}
+		videos = append(videos, page...)
+		if len(page) < searchIndexPageSize {
+			break
+		}
+		afterID = page[len(page)-1].VideoID
+	}
+
+	c.suggestIndex.Rebuild(videos)
+	return nil
+}
+
+// updateIndex 重新加载变更的视频,已删除的视频从索引中移除
+func (c *SearchIndexConsumer) updateIndex(ctx context.Context, msg []byte) error {
+	var event entity.CatalogChangeEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		return fmt.Errorf("解析目录变更消息失败: %v", err)
+	}
+	if len(event.VideoIDs) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, err := c.videoRepository.GetSearchDocumentsByIDs(ctx, event.VideoIDs)
+	if err != nil {
+		return fmt.Errorf("获取检索文档失败: %v", err)
+	}
+	for _, videoID := range event.VideoIDs {
+		if doc, ok := docs[videoID]; ok {
+			c.videoIndex.Put(doc)
+		} else {
+			c.videoIndex.Delete(videoID)
+		}
+	}
+	return nil
+}
+
+// rebuildLoop 定期全量重建检索索引和输入提示
+func (c *SearchIndexConsumer) rebuildLoop() {
+	rebuildTicker := time.NewTicker(searchIndexRebuildInterval)
+	defer rebuildTicker.Stop()
+	suggestTicker := time.NewTicker(suggestRefreshInterval)
+	defer suggestTicker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-rebuildTicker.C:
+			if err := c.rebuild(context.Background()); err != nil {
+				logger.Log.Error("重建视频检索索引失败", zap.Error(err))
+			}
+		case <-suggestTicker.C:
+			if err := c.refreshSuggestions(context.Background()); err != nil {
+				logger.Log.Error("刷新输入提示失败", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (c *SearchIndexConsumer) Start() {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = fmt.Sprintf("pid%d", os.Getpid())
+	}
+	channel := "search_index_" + invalidChannelChars.ReplaceAllString(hostname, "_")
+	if len(channel) > 54 {
+		channel = channel[:54]
+	}
+
+	consumerPool, err := nsqpool.NewConsumerPool(&nsqpool.ConsumerOptions{
+		Topic:    catalogChangeTopic,
+		Channel:  channel + "#ephemeral",
+		PoolSize: 1,
+	})
+	if err != nil {
+		log.Fatalf("创建检索索引消费者池失败: %v\n", err)
+	}
+	c.consumerPool = consumerPool
+
+	consumerPool.RegisterCallback(c.updateIndex)
+	err = consumerPool.Start()
+	if err != nil {
+		log.Fatalf("启动检索索引消费者池失败: %v\n", err)
+	}
+
+	// 先订阅再构建,构建期间的变更消息在构建完成后处理
+	// 首次构建失败时搜索回退到数据库查询,等待下一次定期重建
+	start := time.Now()
+	if err := c.rebuild(context.Background()); err != nil {
+		logger.Log.Error("构建视频检索索引失败", zap.Error(err))
+	} else {
+		logger.Log.Info("视频检索索引构建完成", zap.Int("videos", c.videoIndex.Len()), zap.Duration("elapsed", time.Since(start)))
+	}
+	if err := c.refreshSuggestions(context.Background()); err != nil {
+		logger.Log.Error("构建输入提示失败", zap.Error(err))
+	}
+
+	go c.rebuildLoop()
+}
+
+func (c *SearchIndexConsumer) Stop() {
+	close(c.stop)
+	c.consumerPool.Stop()
+}
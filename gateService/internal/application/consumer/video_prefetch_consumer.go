@@ -0,0 +1,77 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/service"
+	"gateService/pkg/logger"
+	"gateService/pkg/mq/nsqpool"
+	"log"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 下一集预取任务的消息队列主题
+const videoPrefetchTopic = "video_prefetch_channel"
+
+// VideoPrefetchConsumer 执行播放时投递的下一集预取任务
+// 预取的优先级低于其他后台爬取任务: 使用单独的主题,每个实例只有一个消费者,且所有实例共享预取频率限制
+// 超过频率限制的任务延迟重试,重试次数用完后丢弃;爬取失败不重试,用户播放该集时会再次爬取
+type VideoPrefetchConsumer struct {
+	videoService service.VideoService
+	consumerPool *nsqpool.ConsumerPool
+}
+
+func NewVideoPrefetchConsumer(videoService service.VideoService) *VideoPrefetchConsumer {
+	return &VideoPrefetchConsumer{
+		videoService: videoService,
+	}
+}
+
+// prefetch 执行一个预取任务
+func (c *VideoPrefetchConsumer) prefetch(ctx context.Context, msg []byte) error {
+	var job entity.VideoScrapeJob
+	if err := json.Unmarshal(msg, &job); err != nil {
+		return fmt.Errorf("解析预取任务失败: %v", err)
+	}
+	if job.VideoID <= 0 || job.Episode == "" {
+		return nil
+	}
+
+	err := c.videoService.PrefetchVideoSources(ctx, job.VideoID, job.Episode)
+	if err == service.ErrVideoPrefetchLimited {
+		return err
+	}
+	if err != nil {
+		logger.Log.Warn("执行预取任务失败", zap.Int("videoID", job.VideoID), zap.String("episode", job.Episode), zap.Error(err))
+	}
+	return nil
+}
+
+func (c *VideoPrefetchConsumer) Start() {
+	consumerPool, err := nsqpool.NewConsumerPool(&nsqpool.ConsumerOptions{
+		Topic:        videoPrefetchTopic,
+		Channel:      "video_prefetch",
+		PoolSize:     1,
+		MaxInFlight:  1,
+		MaxAttempts:  5,
+		RequeueDelay: 20 * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("创建预取任务消费者池失败: %v\n", err)
+	}
+	c.consumerPool = consumerPool
+
+	consumerPool.RegisterCallback(c.prefetch)
+	err = consumerPool.Start()
+	if err != nil {
+		log.Fatalf("启动预取任务消费者池失败: %v\n", err)
+	}
+}
+
+func (c *VideoPrefetchConsumer) Stop() {
+	c.consumerPool.Stop()
+}
@@ -0,0 +1,70 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/service"
+	"gateService/pkg/mq/nsqpool"
+	"log"
+	"time"
+)
+
+// 后台爬取任务的消息队列主题
+const videoScrapeTopic = "video_scrape_channel"
+
+// VideoScrapeConsumer 执行消息队列中的后台爬取任务
+// 爬取在与用户请求相同的分布式锁内进行,不会与用户触发的爬取重复执行
+// 每个实例的并发数等于消费者数量,避免后台任务占满爬虫服务
+type VideoScrapeConsumer struct {
+	videoService service.VideoService
+	consumerPool *nsqpool.ConsumerPool
+}
+
+func NewVideoScrapeConsumer(videoService service.VideoService) *VideoScrapeConsumer {
+	return &VideoScrapeConsumer{
+		videoService: videoService,
+	}
+}
+
+// scrape 执行一个爬取任务
+func (c *VideoScrapeConsumer) scrape(ctx context.Context, msg []byte) error {
+	var job entity.VideoScrapeJob
+	if err := json.Unmarshal(msg, &job); err != nil {
+		return fmt.Errorf("解析爬取任务失败: %v", err)
+	}
+	if job.VideoID <= 0 || job.Episode == "" {
+		return nil
+	}
+
+	if err := c.videoService.RefreshVideoSources(ctx, job.VideoID, job.Episode, job.Replace); err != nil {
+		return fmt.Errorf("执行爬取任务失败: video=%d episode=%s reason=%s: %v", job.VideoID, job.Episode, job.Reason, err)
+	}
+	return nil
+}
+
+func (c *VideoScrapeConsumer) Start() {
+	consumerPool, err := nsqpool.NewConsumerPool(&nsqpool.ConsumerOptions{
+		Topic:        videoScrapeTopic,
+		Channel:      "video_scrape",
+		PoolSize:     2,
+		MaxInFlight:  1,
+		MaxAttempts:  3,
+		RequeueDelay: 30 * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("创建爬取任务消费者池失败: %v\n", err)
+	}
+	c.consumerPool = consumerPool
+
+	consumerPool.RegisterCallback(c.scrape)
+	err = consumerPool.Start()
+	if err != nil {
+		log.Fatalf("启动爬取任务消费者池失败: %v\n", err)
+	}
+}
+
+func (c *VideoScrapeConsumer) Stop() {
+	c.consumerPool.Stop()
+}
@@ -1,8 +1,73 @@
 package consumer
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/middleware/websocket"
+	"strconv"
+)
+
 type NotificationMessage struct {
 	MsgType      string `json:"type"`
 	SendUserName string `json:"send_username"`
 	Title        string `json:"title"`
 	Content      string `json:"content"`
 }
+
+// sendNotification 通过WebSocket将用户通知实时推送给接收者,接收者不在线时忽略
+func sendNotification(ctx context.Context, userRepository repository.UserRepository, websocketManager *websocket.Manager, notification *entity.UserNotification) error {
+	user, err := userRepository.GetUserByID(ctx, notification.FromUserID)
+	if err != nil {
+		return fmt.Errorf("获取对方用户信息失败: %v", err)
+	}
+
+	notificationMsg := &NotificationMessage{
+		SendUserName: user.Username,
+		Content:      notification.Content,
+	}
+
+	switch notification.NotificationType {
+	case 1:
+		notificationMsg.MsgType = "WS_COMMENT_LIKE"
+		notificationMsg.Title = user.Username + "点赞了你的评论"
+	case 2:
+		notificationMsg.MsgType = "WS_COMMENT_REPLY"
+		notificationMsg.Title = user.Username + "回复了你的评论"
+	case 3:
+		notificationMsg.MsgType = "WS_POST_FAVORITE"
+		notificationMsg.Title = user.Username + "收藏了你的帖子"
+	case 4:
+		notificationMsg.MsgType = "WS_POST_LIKE"
+		notificationMsg.Title = user.Username + "点赞了你的帖子"
+	case 5:
+		notificationMsg.MsgType = "WS_FOLLOW"
+		notificationMsg.Title = user.Username + "关注了你"
+	case 6:
+		notificationMsg.MsgType = "WS_SYSTEM"
+		notificationMsg.Title = "系统消息"
+	}
+
+	notificationMsgJson, err := json.Marshal(notificationMsg)
+	if err != nil {
+		return fmt.Errorf("序列化通知消息失败: %v", err)
+	}
+
+	return websocketManager.SendMessage(strconv.Itoa(notification.UserID), notificationMsgJson)
+}
+
+// notificationSuppressed 判断接收者是否拉黑或静音了发送者,被屏蔽的发送者不再产生通知
+func notificationSuppressed(ctx context.Context, blockRepository repository.BlockRepository, userID, fromUserID int) (bool, error) {
+	for _, blockType := range []int8{entity.BlockTypeBlock, entity.BlockTypeMute} {
+		blocked, err := blockRepository.IsBlocked(ctx, userID, fromUserID, blockType)
+		if err != nil {
+			return false, fmt.Errorf("获取屏蔽状态失败: %v", err)
+		}
+		if blocked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/config"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/logger"
+	"gateService/pkg/mq/nsqpool"
+	"gateService/pkg/password"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 用户数据导出任务的消息队列主题
+const userExportTopic = "user_export_queue"
+
+type AccountServiceImpl struct {
+	exportConfig          *config.ExportConfig
+	userRepository        repository.UserRepository
+	tokenRepository       repository.TokenRepository
+	mfaRepository         repository.MfaRepository
+	accountDataRepository repository.AccountDataRepository
+	jwtManager            *auth.JWTManager
+	cookieManager         *auth.CookieManager
+	passwordHasher        *password.Hasher
+	producerPool          *nsqpool.ProducerPool
+}
+
+func NewAccountServiceImpl(
+	exportConfig *config.ExportConfig,
+	userRepository repository.UserRepository,
+	tokenRepository repository.TokenRepository,
+	mfaRepository repository.MfaRepository,
+	accountDataRepository repository.AccountDataRepository,
+	jwtManager *auth.JWTManager,
+	cookieManager *auth.CookieManager,
+	passwordHasher *password.Hasher,
+	producerPool *nsqpool.ProducerPool,
+) *AccountServiceImpl {
+	return &AccountServiceImpl{
+		exportConfig:          exportConfig,
+		userRepository:        userRepository,
+		tokenRepository:       tokenRepository,
+		mfaRepository:         mfaRepository,
+		accountDataRepository: accountDataRepository,
+		jwtManager:            jwtManager,
+		cookieManager:         cookieManager,
+		passwordHasher:        passwordHasher,
+		producerPool:          producerPool,
+	}
+}
+
+func (s *AccountServiceImpl) RequestDataExport(ctx context.Context, request *dto.RequestDataExportRequest) (*dto.RequestDataExportResponse, error) {
+	// 同一用户在任务保留期内只保留一个有效的导出任务,失败的任务允许重新发起
+	latest, err := s.accountDataRepository.GetLatestExportJob(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取导出任务失败: %v", err)
+	}
+	if latest != nil && latest.Status != entity.DataExportFailed {
+		return &dto.RequestDataExportResponse{
+			Code:    200,
+			Message: "已有导出任务,请勿重复发起",
+			Job:     latest,
+		}, nil
+	}
+
+	job := &entity.DataExportJob{
+		JobID:     uuid.New().String(),
+		UserID:    request.UserID,
+		Status:    entity.DataExportPending,
+		CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
+	}
+	if err := s.accountDataRepository.SaveExportJob(ctx, job, s.exportConfig.TTL); err != nil {
+		return nil, fmt.Errorf("创建导出任务失败: %v", err)
+	}
+
+	if err := s.producerPool.Publish(ctx, userExportTopic, []byte(job.JobID)); err != nil {
+		job.Status = entity.DataExportFailed
+		job.Error = "提交导出任务失败"
+		if saveErr := s.accountDataRepository.SaveExportJob(ctx, job, s.exportConfig.TTL); saveErr != nil {
+			logger.Log.Warn("更新导出任务状态失败", zap.String("jobID", job.JobID), zap.Error(saveErr))
+		}
+		return nil, fmt.Errorf("提交导出任务失败: %v", err)
+	}
+
+	return &dto.RequestDataExportResponse{
+		Code:    200,
+		Message: "导出任务已提交,完成后可下载",
+		Job:     job,
+	}, nil
+}
+
+func (s *AccountServiceImpl) GetDataExport(ctx context.Context, request *dto.GetDataExportRequest) (*dto.GetDataExportResponse, error) {
+	job, err := s.getUserExportJob(ctx, request.UserID, request.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.GetDataExportResponse{
+		Code: 200,
+		Job:  job,
+	}, nil
+}
+
+func (s *AccountServiceImpl) DownloadDataExport(ctx context.Context, request *dto.DownloadDataExportRequest) (*dto.DownloadDataExportResponse, error) {
+	job, err := s.getUserExportJob(ctx, request.UserID, request.JobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != entity.DataExportDone || job.FileName == "" {
+		return nil, errors.New("导出任务尚未完成")
+	}
+
+	filePath := filepath.Join(s.exportConfig.Path, job.FileName)
+	if _, err := os.Stat(filePath); err != nil {
+		return nil, errors.New("导出文件不存在或已过期")
+	}
+
+	return &dto.DownloadDataExportResponse{
+		FilePath: filePath,
+		FileName: fmt.Sprintf("zanime-export-%d-%s.zip", job.UserID, time.Now().Format("20060102")),
+	}, nil
+}
+
+// getUserExportJob 获取属于指定用户的导出任务,任务不存在或不属于该用户时返回相同错误
+func (s *AccountServiceImpl) getUserExportJob(ctx context.Context, userID int, jobID string) (*entity.DataExportJob, error) {
+	job, err := s.accountDataRepository.GetExportJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("获取导出任务失败: %v", err)
+	}
+	if job == nil || job.UserID != userID {
+		return nil, errors.New("导出任务不存在或已过期")
+	}
+	return job, nil
+}
+
+func (s *AccountServiceImpl) DeleteAccount(ctx context.Context, request *dto.DeleteAccountRequest) (*dto.DeleteAccountResponse, error) {
+	userInfo, err := s.userRepository.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	credential, err := s.userRepository.GetUserCredentialByEmail(ctx, userInfo.Email)
+	if err != nil {
+		return nil, fmt.Errorf("验证用户失败: %v", err)
+	}
+	if credential == nil {
+		return nil, errors.New("用户不存在")
+	}
+	ok, err := s.passwordHasher.Verify(credential.Password, request.Password)
+	if err != nil {
+		return nil, fmt.Errorf("验证用户失败: %v", err)
+	}
+	if !ok {
+		return nil, errors.New("密码错误")
+	}
+
+	// 开启两步验证的用户需额外校验动态验证码或恢复码
+	mfa, err := s.mfaRepository.GetUserMfa(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取两步验证状态失败: %v", err)
+	}
+	if mfa != nil && mfa.Enabled {
+		if request.Code == "" {
+			return nil, errors.New("请输入两步验证码")
+		}
+		ok, err := verifyMfaCode(ctx, s.mfaRepository, mfa, request.Code)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("验证码错误")
+		}
+	}
+
+	// 导出任务与文件在注销后不再允许下载
+	latest, err := s.accountDataRepository.GetLatestExportJob(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取导出任务失败: %v", err)
+	}
+
+	if err := s.accountDataRepository.DeleteUserData(ctx, request.UserID); err != nil {
+		return nil, fmt.Errorf("注销账号失败: %v", err)
+	}
+
+	// 吊销全部令牌及登录会话,个人访问令牌已随用户数据删除
+	if err := s.tokenRepository.RevokeUserTokens(ctx, request.UserID, time.Now(), s.jwtManager.GetRefreshTokenExpireTime()); err != nil {
+		return nil, fmt.Errorf("吊销用户令牌失败: %v", err)
+	}
+
+	// 角色缓存过期前已注销的用户仍会被识别为原角色
+	if err := s.userRepository.DeleteUserRoleCache(ctx, request.UserID); err != nil {
+		return nil, fmt.Errorf("删除用户角色缓存失败: %v", err)
+	}
+
+	if latest != nil && latest.FileName != "" {
+		if err := os.Remove(filepath.Join(s.exportConfig.Path, latest.FileName)); err != nil && !os.IsNotExist(err) {
+			logger.Log.Warn("删除导出文件失败", zap.Int("userID", request.UserID), zap.Error(err))
+		}
+	}
+
+	if c, ok := ctx.(*gin.Context); ok {
+		s.cookieManager.ClearTokenCookie(c)
+		s.cookieManager.ClearRefreshTokenCookie(c)
+	}
+
+	return &dto.DeleteAccountResponse{
+		Code:    200,
+		Message: "账号已注销",
+	}, nil
+}
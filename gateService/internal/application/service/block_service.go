@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/interfaces/dto"
+)
+
+// 拉黑/静音列表默认每页数量
+const defaultBlockPageSize = 20
+
+type BlockServiceImpl struct {
+	blockRepository  repository.BlockRepository
+	followRepository repository.FollowRepository
+	userRepository   repository.UserRepository
+}
+
+func NewBlockServiceImpl(blockRepository repository.BlockRepository, followRepository repository.FollowRepository, userRepository repository.UserRepository) *BlockServiceImpl {
+	return &BlockServiceImpl{
+		blockRepository:  blockRepository,
+		followRepository: followRepository,
+		userRepository:   userRepository,
+	}
+}
+
+func (s *BlockServiceImpl) Block(ctx context.Context, request *dto.BlockRequest) (*dto.BlockResponse, error) {
+	if err := s.checkTarget(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.blockRepository.AddBlock(ctx, request.UserID, request.TargetUserID, entity.BlockTypeBlock); err != nil {
+		return nil, fmt.Errorf("拉黑失败: %v", err)
+	}
+
+	// 拉黑后解除双方的关注关系
+	if _, err := s.followRepository.DeleteFollow(ctx, request.UserID, request.TargetUserID); err != nil {
+		return nil, fmt.Errorf("解除关注关系失败: %v", err)
+	}
+	if _, err := s.followRepository.DeleteFollow(ctx, request.TargetUserID, request.UserID); err != nil {
+		return nil, fmt.Errorf("解除关注关系失败: %v", err)
+	}
+
+	return &dto.BlockResponse{
+		Code:    200,
+		Message: "已拉黑",
+	}, nil
+}
+
+func (s *BlockServiceImpl) Unblock(ctx context.Context, request *dto.BlockRequest) (*dto.BlockResponse, error) {
+	if _, err := s.blockRepository.RemoveBlock(ctx, request.UserID, request.TargetUserID, entity.BlockTypeBlock); err != nil {
+		return nil, fmt.Errorf("取消拉黑失败: %v", err)
+	}
+
+	return &dto.BlockResponse{
+		Code:    200,
+		Message: "已取消拉黑",
+	}, nil
+}
+
+func (s *BlockServiceImpl) Mute(ctx context.Context, request *dto.BlockRequest) (*dto.BlockResponse, error) {
+	if err := s.checkTarget(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.blockRepository.AddBlock(ctx, request.UserID, request.TargetUserID, entity.BlockTypeMute); err != nil {
+		return nil, fmt.Errorf("静音失败: %v", err)
+	}
+
+	return &dto.BlockResponse{
+		Code:    200,
+		Message: "已静音",
+	}, nil
+}
+
+func (s *BlockServiceImpl) Unmute(ctx context.Context, request *dto.BlockRequest) (*dto.BlockResponse, error) {
+	if _, err := s.blockRepository.RemoveBlock(ctx, request.UserID, request.TargetUserID, entity.BlockTypeMute); err != nil {
+		return nil, fmt.Errorf("取消静音失败: %v", err)
+	}
+
+	return &dto.BlockResponse{
+		Code:    200,
+		Message: "已取消静音",
+	}, nil
+}
+
+func (s *BlockServiceImpl) GetBlockedUsers(ctx context.Context, request *dto.BlockListRequest) (*dto.BlockListResponse, error) {
+	users, err := s.blockRepository.ListBlockedUsers(ctx, request.UserID, entity.BlockTypeBlock, request.Page, blockListPageSize(request))
+	if err != nil {
+		return nil, fmt.Errorf("获取拉黑列表失败: %v", err)
+	}
+
+	return &dto.BlockListResponse{
+		Code:  200,
+		Users: users,
+	}, nil
+}
+
+func (s *BlockServiceImpl) GetMutedUsers(ctx context.Context, request *dto.BlockListRequest) (*dto.BlockListResponse, error) {
+	users, err := s.blockRepository.ListBlockedUsers(ctx, request.UserID, entity.BlockTypeMute, request.Page, blockListPageSize(request))
+	if err != nil {
+		return nil, fmt.Errorf("获取静音列表失败: %v", err)
+	}
+
+	return &dto.BlockListResponse{
+		Code:  200,
+		Users: users,
+	}, nil
+}
+
+// checkTarget 校验屏蔽的目标用户,不能屏蔽自己或不存在的用户
+func (s *BlockServiceImpl) checkTarget(ctx context.Context, request *dto.BlockRequest) error {
+	if request.UserID == request.TargetUserID {
+		return errors.New("不能屏蔽自己")
+	}
+
+	target, err := s.userRepository.GetUserByID(ctx, request.TargetUserID)
+	if err != nil || target.Status != 1 {
+		return errors.New("用户不存在")
+	}
+	return nil
+}
+
+// blockListPageSize 未指定每页数量时使用默认值
+func blockListPageSize(request *dto.BlockListRequest) int {
+	if request.PageSize == 0 {
+		return defaultBlockPageSize
+	}
+	return request.PageSize
+}
@@ -0,0 +1,567 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/interfaces/dto"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// 上传导入文件的大小上限
+	catalogImportMaxSize = 20 << 20
+	// 每个事务写入的记录数
+	catalogImportBatchSize = 100
+	// 批量查询已有视频时每次查询的记录数
+	catalogLookupBatchSize = 500
+	// 导出时每次查询的记录数
+	catalogExportPageSize = 1000
+	// CSV中类型列表的分隔符
+	catalogGenreSeparator = "|"
+)
+
+// catalogCSVColumns CSV导入导出的列,导入时video_id、description和genres可省略
+// 省略genres或其值为空时不修改已有视频的类型,新建视频时必须提供类型
+var catalogCSVColumns = []string{"video_id", "video_name", "release_date", "area", "description", "cover_image_url", "genres"}
+
+// catalogRow 导入文件中的一条记录及其解析结果
+type catalogRow struct {
+	row    int
+	record *entity.CatalogRecord
+	err    error
+}
+
+func (s *CatalogServiceImpl) ImportCatalog(ctx context.Context, request *dto.CatalogImportRequest) (*dto.CatalogImportResponse, error) {
+	reader, fileName, err := openCatalogImportFile(request)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	format, err := catalogFormat(request.Format, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*catalogRow
+	if format == "csv" {
+		rows, err = parseCatalogCSV(reader)
+	} else {
+		rows, err = parseCatalogJSON(reader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析导入文件失败: %v", err)
+	}
+
+	report, changes, err := s.diffCatalog(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = request.DryRun
+
+	response := &dto.CatalogImportResponse{
+		Code:   200,
+		Report: report,
+	}
+	switch {
+	case request.DryRun:
+		response.Message = "试运行完成,未写入任何变更"
+		return response, nil
+	case report.Invalid > 0 || report.Conflicts > 0:
+		response.Message = "存在无效或冲突的记录,未写入任何变更"
+		return response, nil
+	case len(changes) == 0:
+		response.Message = "没有需要写入的变更"
+		return response, nil
+	}
+
+	if request.UserID <= 0 {
+		return nil, errors.New("新建视频需要指定上传者")
+	}
+	if err := s.applyCatalogChanges(ctx, changes, request.UserID); err != nil {
+		return nil, err
+	}
+
+	// 回填新建视频的ID
+	for _, item := range report.Items {
+		if change, ok := changes[item.Row]; ok {
+			item.VideoID = change.record.VideoID
+		}
+	}
+	report.Applied = true
+	response.Message = "导入完成"
+	return response, nil
+}
+
+func (s *CatalogServiceImpl) ExportCatalog(ctx context.Context, request *dto.CatalogExportRequest, writer io.Writer) error {
+	format := request.Format
+	if format == "" {
+		format = "json"
+	}
+
+	var csvWriter *csv.Writer
+	if format == "csv" {
+		csvWriter = csv.NewWriter(writer)
+		if err := csvWriter.Write(catalogCSVColumns); err != nil {
+			return fmt.Errorf("写入导出文件失败: %v", err)
+		}
+	} else if _, err := io.WriteString(writer, "["); err != nil {
+		return fmt.Errorf("写入导出文件失败: %v", err)
+	}
+
+	afterID, count := 0, 0
+	for {
+		records, err := s.videoRepository.ListCatalogRecords(ctx, afterID, catalogExportPageSize)
+		if err != nil {
+			return fmt.Errorf("获取视频目录失败: %v", err)
+		}
+
+		for _, record := range records {
+			if csvWriter != nil {
+				err = csvWriter.Write([]string{
+					strconv.Itoa(record.VideoID), record.Name, record.ReleaseDate, record.Area,
+					record.Description, record.CoverImageUrl, strings.Join(record.Genres, catalogGenreSeparator),
+				})
+			} else {
+				err = writeCatalogJSONRecord(writer, record, count == 0)
+			}
+			if err != nil {
+				return fmt.Errorf("写入导出文件失败: %v", err)
+			}
+			count++
+		}
+
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return fmt.Errorf("写入导出文件失败: %v", err)
+			}
+		}
+		if len(records) < catalogExportPageSize {
+			break
+		}
+		afterID = records[len(records)-1].VideoID
+	}
+
+	if csvWriter == nil {
+		if _, err := io.WriteString(writer, "\n]\n"); err != nil {
+			return fmt.Errorf("写入导出文件失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// catalogChange 一条需要写入的记录及其在报告中的位置
+type catalogChange struct {
+	record   *entity.CatalogRecord
+	relocate bool // 名称、上映时间或地区发生变化,需要清除该视频的播放地址缓存
+}
+
+// diffCatalog 校验导入记录并与已有视频比较,生成差异报告和需要写入的变更
+// 返回的变更按记录位置索引
+func (s *CatalogServiceImpl) diffCatalog(ctx context.Context, rows []*catalogRow) (*entity.CatalogImportReport, map[int]*catalogChange, error) {
+	report := &entity.CatalogImportReport{
+		Total: len(rows),
+		Items: make([]*entity.CatalogImportItem, 0),
+	}
+	changes := make(map[int]*catalogChange)
+
+	addItem := func(row *catalogRow, action string, videoID int, message string) *entity.CatalogImportItem {
+		item := &entity.CatalogImportItem{
+			Row:     row.row,
+			Action:  action,
+			VideoID: videoID,
+			Message: message,
+		}
+		if row.record != nil {
+			item.VideoName = row.record.Name
+		}
+		switch action {
+		case entity.CatalogActionInvalid:
+			report.Invalid++
+		case entity.CatalogActionConflict:
+			report.Conflicts++
+		case entity.CatalogActionCreate:
+			report.Created++
+		case entity.CatalogActionUpdate:
+			report.Updated++
+		}
+		report.Items = append(report.Items, item)
+		return item
+	}
+
+	// 1. 校验记录并检查文件内部的重复
+	valid := make([]*catalogRow, 0, len(rows))
+	seenIdentity := make(map[string]int)
+	seenID := make(map[int]int)
+	for _, row := range rows {
+		if row.err == nil {
+			row.err = normalizeCatalogRecord(row.record)
+		}
+		if row.err != nil {
+			addItem(row, entity.CatalogActionInvalid, 0, row.err.Error())
+			continue
+		}
+
+		key := row.record.IdentityKey()
+		if first, ok := seenIdentity[key]; ok {
+			addItem(row, entity.CatalogActionConflict, row.record.VideoID, fmt.Sprintf("与第%d条记录的名称、上映时间和地区相同", first))
+			continue
+		}
+		if row.record.VideoID != 0 {
+			if first, ok := seenID[row.record.VideoID]; ok {
+				addItem(row, entity.CatalogActionConflict, row.record.VideoID, fmt.Sprintf("与第%d条记录的视频ID相同", first))
+				continue
+			}
+			seenID[row.record.VideoID] = row.row
+		}
+		seenIdentity[key] = row.row
+		valid = append(valid, row)
+	}
+
+	// 2. 分批查询已有视频并比较
+	for start := 0; start < len(valid); start += catalogLookupBatchSize {
+		end := start + catalogLookupBatchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[start:end]
+
+		records := make([]*entity.CatalogRecord, 0, len(batch))
+		videoIDs := make([]int, 0)
+		for _, row := range batch {
+			records = append(records, row.record)
+			if row.record.VideoID != 0 {
+				videoIDs = append(videoIDs, row.record.VideoID)
+			}
+		}
+
+		byIdentity, err := s.videoRepository.GetCatalogRecordsByIdentities(ctx, records)
+		if err != nil {
+			return nil, nil, fmt.Errorf("查询已有视频失败: %v", err)
+		}
+		byID, err := s.videoRepository.GetCatalogRecordsByIDs(ctx, videoIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("查询已有视频失败: %v", err)
+		}
+
+		for _, row := range batch {
+			record := row.record
+			matched := byIdentity[record.IdentityKey()]
+
+			var existing *entity.CatalogRecord
+			if record.VideoID != 0 {
+				existing = byID[record.VideoID]
+				if existing == nil {
+					addItem(row, entity.CatalogActionInvalid, record.VideoID, "视频ID不存在")
+					continue
+				}
+				if matched != nil && matched.VideoID != record.VideoID {
+					addItem(row, entity.CatalogActionConflict, record.VideoID, fmt.Sprintf("与视频%d的名称、上映时间和地区相同", matched.VideoID))
+					continue
+				}
+			} else {
+				existing = matched
+			}
+
+			if existing == nil {
+				if record.Genres == nil {
+					addItem(row, entity.CatalogActionInvalid, 0, "新建视频至少需要一个类型")
+					continue
+				}
+				addItem(row, entity.CatalogActionCreate, 0, "")
+				changes[row.row] = &catalogChange{record: record}
+				continue
+			}
+
+			record.VideoID = existing.VideoID
+			diff := diffCatalogRecord(existing, record)
+			if len(diff) == 0 {
+				report.Unchanged++
+				continue
+			}
+			item := addItem(row, entity.CatalogActionUpdate, record.VideoID, "")
+			item.Changes = diff
+			changes[row.row] = &catalogChange{
+				record:   record,
+				relocate: existing.IdentityKey() != record.IdentityKey(),
+			}
+		}
+	}
+
+	return report, changes, nil
+}
+
+// applyCatalogChanges 按文件顺序分批写入变更,每批一个事务,写入后清除目录派生的缓存
+func (s *CatalogServiceImpl) applyCatalogChanges(ctx context.Context, changes map[int]*catalogChange, uploaderID int) error {
+	positions := make([]int, 0, len(changes))
+	for row := range changes {
+		positions = append(positions, row)
+	}
+	sort.Ints(positions)
+
+	applied := 0
+	var applyErr error
+	for start := 0; start < len(positions); start += catalogImportBatchSize {
+		end := start + catalogImportBatchSize
+		if end > len(positions) {
+			end = len(positions)
+		}
+
+		records := make([]*entity.CatalogRecord, 0, end-start)
+		for _, row := range positions[start:end] {
+			records = append(records, changes[row].record)
+		}
+		if err := s.videoRepository.SaveCatalogRecords(ctx, records, uploaderID); err != nil {
+			applyErr = fmt.Errorf("已写入%d条记录,第%d批写入失败: %v", applied, start/catalogImportBatchSize+1, err)
+			break
+		}
+		applied += len(records)
+	}
+
+	// 已提交的批次同样需要清除缓存并更新检索索引
+	s.invalidateCache(ctx, 0)
+	videoIDs := make([]int, 0, applied)
+	for _, row := range positions[:applied] {
+		change := changes[row]
+		if change.relocate {
+			s.invalidateCache(ctx, change.record.VideoID)
+		}
+		videoIDs = append(videoIDs, change.record.VideoID)
+	}
+	s.publishCatalogChange(ctx, videoIDs)
+	return applyErr
+}
+
+// openCatalogImportFile 打开上传的文件或命令行指定的本地文件
+func openCatalogImportFile(request *dto.CatalogImportRequest) (io.ReadCloser, string, error) {
+	if request.File != nil {
+		if request.File.Size > catalogImportMaxSize {
+			return nil, "", fmt.Errorf("导入文件不能超过%dMB", catalogImportMaxSize>>20)
+		}
+		file, err := request.File.Open()
+		if err != nil {
+			return nil, "", fmt.Errorf("打开导入文件失败: %v", err)
+		}
+		return file, request.File.Filename, nil
+	}
+
+	if request.FilePath != "" {
+		file, err := os.Open(request.FilePath)
+		if err != nil {
+			return nil, "", fmt.Errorf("打开导入文件失败: %v", err)
+		}
+		return file, request.FilePath, nil
+	}
+
+	return nil, "", errors.New("请上传导入文件")
+}
+
+// catalogFormat 未指定格式时根据文件扩展名判断
+func catalogFormat(format, fileName string) (string, error) {
+	if format != "" {
+		return format, nil
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return "csv", nil
+	case ".json":
+		return "json", nil
+	}
+	return "", errors.New("无法识别导入文件格式,请指定csv或json")
+}
+
+// parseCatalogCSV 解析CSV导入文件,首行为列名
+func parseCatalogCSV(reader io.Reader) ([]*catalogRow, error) {
+	csvReader := csv.NewReader(reader)
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("读取列名失败: %v", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		known := false
+		for _, column := range catalogCSVColumns {
+			if name == column {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("未知的列: %s", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("重复的列: %s", name)
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"video_name", "release_date", "area", "cover_image_url"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("缺少必需的列: %s", name)
+		}
+	}
+
+	field := func(values []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return values[i]
+		}
+		return ""
+	}
+
+	rows := make([]*catalogRow, 0)
+	for {
+		values, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := csvReader.FieldPos(0)
+
+		row := &catalogRow{
+			row: line,
+			record: &entity.CatalogRecord{
+				Name:          field(values, "video_name"),
+				ReleaseDate:   field(values, "release_date"),
+				Area:          field(values, "area"),
+				Description:   field(values, "description"),
+				CoverImageUrl: field(values, "cover_image_url"),
+			},
+		}
+		if genres := strings.TrimSpace(field(values, "genres")); genres != "" {
+			row.record.Genres = strings.Split(genres, catalogGenreSeparator)
+		}
+		if videoID := strings.TrimSpace(field(values, "video_id")); videoID != "" {
+			row.record.VideoID, err = strconv.Atoi(videoID)
+			if err != nil || row.record.VideoID <= 0 {
+				row.err = fmt.Errorf("视频ID格式错误: %s", videoID)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseCatalogJSON 解析JSON导入文件,文件内容为记录数组
+func parseCatalogJSON(reader io.Reader) ([]*catalogRow, error) {
+	var records []*entity.CatalogRecord
+	if err := json.NewDecoder(reader).Decode(&records); err != nil {
+		return nil, err
+	}
+
+	rows := make([]*catalogRow, 0, len(records))
+	for i, record := range records {
+		row := &catalogRow{row: i + 1, record: record}
+		if record == nil {
+			row.err = errors.New("记录不能为空")
+		} else if record.VideoID < 0 {
+			row.err = fmt.Errorf("视频ID格式错误: %d", record.VideoID)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// normalizeCatalogRecord 去除首尾空白、去重类型并按表结构校验长度
+// 类型列表为nil表示未提供类型,更新时保留已有类型;提供时至少需要一个类型
+func normalizeCatalogRecord(record *entity.CatalogRecord) error {
+	record.Name = strings.TrimSpace(record.Name)
+	record.ReleaseDate = strings.TrimSpace(record.ReleaseDate)
+	record.Area = strings.TrimSpace(record.Area)
+	record.Description = strings.TrimSpace(record.Description)
+	record.CoverImageUrl = strings.TrimSpace(record.CoverImageUrl)
+
+	if record.Name == "" || record.ReleaseDate == "" || record.Area == "" || record.CoverImageUrl == "" {
+		return errors.New("视频名称、上映时间、地区和封面不能为空")
+	}
+	for field, value := range map[string]string{"视频名称": record.Name, "上映时间": record.ReleaseDate, "地区": record.Area, "封面": record.CoverImageUrl} {
+		if utf8.RuneCountInString(value) > 255 {
+			return fmt.Errorf("%s不能超过255个字符", field)
+		}
+	}
+	if utf8.RuneCountInString(record.Description) > 5000 {
+		return errors.New("简介不能超过5000个字符")
+	}
+
+	if record.Genres == nil {
+		return nil
+	}
+	genres, err := normalizeGenres(record.Genres)
+	if err != nil {
+		return err
+	}
+	if len(genres) == 0 {
+		return errors.New("类型列表不能为空,不修改类型时请省略该字段")
+	}
+	if len(genres) > 20 {
+		return errors.New("类型不能超过20个")
+	}
+	for _, genre := range genres {
+		if utf8.RuneCountInString(genre) > 50 {
+			return fmt.Errorf("类型不能超过50个字符: %s", genre)
+		}
+		if strings.Contains(genre, catalogGenreSeparator) {
+			return fmt.Errorf("类型不能包含%s: %s", catalogGenreSeparator, genre)
+		}
+	}
+	record.Genres = genres
+	return nil
+}
+
+// diffCatalogRecord 比较已有记录和导入记录,返回发生变化的字段
+// 导入记录未提供类型时不比较类型
+func diffCatalogRecord(existing, record *entity.CatalogRecord) []*entity.CatalogFieldChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"video_name", existing.Name, record.Name},
+		{"release_date", existing.ReleaseDate, record.ReleaseDate},
+		{"area", existing.Area, record.Area},
+		{"description", existing.Description, record.Description},
+		{"cover_image_url", existing.CoverImageUrl, record.CoverImageUrl},
+		{"genres", strings.Join(existing.Genres, catalogGenreSeparator), strings.Join(record.Genres, catalogGenreSeparator)},
+	}
+
+	changes := make([]*entity.CatalogFieldChange, 0)
+	for _, field := range fields {
+		if field.name == "genres" && record.Genres == nil {
+			continue
+		}
+		if field.old != field.new {
+			changes = append(changes, &entity.CatalogFieldChange{Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+	return changes
+}
+
+// writeCatalogJSONRecord 以数组元素的形式写出一条记录,每条记录占一行
+func writeCatalogJSONRecord(writer io.Writer, record *entity.CatalogRecord, first bool) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if first {
+		separator = "\n"
+	}
+	if _, err := io.WriteString(writer, separator); err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
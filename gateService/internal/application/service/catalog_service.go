@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/logger"
+	"gateService/pkg/mq/nsqpool"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// 视频目录变更的消息队列主题,各实例据此更新检索索引
+	catalogChangeTopic = "catalog_change_channel"
+	// 每条目录变更消息包含的最大视频数
+	catalogChangeBatchSize = 500
+)
+
+type CatalogServiceImpl struct {
+	videoRepository repository.VideoRepository
+	producerPool    *nsqpool.ProducerPool
+}
+
+func NewCatalogServiceImpl(videoRepository repository.VideoRepository, producerPool *nsqpool.ProducerPool) *CatalogServiceImpl {
+	return &CatalogServiceImpl{
+		videoRepository: videoRepository,
+		producerPool:    producerPool,
+	}
+}
+
+func (s *CatalogServiceImpl) ListVideos(ctx context.Context, request *dto.CatalogVideoListRequest) (*dto.CatalogVideoListResponse, error) {
+	videos, err := s.videoRepository.GetVideosALLEpisodesByVideoName(ctx, strings.TrimSpace(request.Keyword), request.Page)
+	if err != nil {
+		return nil, fmt.Errorf("获取视频列表失败: %v", err)
+	}
+	if videos == nil {
+		videos = make([]*entity.Video, 0)
+	}
+
+	return &dto.CatalogVideoListResponse{
+		Code:   200,
+		Videos: videos,
+	}, nil
+}
+
+func (s *CatalogServiceImpl) GetVideoDetail(ctx context.Context, request *dto.CatalogVideoRequest) (*dto.CatalogVideoDetailResponse, error) {
+	video, err := s.getVideo(ctx, request.VideoID)
+	if err != nil {
+		return nil, err
+	}
+
+	genres, err := s.videoRepository.GetAnimeGenres(ctx, request.VideoID)
+	if err != nil {
+		return nil, fmt.Errorf("获取视频类型失败: %v", err)
+	}
+	if genres == nil {
+		genres = make([]string, 0)
+	}
+
+	aliases, err := s.videoRepository.GetAnimeAliases(ctx, request.VideoID)
+	if err != nil {
+		return nil, fmt.Errorf("获取视频别名失败: %v", err)
+	}
+
+	episodes, err := s.videoRepository.GetVideoEpisodes(ctx, request.VideoID)
+	if err != nil {
+		return nil, fmt.Errorf("获取剧集列表失败: %v", err)
+	}
+
+	return &dto.CatalogVideoDetailResponse{
+		Code:     200,
+		Video:    video,
+		Genres:   genres,
+		Aliases:  aliases,
+		Episodes: episodes,
+	}, nil
+}
+
+func (s *CatalogServiceImpl) CreateVideo(ctx context.Context, request *dto.CatalogVideoSaveRequest) (*dto.CatalogVideoSaveResponse, error) {
+	video, err := buildCatalogVideo(request)
+	if err != nil {
+		return nil, err
+	}
+	genres, err := normalizeGenres(request.Genres)
+	if err != nil {
+		return nil, err
+	}
+	// 观看进度等查询通过anime_genres关联视频,没有类型的视频无法正常播放
+	if len(genres) == 0 {
+		return nil, errors.New("视频类型不能为空")
+	}
+
+	if err := s.checkIdentity(ctx, video); err != nil {
+		return nil, err
+	}
+
+	video.UploaderID = request.UserID
+	if err := s.videoRepository.CreateVideo(ctx, video, genres); err != nil {
+		return nil, fmt.Errorf("创建视频失败: %v", err)
+	}
+
+	s.invalidateCache(ctx, video.ID)
+	s.publishCatalogChange(ctx, []int{video.ID})
+
+	return &dto.CatalogVideoSaveResponse{
+		Code:    200,
+		Message: "视频已创建",
+		VideoID: video.ID,
+	}, nil
+}
+
+func (s *CatalogServiceImpl) UpdateVideo(ctx context.Context, request *dto.CatalogVideoSaveRequest) (*dto.CatalogVideoSaveResponse, error) {
+	if request.VideoID <= 0 {
+		return nil, errors.New("视频ID不能为空")
+	}
+
+	existing, err := s.getVideo(ctx, request.VideoID)
+	if err != nil {
+		return nil, err
+	}
+
+	video, err := buildCatalogVideo(request)
+	if err != nil {
+		return nil, err
+	}
+	genres, err := normalizeGenres(request.Genres)
+	if err != nil {
+		return nil, err
+	}
+
+	video.ID = existing.ID
+	video.UploaderID = existing.UploaderID
+	if err := s.checkIdentity(ctx, video); err != nil {
+		return nil, err
+	}
+
+	if err := s.videoRepository.UpdateVideo(ctx, video); err != nil {
+		return nil, fmt.Errorf("更新视频失败: %v", err)
+	}
+	if len(genres) > 0 {
+		if err := s.videoRepository.ReplaceAnimeGenres(ctx, video.ID, genres); err != nil {
+			return nil, fmt.Errorf("保存视频类型失败: %v", err)
+		}
+	}
+
+	s.invalidateCache(ctx, video.ID)
+	s.publishCatalogChange(ctx, []int{video.ID})
+
+	return &dto.CatalogVideoSaveResponse{
+		Code:    200,
+		Message: "视频已更新",
+		VideoID: video.ID,
+	}, nil
+}
+
+func (s *CatalogServiceImpl) DeleteVideo(ctx context.Context, request *dto.CatalogVideoRequest) (*dto.CatalogResponse, error) {
+	if _, err := s.getVideo(ctx, request.VideoID); err != nil {
+		return nil, err
+	}
+
+	if err := s.videoRepository.DeleteVideo(ctx, request.VideoID); err != nil {
+		return nil, fmt.Errorf("删除视频失败: %v", err)
+	}
+
+	s.invalidateCache(ctx, request.VideoID)
+	s.publishCatalogChange(ctx, []int{request.VideoID})
+
+	return &dto.CatalogResponse{
+		Code:    200,
+		Message: "视频已删除",
+	}, nil
+}
+
+func (s *CatalogServiceImpl) UpdateGenres(ctx context.Context, request *dto.CatalogGenresRequest) (*dto.CatalogResponse, error) {
+	genres, err := normalizeGenres(request.Genres)
+	if err != nil {
+		return nil, err
+	}
+	if len(genres) == 0 {
+		return nil, errors.New("视频类型不能为空")
+	}
+
+	if _, err := s.getVideo(ctx, request.VideoID); err != nil {
+		return nil, err
+	}
+
+	if err := s.videoRepository.ReplaceAnimeGenres(ctx, request.VideoID, genres); err != nil {
+		return nil, fmt.Errorf("保存视频类型失败: %v", err)
+	}
+
+	s.invalidateCache(ctx, request.VideoID)
+	s.publishCatalogChange(ctx, []int{request.VideoID})
+
+	return &dto.CatalogResponse{
+		Code:    200,
+		Message: "视频类型已更新",
+	}, nil
+}
+
+func (s *CatalogServiceImpl) UpdateAliases(ctx context.Context, request *dto.CatalogAliasesRequest) (*dto.CatalogResponse, error) {
+	aliases := make([]string, 0, len(request.Aliases))
+	seen := make(map[string]bool, len(request.Aliases))
+	for _, alias := range request.Aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			return nil, errors.New("别名不能为空")
+		}
+		if seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+
+	if _, err := s.getVideo(ctx, request.VideoID); err != nil {
+		return nil, err
+	}
+
+	if err := s.videoRepository.ReplaceAnimeAliases(ctx, request.VideoID, aliases); err != nil {
+		return nil, fmt.Errorf("保存视频别名失败: %v", err)
+	}
+
+	// 别名只用于检索,不影响缓存
+	s.publishCatalogChange(ctx, []int{request.VideoID})
+
+	return &dto.CatalogResponse{
+		Code:    200,
+		Message: "视频别名已更新",
+	}, nil
+}
+
+func (s *CatalogServiceImpl) UpdateEpisodes(ctx context.Context, request *dto.CatalogEpisodesRequest) (*dto.CatalogResponse, error) {
+	// 替换为空列表会删除全部剧集并级联删除用户的观看进度
+	if len(request.Episodes) == 0 {
+		return nil, errors.New("剧集列表不能为空")
+	}
+
+	episodes := make([]*entity.VideoEpisode, 0, len(request.Episodes))
+	seen := make(map[string]bool, len(request.Episodes))
+	for _, item := range request.Episodes {
+		episode := strings.TrimSpace(item.Episode)
+		if episode == "" {
+			return nil, errors.New("集数不能为空")
+		}
+		if seen[episode] {
+			return nil, fmt.Errorf("集数重复: %s", episode)
+		}
+		seen[episode] = true
+		episodes = append(episodes, &entity.VideoEpisode{
+			VideoID:  request.VideoID,
+			Episode:  episode,
+			VideoURL: strings.TrimSpace(item.VideoURL),
+			Status:   1,
+		})
+	}
+
+	if _, err := s.getVideo(ctx, request.VideoID); err != nil {
+		return nil, err
+	}
+
+	if err := s.videoRepository.ReplaceVideoEpisodes(ctx, request.VideoID, episodes); err != nil {
+		return nil, fmt.Errorf("保存剧集列表失败: %v", err)
+	}
+
+	s.invalidateCache(ctx, request.VideoID)
+
+	return &dto.CatalogResponse{
+		Code:    200,
+		Message: "剧集列表已更新",
+	}, nil
+}
+
+func (s *CatalogServiceImpl) GetEpisodeURL(ctx context.Context, request *dto.CatalogEpisodeURLRequest) (*dto.CatalogEpisodeURLResponse, error) {
+	stored, err := s.getStoredVideoSources(ctx, request.VideoID, request.Episode)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := s.videoRepository.GetVideoSources(ctx, entity.VideoSourceKey(request.VideoID, request.Episode))
+	if err != nil {
+		return nil, fmt.Errorf("获取缓存播放源失败: %v", err)
+	}
+	if cached == nil {
+		cached = make([]*entity.VideoSource, 0)
+	}
+
+	return &dto.CatalogEpisodeURLResponse{
+		Code:    200,
+		Stored:  stored,
+		Expired: stored.ScrapedAt == "" || stored.Age > storedVideoSourceTTL,
+		Cached:  cached,
+	}, nil
+}
+
+func (s *CatalogServiceImpl) UpdateEpisodeURL(ctx context.Context, request *dto.CatalogEpisodeURLUpdateRequest) (*dto.CatalogResponse, error) {
+	videoURL := strings.TrimSpace(request.VideoURL)
+	if _, err := s.getStoredVideoSources(ctx, request.VideoID, request.Episode); err != nil {
+		return nil, err
+	}
+
+	if err := s.videoRepository.UpdateEpisodeVideoURL(ctx, request.VideoID, request.Episode, videoURL); err != nil {
+		return nil, fmt.Errorf("保存播放地址失败: %v", err)
+	}
+
+	// 指定地址时直接覆盖缓存,取消指定时清除缓存,下次播放时使用数据库中的爬取结果或重新爬取
+	URLKey := entity.VideoSourceKey(request.VideoID, request.Episode)
+	var err error
+	if videoURL != "" {
+		err = s.videoRepository.CacheVideoSources(ctx, URLKey, []*entity.VideoSource{manualVideoSource(videoURL)})
+	} else {
+		err = s.videoRepository.DeleteVideoSources(ctx, URLKey)
+	}
+	if err != nil {
+		logger.Log.Warn("更新播放地址缓存失败", zap.String("key", URLKey), zap.Error(err))
+	}
+
+	message := "播放地址已指定"
+	if videoURL == "" {
+		message = "已取消指定播放地址"
+	}
+	return &dto.CatalogResponse{
+		Code:    200,
+		Message: message,
+	}, nil
+}
+
+// getStoredVideoSources 获取数据库中保存的一集视频的播放地址,剧集不存在时返回错误
+func (s *CatalogServiceImpl) getStoredVideoSources(ctx context.Context, videoID int, episode string) (*entity.StoredVideoSources, error) {
+	stored, err := s.videoRepository.GetStoredVideoSources(ctx, videoID, episode)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("剧集不存在")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取播放地址失败: %v", err)
+	}
+	return stored, nil
+}
+
+// getVideo 获取视频,不存在时返回错误
+func (s *CatalogServiceImpl) getVideo(ctx context.Context, videoID int) (*entity.Video, error) {
+	video, err := s.videoRepository.GetVideoByID(ctx, videoID)
+	if err != nil || video.ID == 0 {
+		return nil, errors.New("视频不存在")
+	}
+	return video, nil
+}
+
+// checkIdentity 校验名称、上映时间和地区组合未被其他视频占用
+func (s *CatalogServiceImpl) checkIdentity(ctx context.Context, video *entity.Video) error {
+	videoID, err := s.videoRepository.GetVideoIDByIdentity(ctx, video.Name, video.ReleaseDate, video.Area)
+	if err != nil {
+		return fmt.Errorf("查询视频失败: %v", err)
+	}
+	if videoID != 0 && videoID != video.ID {
+		return fmt.Errorf("相同名称、上映时间和地区的视频已存在,视频ID: %d", videoID)
+	}
+	return nil
+}
+
+// invalidateCache 清除由目录派生的缓存,目录修改已生效,清除失败只记录日志,缓存过期后自动恢复
+func (s *CatalogServiceImpl) invalidateCache(ctx context.Context, videoID int) {
+	if err := s.videoRepository.InvalidateCatalogCache(ctx, videoID); err != nil {
+		logger.Log.Warn("清除视频目录缓存失败", zap.Int("videoID", videoID), zap.Error(err))
+	}
+}
+
+// publishCatalogChange 发布目录变更消息,通知各实例更新检索索引
+// 目录修改已生效,发布失败只记录日志,索引在下一次定期重建时恢复
+func (s *CatalogServiceImpl) publishCatalogChange(ctx context.Context, videoIDs []int) {
+	for start := 0; start < len(videoIDs); start += catalogChangeBatchSize {
+		end := start + catalogChangeBatchSize
+		if end > len(videoIDs) {
+			end = len(videoIDs)
+		}
+
+		eventJson, err := json.Marshal(&entity.CatalogChangeEvent{VideoIDs: videoIDs[start:end]})
+		if err == nil {
+			err = s.producerPool.Publish(ctx, catalogChangeTopic, eventJson)
+		}
+		if err != nil {
+			logger.Log.Warn("发布目录变更消息失败", zap.Ints("videoIDs", videoIDs[start:end]), zap.Error(err))
+		}
+	}
+}
+
+// buildCatalogVideo 去除首尾空白并校验视频基本信息
+func buildCatalogVideo(request *dto.CatalogVideoSaveRequest) (*entity.Video, error) {
+	video := &entity.Video{
+		ID:            request.VideoID,
+		Name:          strings.TrimSpace(request.Name),
+		ReleaseDate:   strings.TrimSpace(request.ReleaseDate),
+		Area:          strings.TrimSpace(request.Area),
+		Description:   strings.TrimSpace(request.Description),
+		CoverImageUrl: strings.TrimSpace(request.CoverImageUrl),
+	}
+	if video.Name == "" || video.ReleaseDate == "" || video.Area == "" || video.CoverImageUrl == "" {
+		return nil, errors.New("视频名称、上映时间、地区和封面不能为空")
+	}
+	return video, nil
+}
+
+// normalizeGenres 去除首尾空白并去重,保持原有顺序
+func normalizeGenres(genres []string) ([]string, error) {
+	result := make([]string, 0, len(genres))
+	seen := make(map[string]bool, len(genres))
+	for _, genre := range genres {
+		genre = strings.TrimSpace(genre)
+		if genre == "" {
+			return nil, errors.New("视频类型不能为空")
+		}
+		if seen[genre] {
+			continue
+		}
+		seen[genre] = true
+		result = append(result, genre)
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/logger"
+	"gateService/pkg/mq/nsqpool"
+
+	"go.uber.org/zap"
+)
+
+// 关注通知的消息队列主题
+const followTopic = "follow_channel"
+
+// 关注列表默认每页数量
+const defaultFollowPageSize = 20
+
+type FollowServiceImpl struct {
+	followRepository repository.FollowRepository
+	blockRepository  repository.BlockRepository
+	userRepository   repository.UserRepository
+	producerPool     *nsqpool.ProducerPool
+}
+
+func NewFollowServiceImpl(followRepository repository.FollowRepository, blockRepository repository.BlockRepository, userRepository repository.UserRepository, producerPool *nsqpool.ProducerPool) *FollowServiceImpl {
+	return &FollowServiceImpl{
+		followRepository: followRepository,
+		blockRepository:  blockRepository,
+		userRepository:   userRepository,
+		producerPool:     producerPool,
+	}
+}
+
+func (s *FollowServiceImpl) Follow(ctx context.Context, request *dto.FollowRequest) (*dto.FollowResponse, error) {
+	if request.UserID == request.FolloweeID {
+		return nil, errors.New("不能关注自己")
+	}
+
+	followee, err := s.userRepository.GetUserByID(ctx, request.FolloweeID)
+	if err != nil || followee.Status != 1 {
+		return nil, errors.New("用户不存在")
+	}
+
+	blocked, err := s.blockRepository.IsBlocked(ctx, request.FolloweeID, request.UserID, entity.BlockTypeBlock)
+	if err != nil {
+		return nil, fmt.Errorf("获取拉黑状态失败: %v", err)
+	}
+	if blocked {
+		return nil, errors.New("对方已将你拉黑,无法关注")
+	}
+	blocked, err = s.blockRepository.IsBlocked(ctx, request.UserID, request.FolloweeID, entity.BlockTypeBlock)
+	if err != nil {
+		return nil, fmt.Errorf("获取拉黑状态失败: %v", err)
+	}
+	if blocked {
+		return nil, errors.New("请先将对方移出拉黑列表")
+	}
+
+	created, err := s.followRepository.CreateFollow(ctx, request.UserID, request.FolloweeID)
+	if err != nil {
+		return nil, fmt.Errorf("关注失败: %v", err)
+	}
+
+	// 关注关系已同步写入,通知的创建和推送异步执行,失败不影响关注结果
+	if created {
+		followJson, err := json.Marshal(&entity.UserFollow{
+			FollowerID: request.UserID,
+			FolloweeID: request.FolloweeID,
+		})
+		if err == nil {
+			err = s.producerPool.Publish(ctx, followTopic, followJson)
+		}
+		if err != nil {
+			logger.Log.Warn("发布关注通知失败", zap.Int("followerID", request.UserID), zap.Int("followeeID", request.FolloweeID), zap.Error(err))
+		}
+	}
+
+	return &dto.FollowResponse{
+		Code:      200,
+		Message:   "关注成功",
+		Following: true,
+	}, nil
+}
+
+func (s *FollowServiceImpl) Unfollow(ctx context.Context, request *dto.FollowRequest) (*dto.FollowResponse, error) {
+	if _, err := s.followRepository.DeleteFollow(ctx, request.UserID, request.FolloweeID); err != nil {
+		return nil, fmt.Errorf("取消关注失败: %v", err)
+	}
+
+	return &dto.FollowResponse{
+		Code:      200,
+		Message:   "已取消关注",
+		Following: false,
+	}, nil
+}
+
+func (s *FollowServiceImpl) GetFollowers(ctx context.Context, request *dto.FollowListRequest) (*dto.FollowListResponse, error) {
+	targetUserID, pageSize := followListParams(request)
+
+	users, err := s.followRepository.GetFollowers(ctx, targetUserID, request.Page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("获取粉丝列表失败: %v", err)
+	}
+
+	return &dto.FollowListResponse{
+		Code:  200,
+		Users: users,
+	}, nil
+}
+
+func (s *FollowServiceImpl) GetFollowing(ctx context.Context, request *dto.FollowListRequest) (*dto.FollowListResponse, error) {
+	targetUserID, pageSize := followListParams(request)
+
+	users, err := s.followRepository.GetFollowing(ctx, targetUserID, request.Page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("获取关注列表失败: %v", err)
+	}
+
+	return &dto.FollowListResponse{
+		Code:  200,
+		Users: users,
+	}, nil
+}
+
+// followListParams 未指定查询用户时查询当前用户,未指定每页数量时使用默认值
+func followListParams(request *dto.FollowListRequest) (int, int) {
+	targetUserID := request.TargetUserID
+	if targetUserID == 0 {
+		targetUserID = request.UserID
+	}
+	pageSize := request.PageSize
+	if pageSize == 0 {
+		pageSize = defaultFollowPageSize
+	}
+	return targetUserID, pageSize
+}
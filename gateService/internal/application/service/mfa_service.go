@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/password"
+	"gateService/pkg/totp"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// mfaConfig 两步验证相关配置
+type mfaConfig struct {
+	issuer            string // 验证器应用中显示的签发方名称
+	recoveryCodeCount int    // 每次生成的恢复码数量
+}
+
+// 恢复码字符集,去除了易混淆的0/O/1/I
+const recoveryCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+type MfaServiceImpl struct {
+	mfaConfig      *mfaConfig
+	userRepository repository.UserRepository
+	mfaRepository  repository.MfaRepository
+	passwordHasher *password.Hasher
+}
+
+func NewMfaServiceImpl(
+	issuer string,
+	userRepository repository.UserRepository,
+	mfaRepository repository.MfaRepository,
+	passwordHasher *password.Hasher,
+) *MfaServiceImpl {
+	if issuer == "" {
+		issuer = "Zanime"
+	}
+	return &MfaServiceImpl{
+		mfaConfig: &mfaConfig{
+			issuer:            issuer,
+			recoveryCodeCount: 10,
+		},
+		userRepository: userRepository,
+		mfaRepository:  mfaRepository,
+		passwordHasher: passwordHasher,
+	}
+}
+
+func (s *MfaServiceImpl) GetMfaStatus(ctx context.Context, request *dto.GetMfaStatusRequest) (*dto.GetMfaStatusResponse, error) {
+	mfa, err := s.mfaRepository.GetUserMfa(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取两步验证状态失败: %v", err)
+	}
+
+	return &dto.GetMfaStatusResponse{
+		Code:    200,
+		Enabled: mfa != nil && mfa.Enabled,
+	}, nil
+}
+
+func (s *MfaServiceImpl) SetupMfa(ctx context.Context, request *dto.SetupMfaRequest) (*dto.SetupMfaResponse, error) {
+	mfa, err := s.mfaRepository.GetUserMfa(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取两步验证状态失败: %v", err)
+	}
+	if mfa != nil && mfa.Enabled {
+		return nil, fmt.Errorf("已开启两步验证,请先关闭后再重新绑定")
+	}
+
+	userInfo, err := s.userRepository.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %v", err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepository.SaveUserMfaSecret(ctx, request.UserID, secret); err != nil {
+		return nil, fmt.Errorf("保存两步验证密钥失败: %v", err)
+	}
+
+	return &dto.SetupMfaResponse{
+		Code:            200,
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.mfaConfig.issuer, userInfo.Email, secret),
+	}, nil
+}
+
+func (s *MfaServiceImpl) ConfirmMfa(ctx context.Context, request *dto.ConfirmMfaRequest) (*dto.ConfirmMfaResponse, error) {
+	mfa, err := s.mfaRepository.GetUserMfa(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取两步验证状态失败: %v", err)
+	}
+	if mfa == nil {
+		return nil, fmt.Errorf("请先开始绑定两步验证")
+	}
+	if mfa.Enabled {
+		return nil, fmt.Errorf("已开启两步验证")
+	}
+
+	// 确认阶段只接受动态验证码,确保验证器应用已正确导入密钥
+	ok, step, err := totp.Validate(mfa.Secret, request.Code, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("校验验证码失败: %v", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("验证码错误")
+	}
+	fresh, err := s.mfaRepository.MarkTotpStepUsed(ctx, request.UserID, step, totpUsedTTL())
+	if err != nil {
+		return nil, fmt.Errorf("记录验证码使用状态失败: %v", err)
+	}
+	if !fresh {
+		return nil, fmt.Errorf("验证码已使用,请等待下一个验证码")
+	}
+
+	codes, hashes, err := generateRecoveryCodes(s.mfaConfig.recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepository.EnableUserMfa(ctx, request.UserID, hashes); err != nil {
+		return nil, fmt.Errorf("开启两步验证失败: %v", err)
+	}
+
+	return &dto.ConfirmMfaResponse{
+		Code:          200,
+		Message:       "两步验证已开启,请妥善保存恢复码",
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (s *MfaServiceImpl) DisableMfa(ctx context.Context, request *dto.DisableMfaRequest) (*dto.DisableMfaResponse, error) {
+	mfa, err := s.mfaRepository.GetUserMfa(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取两步验证状态失败: %v", err)
+	}
+	if mfa == nil || !mfa.Enabled {
+		return nil, fmt.Errorf("未开启两步验证")
+	}
+
+	userInfo, err := s.userRepository.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	credential, err := s.userRepository.GetUserCredentialByEmail(ctx, userInfo.Email)
+	if err != nil {
+		return nil, fmt.Errorf("验证用户失败: %v", err)
+	}
+	if credential == nil {
+		return nil, fmt.Errorf("用户不存在")
+	}
+	ok, err := s.passwordHasher.Verify(credential.Password, request.Password)
+	if err != nil {
+		return nil, fmt.Errorf("验证用户失败: %v", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("密码错误")
+	}
+
+	ok, err = verifyMfaCode(ctx, s.mfaRepository, mfa, request.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("验证码错误")
+	}
+
+	if err := s.mfaRepository.DisableUserMfa(ctx, request.UserID); err != nil {
+		return nil, fmt.Errorf("关闭两步验证失败: %v", err)
+	}
+
+	return &dto.DisableMfaResponse{
+		Code:    200,
+		Message: "两步验证已关闭",
+	}, nil
+}
+
+// verifyMfaCode 校验两步验证码
+// 6位数字按TOTP动态验证码校验,同一时间步的验证码只能使用一次;其余输入按恢复码校验
+func verifyMfaCode(ctx context.Context, mfaRepository repository.MfaRepository, mfa *entity.UserMfa, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits && isDigits(code) {
+		ok, step, err := totp.Validate(mfa.Secret, code, time.Now())
+		if err != nil {
+			return false, fmt.Errorf("校验验证码失败: %v", err)
+		}
+		if !ok {
+			return false, nil
+		}
+		fresh, err := mfaRepository.MarkTotpStepUsed(ctx, mfa.UserID, step, totpUsedTTL())
+		if err != nil {
+			return false, fmt.Errorf("记录验证码使用状态失败: %v", err)
+		}
+		return fresh, nil
+	}
+
+	ok, err := mfaRepository.UseRecoveryCode(ctx, mfa.UserID, hashRecoveryCode(code))
+	if err != nil {
+		return false, fmt.Errorf("校验恢复码失败: %v", err)
+	}
+	return ok, nil
+}
+
+// totpUsedTTL 已使用验证码标记的保留时长,覆盖验证码允许的全部偏移窗口
+func totpUsedTTL() time.Duration {
+	return time.Duration(2*totp.Skew+1) * totp.Period
+}
+
+// generateRecoveryCodes 生成count个恢复码,格式为XXXXX-XXXXX,同时返回其哈希值
+func generateRecoveryCodes(count int) ([]string, []string, error) {
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < count; i++ {
+		var b strings.Builder
+		for j := 0; j < 10; j++ {
+			if j == 5 {
+				b.WriteByte('-')
+			}
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, nil, fmt.Errorf("生成恢复码失败: %v", err)
+			}
+			b.WriteByte(recoveryCodeAlphabet[n.Int64()])
+		}
+		code := b.String()
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode 计算恢复码的哈希值,忽略大小写、空格和连字符
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// isDigits 判断字符串是否全部由数字组成
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
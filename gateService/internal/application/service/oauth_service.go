@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/config"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/identity"
+	"gateService/pkg/password"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OAuthServiceImpl struct {
+	oauthConfig        *config.OAuthConfig
+	providers          map[string]identity.IdentityProvider
+	userRepository     repository.UserRepository
+	identityRepository repository.IdentityRepository
+	mfaRepository      repository.MfaRepository
+	jwtManager         *auth.JWTManager
+	tokenIssuer        *tokenIssuer
+	passwordHasher     *password.Hasher
+}
+
+func NewOAuthServiceImpl(
+	oauthConfig *config.OAuthConfig,
+	providers map[string]identity.IdentityProvider,
+	userRepository repository.UserRepository,
+	identityRepository repository.IdentityRepository,
+	mfaRepository repository.MfaRepository,
+	tokenRepository repository.TokenRepository,
+	jwtManager *auth.JWTManager,
+	cookieManager *auth.CookieManager,
+	passwordHasher *password.Hasher,
+) *OAuthServiceImpl {
+	if oauthConfig.StateTTL == 0 {
+		oauthConfig.StateTTL = 10 * time.Minute
+	}
+	return &OAuthServiceImpl{
+		oauthConfig:        oauthConfig,
+		providers:          providers,
+		userRepository:     userRepository,
+		identityRepository: identityRepository,
+		mfaRepository:      mfaRepository,
+		jwtManager:         jwtManager,
+		tokenIssuer:        newTokenIssuer(jwtManager, cookieManager, tokenRepository),
+		passwordHasher:     passwordHasher,
+	}
+}
+
+func (s *OAuthServiceImpl) GetProviders(ctx context.Context) (*dto.OAuthProvidersResponse, error) {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &dto.OAuthProvidersResponse{
+		Code:      200,
+		Providers: names,
+	}, nil
+}
+
+func (s *OAuthServiceImpl) GetAuthorizationURL(ctx context.Context, request *dto.OAuthLoginRequest) (*dto.OAuthLoginResponse, error) {
+	provider, ok := s.providers[request.Provider]
+	if !ok {
+		return nil, fmt.Errorf("不支持的登录方式: %s", request.Provider)
+	}
+
+	state, err := identity.GenerateRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := identity.GenerateRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	codeVerifier, err := identity.GenerateCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.identityRepository.SaveOAuthState(ctx, state, &entity.OAuthState{
+		Provider:     request.Provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		RedirectPath: sanitizeRedirectPath(request.Redirect),
+	}, s.oauthConfig.StateTTL)
+	if err != nil {
+		return nil, fmt.Errorf("保存登录状态失败: %v", err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, identity.CodeChallengeS256(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("生成授权地址失败: %v", err)
+	}
+
+	return &dto.OAuthLoginResponse{
+		Code:    200,
+		AuthURL: authURL,
+	}, nil
+}
+
+func (s *OAuthServiceImpl) HandleCallback(ctx context.Context, request *dto.OAuthCallbackRequest) (*dto.OAuthCallbackResponse, error) {
+	if request.Error != "" {
+		return nil, fmt.Errorf("第三方登录失败: %s %s", request.Error, request.ErrorDescription)
+	}
+	if request.State == "" || request.Code == "" {
+		return nil, errors.New("缺少授权码或state")
+	}
+
+	// state只能使用一次,且必须与回调的身份提供方一致
+	state, err := s.identityRepository.ConsumeOAuthState(ctx, request.State)
+	if err != nil {
+		return nil, fmt.Errorf("获取登录状态失败: %v", err)
+	}
+	if state == nil || state.Provider != request.Provider {
+		return nil, errors.New("登录请求无效或已过期,请重新登录")
+	}
+
+	provider, ok := s.providers[request.Provider]
+	if !ok {
+		return nil, fmt.Errorf("不支持的登录方式: %s", request.Provider)
+	}
+
+	ident, err := provider.Exchange(ctx, request.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("第三方登录失败: %v", err)
+	}
+
+	redirectURL := strings.TrimSuffix(s.oauthConfig.FrontendURL, "/") + state.RedirectPath
+
+	userInfo, pending, err := s.resolveUser(ctx, ident)
+	if err != nil {
+		return nil, err
+	}
+
+	// 邮箱已被本地账号使用时不直接绑定,由前端要求用户输入该账号密码后调用确认接口
+	// 令牌放在URL片段中,不会发送到服务端或写入访问日志
+	if pending != nil {
+		linkToken, err := identity.GenerateRandomString(32)
+		if err != nil {
+			return nil, err
+		}
+		pending.RedirectPath = state.RedirectPath
+		if err := s.identityRepository.SavePendingLink(ctx, linkToken, pending, s.oauthConfig.StateTTL); err != nil {
+			return nil, fmt.Errorf("保存待确认的身份绑定失败: %v", err)
+		}
+		return &dto.OAuthCallbackResponse{
+			Code:        200,
+			RedirectURL: redirectURL + "#link_token=" + url.QueryEscape(linkToken),
+		}, nil
+	}
+	userInfo.Password = ""
+
+	mfaToken, err := s.login(ctx, userInfo)
+	if err != nil {
+		return nil, err
+	}
+	if mfaToken != "" {
+		return &dto.OAuthCallbackResponse{
+			Code:        200,
+			RedirectURL: redirectURL + "#mfa_token=" + url.QueryEscape(mfaToken),
+		}, nil
+	}
+
+	return &dto.OAuthCallbackResponse{
+		Code:        200,
+		RedirectURL: redirectURL,
+	}, nil
+}
+
+func (s *OAuthServiceImpl) ConfirmLink(ctx context.Context, request *dto.OAuthLinkConfirmRequest) (*dto.OAuthLinkConfirmResponse, error) {
+	// 确认令牌只能使用一次,密码错误时需要重新通过身份提供方登录,无法在此暴力猜测密码
+	link, err := s.identityRepository.ConsumePendingLink(ctx, request.LinkToken)
+	if err != nil {
+		return nil, fmt.Errorf("获取待确认的身份绑定失败: %v", err)
+	}
+	if link == nil {
+		return nil, errors.New("绑定请求无效或已过期,请重新登录")
+	}
+
+	credential, err := s.userRepository.GetUserCredentialByEmail(ctx, link.Email)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户失败: %v", err)
+	}
+	if credential == nil || credential.UserID != link.UserID {
+		return nil, errors.New("账号不可用")
+	}
+	ok, err := s.passwordHasher.Verify(credential.Password, request.Password)
+	if err != nil {
+		return nil, fmt.Errorf("校验密码失败: %v", err)
+	}
+	if !ok {
+		return nil, errors.New("密码错误,请重新登录后再绑定")
+	}
+
+	err = s.identityRepository.CreateUserIdentity(ctx, &entity.UserIdentity{
+		UserID:   link.UserID,
+		Provider: link.Provider,
+		Subject:  link.Subject,
+		Email:    link.Email,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("绑定第三方身份失败: %v", err)
+	}
+
+	userInfo, err := s.userRepository.GetUserByID(ctx, link.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	if userInfo.Status != 1 {
+		return nil, errors.New("账号不可用")
+	}
+	userInfo.Password = ""
+
+	mfaToken, err := s.login(ctx, userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OAuthLinkConfirmResponse{
+		Code:        200,
+		Message:     "第三方账号已绑定",
+		MfaToken:    mfaToken,
+		RedirectURL: strings.TrimSuffix(s.oauthConfig.FrontendURL, "/") + link.RedirectPath,
+	}, nil
+}
+
+// login 完成第三方登录
+// 开启两步验证时与密码登录一致,仅生成待确认令牌,否则签发会话令牌
+// 返回:
+// - string: 两步验证待确认令牌,未开启两步验证时为空
+// - error: 错误信息
+func (s *OAuthServiceImpl) login(ctx context.Context, userInfo *entity.UserInfo) (string, error) {
+	mfa, err := s.mfaRepository.GetUserMfa(ctx, userInfo.UserID)
+	if err != nil {
+		return "", fmt.Errorf("获取两步验证状态失败: %v", err)
+	}
+	if mfa != nil && mfa.Enabled {
+		mfaToken, err := s.jwtManager.GenerateMfaPendingToken(&entity.UserInfo{UserID: userInfo.UserID, Email: userInfo.Email})
+		if err != nil {
+			return "", fmt.Errorf("生成两步验证令牌失败: %v", err)
+		}
+		return mfaToken, nil
+	}
+
+	if err := s.tokenIssuer.issueTokens(ctx, userInfo, uuid.New().String()); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// resolveUser 根据第三方身份查找或创建本地用户
+// 已绑定时返回绑定的用户;未绑定时要求身份提供方已验证邮箱,邮箱未注册则创建新账号并绑定;
+// 邮箱已注册时只返回待确认的绑定,需要用户输入本地账号密码确认,不依赖身份提供方的邮箱校验
+// 返回:
+// - *entity.UserInfo: 本地用户,需要确认绑定时为nil
+// - *entity.OAuthPendingLink: 待确认的身份绑定,不需要确认时为nil
+// - error: 错误信息
+func (s *OAuthServiceImpl) resolveUser(ctx context.Context, ident *identity.Identity) (*entity.UserInfo, *entity.OAuthPendingLink, error) {
+	link, err := s.identityRepository.GetUserIdentity(ctx, ident.Provider, ident.Subject)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取第三方身份绑定失败: %v", err)
+	}
+
+	var userInfo *entity.UserInfo
+	if link != nil {
+		userInfo, err = s.userRepository.GetUserByID(ctx, link.UserID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("获取用户信息失败: %v", err)
+		}
+	} else {
+		// 未验证的邮箱可能并不属于该用户,不能据此绑定或占用本地账号
+		if ident.Email == "" || !ident.EmailVerified {
+			return nil, nil, errors.New("第三方账号未提供已验证的邮箱,无法登录")
+		}
+
+		exist, err := s.userRepository.IsExistUser(ctx, ident.Email)
+		if err != nil {
+			return nil, nil, fmt.Errorf("检查用户是否存在失败: %v", err)
+		}
+		if exist {
+			userInfo, err = s.userRepository.GetUserByEmail(ctx, ident.Email)
+			if err != nil {
+				return nil, nil, fmt.Errorf("获取用户信息失败: %v", err)
+			}
+			if userInfo.Status != 1 {
+				return nil, nil, errors.New("账号不可用")
+			}
+			return nil, &entity.OAuthPendingLink{
+				UserID:   userInfo.UserID,
+				Provider: ident.Provider,
+				Subject:  ident.Subject,
+				Email:    ident.Email,
+			}, nil
+		}
+
+		userInfo, err = s.createUser(ctx, ident)
+		if err != nil {
+			return nil, nil, fmt.Errorf("获取用户信息失败: %v", err)
+		}
+
+		err = s.identityRepository.CreateUserIdentity(ctx, &entity.UserIdentity{
+			UserID:   userInfo.UserID,
+			Provider: ident.Provider,
+			Subject:  ident.Subject,
+			Email:    ident.Email,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("绑定第三方身份失败: %v", err)
+		}
+	}
+
+	if userInfo.Status != 1 {
+		return nil, nil, errors.New("账号不可用")
+	}
+	return userInfo, nil, nil
+}
+
+// createUser 为第三方身份创建本地账号
+// 密码设为随机值,用户如需密码登录可通过找回密码设置
+func (s *OAuthServiceImpl) createUser(ctx context.Context, ident *identity.Identity) (*entity.UserInfo, error) {
+	randomPassword, err := identity.GenerateRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	passwordHash, err := s.passwordHasher.Hash(randomPassword)
+	if err != nil {
+		return nil, fmt.Errorf("生成密码哈希失败: %v", err)
+	}
+
+	username := ident.Name
+	if username == "" {
+		username = strings.SplitN(ident.Email, "@", 2)[0]
+	}
+	avatarURL := ident.Picture
+	if avatarURL == "" {
+		avatarURL = "/src/static/picture/Ellipse_3.png"
+	}
+
+	userID, err := s.userRepository.CreateUser(ctx, &entity.UserInfo{
+		Username:  username,
+		Email:     ident.Email,
+		Password:  passwordHash,
+		AvatarURL: avatarURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepository.GetUserByID(ctx, userID)
+}
+
+// sanitizeRedirectPath 校验登录后跳转路径,仅允许站内相对路径,防止开放重定向
+func sanitizeRedirectPath(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.ContainsAny(path, "\\\r\n") {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"strings"
+	"time"
+)
+
+// 单个用户允许持有的个人访问令牌数量上限
+const maxPersonalAccessTokensPerUser = 20
+
+type PersonalAccessTokenServiceImpl struct {
+	userRepository                repository.UserRepository
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository
+}
+
+func NewPersonalAccessTokenServiceImpl(userRepository repository.UserRepository, personalAccessTokenRepository repository.PersonalAccessTokenRepository) *PersonalAccessTokenServiceImpl {
+	return &PersonalAccessTokenServiceImpl{
+		userRepository:                userRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+	}
+}
+
+func (s *PersonalAccessTokenServiceImpl) CreateToken(ctx context.Context, request *dto.CreatePersonalAccessTokenRequest) (*dto.CreatePersonalAccessTokenResponse, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, errors.New("令牌名称不能为空")
+	}
+
+	scopes, err := normalizeScopes(request.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfo, err := s.userRepository.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	if entity.IsTestAccountEmail(userInfo.Email) {
+		return nil, errors.New("体验账号不能创建个人访问令牌")
+	}
+
+	count, err := s.personalAccessTokenRepository.CountTokensByUserID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取个人访问令牌失败: %v", err)
+	}
+	if count >= maxPersonalAccessTokensPerUser {
+		return nil, fmt.Errorf("个人访问令牌数量已达上限%d个,请先吊销不再使用的令牌", maxPersonalAccessTokensPerUser)
+	}
+
+	token, displayPrefix, tokenHash, err := auth.GeneratePersonalAccessToken()
+	if err != nil {
+		return nil, fmt.Errorf("生成个人访问令牌失败: %v", err)
+	}
+
+	now := time.Now()
+	pat := &entity.PersonalAccessToken{
+		UserID:      request.UserID,
+		Name:        name,
+		TokenHash:   tokenHash,
+		TokenPrefix: displayPrefix,
+		Scopes:      scopes,
+		CreatedAt:   now,
+	}
+	if request.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, request.ExpiresInDays)
+		pat.ExpiresAt = &expiresAt
+	}
+
+	if err := s.personalAccessTokenRepository.CreateToken(ctx, pat); err != nil {
+		return nil, fmt.Errorf("创建个人访问令牌失败: %v", err)
+	}
+
+	return &dto.CreatePersonalAccessTokenResponse{
+		Code:                200,
+		Message:             "个人访问令牌创建成功,请立即保存,关闭后将无法再次查看",
+		Token:               token,
+		PersonalAccessToken: pat,
+	}, nil
+}
+
+func (s *PersonalAccessTokenServiceImpl) ListTokens(ctx context.Context, request *dto.ListPersonalAccessTokensRequest) (*dto.ListPersonalAccessTokensResponse, error) {
+	tokens, err := s.personalAccessTokenRepository.ListTokensByUserID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取个人访问令牌失败: %v", err)
+	}
+
+	return &dto.ListPersonalAccessTokensResponse{
+		Code:            200,
+		Tokens:          tokens,
+		AvailableScopes: entity.PersonalAccessTokenScopes,
+	}, nil
+}
+
+func (s *PersonalAccessTokenServiceImpl) RevokeToken(ctx context.Context, request *dto.RevokePersonalAccessTokenRequest) (*dto.RevokePersonalAccessTokenResponse, error) {
+	// 按用户ID限定删除范围,防止吊销他人令牌
+	deleted, err := s.personalAccessTokenRepository.DeleteToken(ctx, request.UserID, request.TokenID)
+	if err != nil {
+		return nil, fmt.Errorf("吊销个人访问令牌失败: %v", err)
+	}
+	if !deleted {
+		return nil, errors.New("个人访问令牌不存在")
+	}
+
+	return &dto.RevokePersonalAccessTokenResponse{
+		Code:    200,
+		Message: "个人访问令牌已吊销",
+	}, nil
+}
+
+// normalizeScopes 校验授权范围并去重,同时支持逗号分隔的单个参数
+func normalizeScopes(requested []string) ([]string, error) {
+	allowed := make(map[string]struct{}, len(entity.PersonalAccessTokenScopes))
+	for _, scope := range entity.PersonalAccessTokenScopes {
+		allowed[scope] = struct{}{}
+	}
+
+	seen := make(map[string]struct{})
+	scopes := make([]string, 0, len(requested))
+	for _, item := range requested {
+		for _, scope := range strings.Split(item, ",") {
+			scope = strings.TrimSpace(scope)
+			if scope == "" {
+				continue
+			}
+			if _, ok := allowed[scope]; !ok {
+				return nil, fmt.Errorf("不支持的授权范围: %s", scope)
+			}
+			if _, ok := seen[scope]; ok {
+				continue
+			}
+			seen[scope] = struct{}{}
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return nil, errors.New("至少需要选择一个授权范围")
+	}
+	return scopes, nil
+}
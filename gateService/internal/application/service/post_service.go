@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"gateService/internal/domain/entity"
 	"gateService/internal/domain/repository"
@@ -26,6 +27,7 @@ type PostServiceImpl struct {
 	postTagRelationRepository repository.PostTagRelationRepository // 帖子标签关系仓储接口
 	postCommentRepository     repository.PostCommentRepository     // 帖子评论仓储接口
 	userRepository            repository.UserRepository            // 用户仓储接口
+	blockRepository           repository.BlockRepository           // 用户屏蔽关系仓储接口
 	producerPool              *nsqpool.ProducerPool
 }
 
@@ -36,9 +38,10 @@ type PostServiceImpl struct {
 // - postTagRelationRepository: 帖子标签关系仓储接口,用于维护帖子和标签的关联
 // - postCommentRepository: 帖子评论仓储接口,用于评论的管理
 // - userRepository: 用户仓储接口,用于获取用户信息
+// - blockRepository: 用户屏蔽关系仓储接口,用于拦截被拉黑用户的回复和过滤被屏蔽用户的评论
 // 返回:
 // - *PostServiceImpl: 初始化后的PostServiceImpl实例
-func NewPostServiceImpl(storageConfig *config.StorageConfig, postRepository repository.PostRepository, postTagRepository repository.PostTagRepository, postTagRelationRepository repository.PostTagRelationRepository, postCommentRepository repository.PostCommentRepository, userRepository repository.UserRepository, producerPool *nsqpool.ProducerPool) *PostServiceImpl {
+func NewPostServiceImpl(storageConfig *config.StorageConfig, postRepository repository.PostRepository, postTagRepository repository.PostTagRepository, postTagRelationRepository repository.PostTagRelationRepository, postCommentRepository repository.PostCommentRepository, userRepository repository.UserRepository, blockRepository repository.BlockRepository, producerPool *nsqpool.ProducerPool) *PostServiceImpl {
 	return &PostServiceImpl{
 		storageConfig:             storageConfig,
 		postRepository:            postRepository,
@@ -46,6 +49,7 @@ func NewPostServiceImpl(storageConfig *config.StorageConfig, postRepository repo
 		postTagRelationRepository: postTagRelationRepository,
 		postCommentRepository:     postCommentRepository,
 		userRepository:            userRepository,
+		blockRepository:           blockRepository,
 		producerPool:              producerPool,
 	}
 }
@@ -292,41 +296,48 @@ func (s *PostServiceImpl) GetPostByID(ctx context.Context, postID int64) (*entit
 // - *dto.GetCommentsResponse: 评论列表响应,包含评论列表、总页数等信息
 // - error: 获取评论过程中的错误信息
 func (s *PostServiceImpl) GetPostCommentsByPostID(ctx context.Context, request *dto.GetPostCommentsRequest) (*dto.GetPostCommentsResponse, error) {
-	// 1. 获取评论基本数据
+	// 1. 获取需要隐藏的用户
+	// 请求开启隐藏时排除当前用户拉黑或静音的用户发表的评论
+	hiddenUserIDs, err := s.getHiddenUserIDs(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 获取评论基本数据
 	// 根据帖子ID和分页参数获取评论列表
-	comments, err := s.postCommentRepository.GetPostCommentsByPostID(ctx, request.ID, request.Page, request.PageSize)
+	comments, err := s.postCommentRepository.GetPostCommentsByPostID(ctx, request.ID, request.Page, request.PageSize, hiddenUserIDs)
 	if err != nil {
 		return nil, fmt.Errorf("获取帖子评论失败: %v", err)
 	}
 
-	// 2. 获取总页数
+	// 3. 获取总页数
 	// 用于前端分页展示
-	totalPage, err := s.postCommentRepository.GetCommentTotalPage(ctx, request.ID, request.PageSize)
+	totalPage, err := s.postCommentRepository.GetCommentTotalPage(ctx, request.ID, request.PageSize, hiddenUserIDs)
 	if err != nil {
 		return nil, fmt.Errorf("获取评论总页数失败: %v", err)
 	}
 
-	// 3. 获取评论作者信息
-	// 3.1 提取所有评论的作者ID
+	// 4. 获取评论作者信息
+	// 4.1 提取所有评论的作者ID
 	userIDs := make([]int, 0, len(comments))
 	for _, comment := range comments {
 		userIDs = append(userIDs, comment.UserID)
 	}
 
-	// 3.2 批量获取作者信息
+	// 4.2 批量获取作者信息
 	users, err := s.userRepository.GetUsersByIDs(ctx, &userIDs)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户信息失败: %v", err)
 	}
 
-	// 4. 获取当前用户的点赞状态
+	// 5. 获取当前用户的点赞状态
 	// 查询当前用户对这些评论的点赞记录
 	likeMap, err := s.postCommentRepository.GetPostCommentLikesByUserID(ctx, request.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户点赞状态失败: %v", err)
 	}
 
-	// 5. 组装评论数据
+	// 6. 组装评论数据
 	commentItems := make([]*dto.CommentItem, 0, len(comments))
 	for i, comment := range comments {
 		// 构建每条评论的完整信息
@@ -347,7 +358,7 @@ func (s *PostServiceImpl) GetPostCommentsByPostID(ctx context.Context, request *
 		})
 	}
 
-	// 6. 返回响应数据
+	// 7. 返回响应数据
 	return &dto.GetPostCommentsResponse{
 		Code:      200,          // 成功状态码
 		TotalPage: totalPage,    // 总页数
@@ -363,13 +374,19 @@ func (s *PostServiceImpl) GetPostCommentsByPostID(ctx context.Context, request *
 // - []*dto.ReplyItem: 子评论列表数据
 // - error: 获取子评论过程中的错误信息
 func (s *PostServiceImpl) GetPostCommentsByRootID(ctx context.Context, request *dto.GetPostCommentsRequest) ([]*dto.ReplyItem, error) {
-	// 1. 从数据库获取指定根评论下的子评论列表
-	comments, err := s.postCommentRepository.GetPostCommentsByRootID(ctx, request.RootID, request.Page, request.PageSize)
+	// 1. 获取需要隐藏的用户
+	hiddenUserIDs, err := s.getHiddenUserIDs(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 从数据库获取指定根评论下的子评论列表
+	comments, err := s.postCommentRepository.GetPostCommentsByRootID(ctx, request.RootID, request.Page, request.PageSize, hiddenUserIDs)
 	if err != nil {
 		return nil, fmt.Errorf("获取二级评论失败: %v", err)
 	}
 
-	// 2. 收集评论相关的用户ID
+	// 3. 收集评论相关的用户ID
 	// userIDs: 评论作者的用户ID列表
 	// toUserIDs: 被回复用户的ID列表
 	userIDs := make([]int, 0)
@@ -379,29 +396,29 @@ func (s *PostServiceImpl) GetPostCommentsByRootID(ctx context.Context, request *
 		toUserIDs = append(toUserIDs, *comment.ToUserID)
 	}
 
-	// 3. 批量获取用户信息
-	// 3.1 获取评论作者信息
+	// 4. 批量获取用户信息
+	// 4.1 获取评论作者信息
 	users, err := s.userRepository.GetUsersByIDs(ctx, &userIDs)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户信息失败: %v", err)
 	}
-	// 3.2 获取被回复用户信息
+	// 4.2 获取被回复用户信息
 	toUsers, err := s.userRepository.GetUsersByIDs(ctx, &toUserIDs)
 	if err != nil {
 		return nil, fmt.Errorf("获取被回复用户信息失败: %v", err)
 	}
 
-	// 4. 获取当前用户对这些评论的点赞状态
+	// 5. 获取当前用户对这些评论的点赞状态
 	likeMap, err := s.postCommentRepository.GetPostCommentLikesByUserID(ctx, request.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户点赞状态失败: %v", err)
 	}
 
-	// 5. 组装回复数据
-	replys := make([]*dto.ReplyItem, len(comments))
+	// 6. 组装回复数据
+	replys := make([]*dto.ReplyItem, 0, len(comments))
 	for i, comment := range comments {
 		// 构建每条回复的完整信息
-		replys[i] = &dto.ReplyItem{
+		replys = append(replys, &dto.ReplyItem{
 			ID:      comment.CommentID, // 回复ID
 			Content: comment.Content,   // 回复内容
 			// 回复作者信息
@@ -417,12 +434,42 @@ func (s *PostServiceImpl) GetPostCommentsByRootID(ctx context.Context, request *
 			ReplyTo: dto.UserInfo{
 				Username: (*toUsers)[i].Username,
 			},
-		}
+		})
 	}
 
 	return replys, nil
 }
 
+// getHiddenUserIDs 获取浏览评论时需要隐藏的用户,即当前用户拉黑或静音的用户
+// 参数:
+// - ctx: 上下文,用于传递请求上下文
+// - request: 获取评论请求,包含当前用户ID和是否隐藏被屏蔽用户的评论
+// 返回:
+// - []int: 需要隐藏的用户ID列表,未开启隐藏时为空
+// - error: 获取屏蔽列表过程中的错误信息
+func (s *PostServiceImpl) getHiddenUserIDs(ctx context.Context, request *dto.GetPostCommentsRequest) ([]int, error) {
+	if !request.HideMuted {
+		return nil, nil
+	}
+
+	hiddenUsers := make(map[int]bool)
+	for _, blockType := range []int8{entity.BlockTypeBlock, entity.BlockTypeMute} {
+		blocked, err := s.blockRepository.GetBlockedUserIDs(ctx, request.UserID, blockType)
+		if err != nil {
+			return nil, fmt.Errorf("获取屏蔽列表失败: %v", err)
+		}
+		for userID := range blocked {
+			hiddenUsers[userID] = true
+		}
+	}
+
+	hiddenUserIDs := make([]int, 0, len(hiddenUsers))
+	for userID := range hiddenUsers {
+		hiddenUserIDs = append(hiddenUserIDs, userID)
+	}
+	return hiddenUserIDs, nil
+}
+
 // SubmitComment 提交评论或回复
 // 参数:
 // - ctx: 上下文,用于传递请求上下文
@@ -462,6 +509,43 @@ func (s *PostServiceImpl) SubmitComment(ctx context.Context, request *dto.Submit
 // - *dto.SubmitPostReplyResponse: 提交回复的响应数据,包含状态码等信息
 // - error: 提交回复过程中的错误信息
 func (s *PostServiceImpl) SubmitPostReply(ctx context.Context, request *dto.SubmitPostReplyRequest) (*dto.SubmitPostReplyResponse, error) {
+	if request.ParentID == nil {
+		return nil, errors.New("被回复的评论不能为空")
+	}
+
+	// 被回复的用户以数据库中父评论的作者为准,不信任客户端传入的用户ID
+	// 父评论可能是当前用户刚发表的评论,此时需先将虚拟ID转换为真实ID
+	parentID := *request.ParentID
+	if realID, err := s.postCommentRepository.GetCommentVirtualID(ctx, request.UserID, parentID); err == nil {
+		parentID = realID
+	}
+	parentAuthorID, err := s.postCommentRepository.GetCommentUserIDByCommentID(ctx, nil, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("获取被回复的评论失败: %v", err)
+	}
+	if request.ToUserID != nil && *request.ToUserID != parentAuthorID {
+		return nil, errors.New("被回复的用户与评论作者不一致")
+	}
+
+	post, err := s.postRepository.GetPostByID(ctx, request.PostID)
+	if err != nil {
+		return nil, fmt.Errorf("获取帖子失败: %v", err)
+	}
+
+	// 被回复的用户或帖子作者拉黑了当前用户时不允许回复
+	for _, authorID := range []int{parentAuthorID, post.UserID} {
+		if authorID == request.UserID {
+			continue
+		}
+		blocked, err := s.blockRepository.IsBlocked(ctx, authorID, request.UserID, entity.BlockTypeBlock)
+		if err != nil {
+			return nil, fmt.Errorf("获取拉黑状态失败: %v", err)
+		}
+		if blocked {
+			return nil, errors.New("对方已将你拉黑,无法回复")
+		}
+	}
+
 	reply := &entity.PostComment{
 		PostID:    request.PostID,
 		CommentID: request.CommentID,
@@ -470,7 +554,7 @@ func (s *PostServiceImpl) SubmitPostReply(ctx context.Context, request *dto.Subm
 		CreatedAt: request.CreatedAt,
 		ParentID:  request.ParentID,
 		RootID:    request.RootID,
-		ToUserID:  request.ToUserID,
+		ToUserID:  &parentAuthorID,
 		Level:     2,
 		Status:    1,
 	}
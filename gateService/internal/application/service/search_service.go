@@ -2,32 +2,88 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"gateService/internal/domain/entity"
 	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/search"
 	"gateService/internal/interfaces/dto"
+	"gateService/pkg/logger"
+	"gateService/pkg/mq/nsqpool"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// 搜索结果每页数量
+	searchPageSize = 10
+	// 输入提示默认返回数量
+	defaultSuggestLimit = 10
+	// 热门搜索默认返回数量
+	defaultTrendingLimit = 10
+	// 热门搜索词的最少搜索用户数
+	trendingMinUsers = 3
+	// 无结果搜索词每页数量
+	zeroResultPageSize = 50
+	// 参与统计的搜索词最大长度,更长的搜索词不统计
+	maxStatQueryLength = 50
+	// 搜索行为的消息队列主题
+	searchQueryTopic = "search_query_channel"
 )
 
 type SearchServiceImpl struct {
-	videoRepo repository.VideoRepository
+	videoRepo           repository.VideoRepository
+	searchAnalyticsRepo repository.SearchAnalyticsRepository
+	videoIndex          *search.VideoIndex
+	suggestIndex        *search.SuggestIndex
+	producerPool        *nsqpool.ProducerPool
 }
 
-func NewSearchServiceImpl(videoRepo repository.VideoRepository) *SearchServiceImpl {
-	return &SearchServiceImpl{videoRepo: videoRepo}
+func NewSearchServiceImpl(videoRepo repository.VideoRepository, searchAnalyticsRepo repository.SearchAnalyticsRepository, videoIndex *search.VideoIndex, suggestIndex *search.SuggestIndex, producerPool *nsqpool.ProducerPool) *SearchServiceImpl {
+	return &SearchServiceImpl{
+		videoRepo:           videoRepo,
+		searchAnalyticsRepo: searchAnalyticsRepo,
+		videoIndex:          videoIndex,
+		suggestIndex:        suggestIndex,
+		producerPool:        producerPool,
+	}
 }
 
 func (s *SearchServiceImpl) SearchVideos(ctx context.Context, request *dto.SearchRequest) (*dto.SearchResponse, error) {
-	animes, err := s.videoRepo.GetVideosByVideoName(ctx, request.Query)
-	if err != nil {
-		return nil, fmt.Errorf("获取搜索列表失败: %v", err)
-	}
-	searchAnimes := make([]*dto.SearchAnime, 0, len(animes))
-	for _, anime := range animes {
-		searchAnimes = append(searchAnimes, &dto.SearchAnime{
-			VideoID:  anime.ID,
-			Title:    anime.Name,
-			CoverUrl: anime.CoverImageUrl,
-		})
+	searchAnimes := make([]*dto.SearchAnime, 0, searchPageSize)
+	total := 0
+
+	// 检索索引未完成构建时回退到按名称模糊查询
+	if !s.videoIndex.Ready() {
+		animes, err := s.videoRepo.GetVideosByVideoName(ctx, request.Query)
+		if err != nil {
+			return nil, fmt.Errorf("获取搜索列表失败: %v", err)
+		}
+		for _, anime := range animes {
+			searchAnimes = append(searchAnimes, &dto.SearchAnime{
+				VideoID:  anime.ID,
+				Title:    anime.Name,
+				CoverUrl: anime.CoverImageUrl,
+			})
+		}
+		total = len(animes)
+	} else {
+		var docs []*entity.VideoSearchDocument
+		docs, total = s.videoIndex.Search(request.Query, 0, searchPageSize)
+		for _, doc := range docs {
+			searchAnimes = append(searchAnimes, &dto.SearchAnime{
+				VideoID:  doc.VideoID,
+				Title:    doc.Name,
+				CoverUrl: doc.CoverImageUrl,
+			})
+		}
 	}
+
+	s.publishSearch(ctx, request.UserID, request.Query, total, entity.SearchSourceBox)
+
 	return &dto.SearchResponse{
 		Code:   200,
 		Animes: searchAnimes,
@@ -35,11 +91,35 @@ func (s *SearchServiceImpl) SearchVideos(ctx context.Context, request *dto.Searc
 }
 
 func (s *SearchServiceImpl) SearchVideosDetail(ctx context.Context, request *dto.SearchDetailRequest) (*dto.SearchDetailResponse, error) {
-	animes, err := s.videoRepo.GetVideosALLEpisodesByVideoName(ctx, request.Params, request.Page)
+	if request.Page < 1 {
+		request.Page = 1
+	}
+
+	var animes []*entity.Video
+	var err error
+	total := 0
+	if !s.videoIndex.Ready() {
+		animes, err = s.videoRepo.GetVideosALLEpisodesByVideoName(ctx, request.Params, request.Page)
+		total = len(animes)
+	} else {
+		// 按索引中的相关度排序,再查询当前页视频的详情和剧集
+		var docs []*entity.VideoSearchDocument
+		docs, total = s.videoIndex.Search(request.Params, (request.Page-1)*searchPageSize, searchPageSize)
+		videoIDs := make([]int, 0, len(docs))
+		for _, doc := range docs {
+			videoIDs = append(videoIDs, doc.VideoID)
+		}
+		animes, err = s.videoRepo.GetVideosWithEpisodesByIDs(ctx, videoIDs)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("获取搜索详情失败: %v", err)
 	}
 
+	// 翻页属于同一次搜索,只统计第一页
+	if request.Page == 1 {
+		s.publishSearch(ctx, request.UserID, request.Params, total, entity.SearchSourceDetail)
+	}
+
 	videoIDs := make([]int, 0, len(animes))
 	for _, anime := range animes {
 		videoIDs = append(videoIDs, anime.ID)
@@ -68,3 +148,86 @@ func (s *SearchServiceImpl) SearchVideosDetail(ctx context.Context, request *dto
 		Animes: searchDetailAnimes,
 	}, nil
 }
+
+func (s *SearchServiceImpl) SuggestVideos(ctx context.Context, request *dto.SearchSuggestRequest) (*dto.SearchSuggestResponse, error) {
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+
+	videos := s.suggestIndex.Suggest(request.Query, limit)
+	animes := make([]*dto.SearchAnime, 0, len(videos))
+	for _, video := range videos {
+		animes = append(animes, &dto.SearchAnime{
+			VideoID:  video.VideoID,
+			Title:    video.Name,
+			CoverUrl: video.CoverImageUrl,
+		})
+	}
+	return &dto.SearchSuggestResponse{
+		Code:   200,
+		Animes: animes,
+	}, nil
+}
+
+func (s *SearchServiceImpl) GetTrendingSearches(ctx context.Context, request *dto.SearchTrendingRequest) (*dto.SearchTrendingResponse, error) {
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultTrendingLimit
+	}
+
+	queries, err := s.searchAnalyticsRepo.GetTrendingQueries(ctx, limit, trendingMinUsers)
+	if err != nil {
+		return nil, fmt.Errorf("获取热门搜索失败: %v", err)
+	}
+	return &dto.SearchTrendingResponse{
+		Code:    200,
+		Queries: queries,
+	}, nil
+}
+
+func (s *SearchServiceImpl) GetZeroResultSearches(ctx context.Context, request *dto.SearchZeroResultRequest) (*dto.SearchZeroResultResponse, error) {
+	queries, total, err := s.searchAnalyticsRepo.GetZeroResultQueries(ctx, (request.Page-1)*zeroResultPageSize, zeroResultPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("获取无结果搜索词失败: %v", err)
+	}
+	return &dto.SearchZeroResultResponse{
+		Code:    200,
+		Total:   total,
+		Queries: queries,
+	}, nil
+}
+
+// publishSearch 发布搜索行为消息,用于统计热门搜索词和无结果搜索词
+// 搜索框输入过程中的实时搜索同样发布,由消费者按搜索入口决定是否计入热门搜索
+// 搜索结果已返回,发布失败只记录日志
+func (s *SearchServiceImpl) publishSearch(ctx context.Context, userID int, query string, resultCount int, source string) {
+	query = normalizeStatQuery(query)
+	if query == "" {
+		return
+	}
+
+	eventJson, err := json.Marshal(&entity.SearchQueryEvent{
+		Query:       query,
+		UserID:      userID,
+		ResultCount: resultCount,
+		Source:      source,
+		SearchedAt:  time.Now().Unix(),
+	})
+	if err == nil {
+		err = s.producerPool.Publish(ctx, searchQueryTopic, eventJson)
+	}
+	if err != nil {
+		logger.Log.Warn("发布搜索消息失败", zap.String("query", query), zap.Error(err))
+	}
+}
+
+// normalizeStatQuery 去除首尾空白、合并连续空白并转为小写,使同一搜索词只统计一次
+// 过长的搜索词不参与统计,返回空字符串
+func normalizeStatQuery(query string) string {
+	query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if utf8.RuneCountInString(query) > maxStatQueryLength {
+		return ""
+	}
+	return query
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateService/internal/domain/repository"
+	"gateService/internal/interfaces/dto"
+)
+
+type SessionServiceImpl struct {
+	tokenRepository repository.TokenRepository
+}
+
+func NewSessionServiceImpl(tokenRepository repository.TokenRepository) *SessionServiceImpl {
+	return &SessionServiceImpl{
+		tokenRepository: tokenRepository,
+	}
+}
+
+func (s *SessionServiceImpl) ListSessions(ctx context.Context, request *dto.ListSessionsRequest) (*dto.ListSessionsResponse, error) {
+	sessions, err := s.tokenRepository.ListSessions(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取登录会话失败: %v", err)
+	}
+
+	for _, session := range sessions {
+		session.Current = session.SessionID == request.CurrentSessionID
+	}
+
+	return &dto.ListSessionsResponse{
+		Code:     200,
+		Sessions: sessions,
+	}, nil
+}
+
+func (s *SessionServiceImpl) RevokeSession(ctx context.Context, request *dto.RevokeSessionRequest) (*dto.RevokeSessionResponse, error) {
+	if request.SessionID == request.CurrentSessionID {
+		return nil, errors.New("不能移除当前会话,请使用退出登录")
+	}
+
+	// 会话ID仅在用户自己的会话集合中查找,防止移除他人会话
+	sessions, err := s.tokenRepository.ListSessions(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取登录会话失败: %v", err)
+	}
+	found := false
+	for _, session := range sessions {
+		if session.SessionID == request.SessionID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.New("会话不存在或已失效")
+	}
+
+	if err := s.tokenRepository.RevokeRefreshFamily(ctx, request.UserID, request.SessionID); err != nil {
+		return nil, fmt.Errorf("移除登录会话失败: %v", err)
+	}
+
+	return &dto.RevokeSessionResponse{
+		Code:    200,
+		Message: "会话已移除",
+	}, nil
+}
+
+func (s *SessionServiceImpl) RevokeOtherSessions(ctx context.Context, request *dto.RevokeOtherSessionsRequest) (*dto.RevokeOtherSessionsResponse, error) {
+	if request.CurrentSessionID == "" {
+		return nil, errors.New("当前登录状态不支持会话管理,请重新登录")
+	}
+
+	revoked, err := s.tokenRepository.RevokeOtherRefreshFamilies(ctx, request.UserID, request.CurrentSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("移除其他会话失败: %v", err)
+	}
+
+	return &dto.RevokeOtherSessionsResponse{
+		Code:    200,
+		Message: "已移除其他全部会话",
+		Revoked: revoked,
+	}, nil
+}
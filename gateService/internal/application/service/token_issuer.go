@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/pkg/useragent"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 会话中保存的User-Agent最大长度
+const maxSessionUserAgentLength = 512
+
+// tokenIssuer 会话令牌签发器
+// 密码登录、两步验证登录和第三方登录共用,保证各登录方式建立的会话一致
+type tokenIssuer struct {
+	jwtManager      *auth.JWTManager
+	cookieManager   *auth.CookieManager
+	tokenRepository repository.TokenRepository
+}
+
+func newTokenIssuer(jwtManager *auth.JWTManager, cookieManager *auth.CookieManager, tokenRepository repository.TokenRepository) *tokenIssuer {
+	return &tokenIssuer{
+		jwtManager:      jwtManager,
+		cookieManager:   cookieManager,
+		tokenRepository: tokenRepository,
+	}
+}
+
+// issueTokens 签发访问令牌和属于familyID家族的新刷新令牌,并写入Cookie
+// 新登录时familyID为新生成的家族ID,此时同时在Redis中创建令牌家族,并记录登录设备作为会话信息
+func (i *tokenIssuer) issueTokens(ctx context.Context, userInfo *entity.UserInfo, familyID string) error {
+	session := &entity.Session{
+		SessionID: familyID,
+		UserID:    userInfo.UserID,
+	}
+	if c, ok := ctx.(*gin.Context); ok {
+		session.UserAgent = c.Request.UserAgent()
+		session.IP = c.ClientIP()
+	}
+	if len(session.UserAgent) > maxSessionUserAgentLength {
+		session.UserAgent = session.UserAgent[:maxSessionUserAgentLength]
+	}
+	session.Device = useragent.Parse(session.UserAgent)
+
+	tokenID := uuid.New().String()
+	if err := i.tokenRepository.CreateRefreshFamily(ctx, session, tokenID, i.jwtManager.GetRefreshTokenExpireTime()); err != nil {
+		return fmt.Errorf("创建刷新令牌家族失败: %v", err)
+	}
+	return i.setTokenCookies(ctx, userInfo, familyID, tokenID)
+}
+
+// setTokenCookies 生成访问令牌和刷新令牌并写入Cookie
+func (i *tokenIssuer) setTokenCookies(ctx context.Context, userInfo *entity.UserInfo, familyID, tokenID string) error {
+	token, err := i.jwtManager.GenerateToken(userInfo, familyID)
+	if err != nil {
+		return fmt.Errorf("生成token失败: %v", err)
+	}
+
+	refreshToken, err := i.jwtManager.GenerateRefreshToken(userInfo, familyID, tokenID)
+	if err != nil {
+		return fmt.Errorf("生成刷新令牌失败: %v", err)
+	}
+
+	if c, ok := ctx.(*gin.Context); ok {
+		i.cookieManager.SetTokenCookie(c, token)
+		i.cookieManager.SetRefreshTokenCookie(c, refreshToken)
+	}
+	return nil
+}
@@ -2,17 +2,27 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"gateService/internal/domain/entity"
 	"gateService/internal/domain/repository"
+	domainService "gateService/internal/domain/service"
 	"gateService/internal/infrastructure/config"
 	"gateService/internal/infrastructure/middleware/auth"
 	"gateService/internal/interfaces/dto"
+	"gateService/pkg/identity"
+	"gateService/pkg/logger"
+	"gateService/pkg/mailer"
+	"gateService/pkg/monitor"
 	"gateService/pkg/mq/nsqpool"
 	"gateService/pkg/password"
 	"io"
 	"math"
+	"math/big"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -23,6 +33,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 // accountConfig 账户相关配置
@@ -30,26 +41,78 @@ type accountConfig struct {
 	regInterval time.Duration // 注册间隔时间,用于限制注册频率
 	ttl         time.Duration // 账户生存时间,用于临时账户过期控制
 	redisPrefix string        // redis缓存前缀
+
+	convertedPrefix string        // 体验账号已转正标记的redis缓存前缀
+	convertedTTL    time.Duration // 已转正标记保留时间,需覆盖延迟删除消息的最晚投递时间
+
+	codeTTL            time.Duration // 验证码有效期
+	codeInterval       time.Duration // 同一邮箱发送验证码的最小间隔
+	codeIntervalPrefix string        // 验证码发送间隔的redis缓存前缀
+	codeIPWindow       time.Duration // 同一IP发送次数的统计窗口
+	codeIPLimit        int64         // 同一IP在统计窗口内允许发送的最大次数
+	codeMaxAttempts    int           // 单个验证码允许的最大校验次数
+
+	resetTTL            time.Duration // 密码重置令牌有效期
+	resetInterval       time.Duration // 同一邮箱申请密码重置的最小间隔
+	resetIntervalPrefix string        // 密码重置申请间隔的redis缓存前缀
+}
+
+// loginConfig 登录防暴力破解相关配置
+type loginConfig struct {
+	failWindow     time.Duration // 失败次数统计窗口
+	backoffAfter   int           // 同一账号在同一IP连续失败达到该次数后开始指数退避
+	backoffBase    time.Duration // 退避基础时长,每多失败一次翻倍
+	backoffMax     time.Duration // 退避最大时长
+	lockAfter      int           // 同一账号在同一IP连续失败达到该次数后临时锁定
+	lockDuration   time.Duration // 账号在该IP的锁定时长
+	ipFailLimit    int           // 同一IP在统计窗口内允许的最大失败次数
+	ipLockDuration time.Duration // IP限流时长
+	redisPrefix    string        // redis缓存前缀
+
+	accountLockAfter    int           // 账号在所有IP合计失败达到该次数后临时锁定账号
+	accountLockDuration time.Duration // 账号锁定时长,锁定期间仅已登录过的设备可以登录
+	deviceTTL           time.Duration // 登录设备记录的有效期
 }
 
+// 验证码使用场景
+const verificationSceneRegister = "register"
+
 type UserServiceImpl struct {
 	accountConfig         *accountConfig
+	loginConfig           *loginConfig
 	storageConfig         *config.StorageConfig
+	mailConfig            *config.MailConfig
 	userRepository        repository.UserRepository
+	tokenRepository       repository.TokenRepository
+	verificationRepo      repository.VerificationRepository
+	mfaRepository         repository.MfaRepository
+	patRepository         repository.PersonalAccessTokenRepository
+	followRepository      repository.FollowRepository
 	postRepository        repository.PostRepository
 	postCommentRepository repository.PostCommentRepository
 	jwtManager            *auth.JWTManager
 	cookieManager         *auth.CookieManager
+	tokenIssuer           *tokenIssuer
+	passwordHasher        *password.Hasher
+	mailer                mailer.Mailer
 	producerPool          *nsqpool.ProducerPool
 }
 
 func NewUserServiceImpl(
 	storageConfig *config.StorageConfig,
+	mailConfig *config.MailConfig,
 	userRepository repository.UserRepository,
+	tokenRepository repository.TokenRepository,
+	verificationRepo repository.VerificationRepository,
+	mfaRepository repository.MfaRepository,
+	patRepository repository.PersonalAccessTokenRepository,
+	followRepository repository.FollowRepository,
 	postRepository repository.PostRepository,
 	postCommentRepository repository.PostCommentRepository,
 	jwtManager *auth.JWTManager,
 	cookieManager *auth.CookieManager,
+	passwordHasher *password.Hasher,
+	mailer mailer.Mailer,
 	producerPool *nsqpool.ProducerPool,
 ) *UserServiceImpl {
 	return &UserServiceImpl{
@@ -57,27 +120,56 @@ func NewUserServiceImpl(
 			regInterval: 24 * time.Hour,
 			ttl:         1 * time.Hour,
 			redisPrefix: "test_account:exist:",
+
+			convertedPrefix: "test_account:converted:",
+			convertedTTL:    24 * time.Hour,
+
+			codeTTL:            10 * time.Minute,
+			codeInterval:       60 * time.Second,
+			codeIntervalPrefix: "verification:interval:",
+			codeIPWindow:       1 * time.Hour,
+			codeIPLimit:        10,
+			codeMaxAttempts:    5,
+
+			resetTTL:            30 * time.Minute,
+			resetInterval:       60 * time.Second,
+			resetIntervalPrefix: "reset_password:interval:",
+		},
+		loginConfig: &loginConfig{
+			failWindow:     15 * time.Minute,
+			backoffAfter:   3,
+			backoffBase:    1 * time.Second,
+			backoffMax:     5 * time.Minute,
+			lockAfter:      10,
+			lockDuration:   15 * time.Minute,
+			ipFailLimit:    30,
+			ipLockDuration: 15 * time.Minute,
+			redisPrefix:    "login:",
+
+			accountLockAfter:    50,
+			accountLockDuration: 30 * time.Minute,
+			deviceTTL:           30 * 24 * time.Hour,
 		},
 		storageConfig:         storageConfig,
+		mailConfig:            mailConfig,
 		userRepository:        userRepository,
+		tokenRepository:       tokenRepository,
+		verificationRepo:      verificationRepo,
+		mfaRepository:         mfaRepository,
+		patRepository:         patRepository,
+		followRepository:      followRepository,
 		postRepository:        postRepository,
 		postCommentRepository: postCommentRepository,
 		jwtManager:            jwtManager,
 		cookieManager:         cookieManager,
+		tokenIssuer:           newTokenIssuer(jwtManager, cookieManager, tokenRepository),
+		passwordHasher:        passwordHasher,
+		mailer:                mailer,
 		producerPool:          producerPool,
 	}
 }
 
 func (s *UserServiceImpl) Register(ctx context.Context, user *dto.RegisterRequest) (*dto.RegisterResponse, error) {
-	// 检查用户是否已存在
-	exist, err := s.userRepository.IsExistUser(ctx, user.Email)
-	if err != nil {
-		return nil, err
-	}
-	if exist {
-		return nil, errors.New("用户已存在")
-	}
-
 	// 检查密码长度
 	if !password.CheckPasswordLength(user.Password) {
 		return nil, errors.New("密码长度必须在8-16位之间")
@@ -93,10 +185,36 @@ func (s *UserServiceImpl) Register(ctx context.Context, user *dto.RegisterReques
 		return nil, errors.New("邮箱格式不正确")
 	}
 
-	// 创建用户
+	// 校验并消费邮箱验证码
+	ok, err := s.verificationRepo.VerifyVerificationCode(ctx, verificationSceneRegister, user.Email, user.Code, s.accountConfig.codeMaxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("校验验证码失败: %v", err)
+	}
+	if !ok {
+		return nil, errors.New("验证码错误或已过期")
+	}
+
+	// 已注册的邮箱收不到验证码,验证码校验通过后再检查,避免未持有验证码时探测邮箱是否已注册
+	exist, err := s.userRepository.IsExistUser(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, errors.New("用户已存在")
+	}
+
+	// 生成密码哈希
+	passwordHash, err := s.passwordHasher.Hash(user.Password)
+	if err != nil {
+		return nil, fmt.Errorf("生成密码哈希失败: %v", err)
+	}
+
+	// 创建用户,默认以邮箱前缀作为用户名
 	_, err = s.userRepository.CreateUser(ctx, &entity.UserInfo{
-		Email:    user.Email,
-		Password: user.Password,
+		Username:  strings.SplitN(user.Email, "@", 2)[0],
+		Email:     user.Email,
+		Password:  passwordHash,
+		AvatarURL: "/src/static/picture/Ellipse_3.png",
 	})
 	if err != nil {
 		return nil, err
@@ -108,26 +226,309 @@ func (s *UserServiceImpl) Register(ctx context.Context, user *dto.RegisterReques
 	}, nil
 }
 
+func (s *UserServiceImpl) SendVerificationCode(ctx context.Context, request *dto.SendVerificationCodeRequest) (*dto.SendVerificationCodeResponse, error) {
+	// 检查邮箱格式
+	if !password.IsValidEmail(request.Email) {
+		return nil, errors.New("邮箱格式不正确")
+	}
+
+	// 同一邮箱发送间隔限制
+	exist, ttl, err := s.userRepository.CheckInRedis(ctx, s.accountConfig.codeIntervalPrefix+request.Email)
+	if err != nil {
+		return nil, fmt.Errorf("检查验证码发送间隔失败: %v", err)
+	}
+	if exist {
+		return nil, fmt.Errorf("验证码发送过于频繁，请%v秒后再试", math.Ceil(ttl.Seconds()))
+	}
+
+	// 同一IP发送次数限制
+	count, err := s.verificationRepo.IncrSendCount(ctx, request.UserIP, s.accountConfig.codeIPWindow)
+	if err != nil {
+		return nil, fmt.Errorf("统计验证码发送次数失败: %v", err)
+	}
+	if count > s.accountConfig.codeIPLimit {
+		return nil, errors.New("验证码发送次数过多，请稍后再试")
+	}
+
+	err = s.userRepository.SetInRedis(ctx, s.accountConfig.codeIntervalPrefix+request.Email, 1, s.accountConfig.codeInterval)
+	if err != nil {
+		return nil, fmt.Errorf("设置验证码发送间隔失败: %v", err)
+	}
+
+	response := &dto.SendVerificationCodeResponse{
+		Code:     200,
+		Message:  "验证码已发送",
+		Interval: int(s.accountConfig.codeInterval.Seconds()),
+	}
+
+	// 邮箱已注册时改为发送账号已存在的提醒邮件,响应与未注册时一致,避免暴露账号是否存在
+	exist, err = s.userRepository.IsExistUser(ctx, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		body := "该邮箱已注册 Zanime 账号,可直接登录。如忘记密码,请在登录页使用找回密码功能。如非本人操作,请忽略本邮件。"
+		if err := s.mailer.Send(ctx, request.Email, "Zanime 账号已存在", body); err != nil {
+			return nil, fmt.Errorf("发送提醒邮件失败: %v", err)
+		}
+		return response, nil
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return nil, fmt.Errorf("生成验证码失败: %v", err)
+	}
+
+	err = s.verificationRepo.SaveVerificationCode(ctx, verificationSceneRegister, request.Email, code, s.accountConfig.codeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("保存验证码失败: %v", err)
+	}
+
+	body := fmt.Sprintf("您的注册验证码为: %s\n验证码%v分钟内有效,请勿泄露给他人。如非本人操作,请忽略本邮件。", code, s.accountConfig.codeTTL.Minutes())
+	if err := s.mailer.Send(ctx, request.Email, "Zanime 注册验证码", body); err != nil {
+		return nil, fmt.Errorf("发送验证码邮件失败: %v", err)
+	}
+
+	return response, nil
+}
+
+// generateVerificationCode 生成6位数字验证码
+func generateVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func (s *UserServiceImpl) ForgotPassword(ctx context.Context, request *dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, error) {
+	response := &dto.ForgotPasswordResponse{
+		Code:    200,
+		Message: "如果该邮箱已注册,重置链接已发送至邮箱",
+	}
+
+	// 同一邮箱申请间隔限制
+	exist, ttl, err := s.userRepository.CheckInRedis(ctx, s.accountConfig.resetIntervalPrefix+request.Email)
+	if err != nil {
+		return nil, fmt.Errorf("检查密码重置申请间隔失败: %v", err)
+	}
+	if exist {
+		return nil, fmt.Errorf("申请过于频繁，请%v秒后再试", math.Ceil(ttl.Seconds()))
+	}
+
+	// 同一IP申请次数限制
+	count, err := s.verificationRepo.IncrSendCount(ctx, request.UserIP, s.accountConfig.codeIPWindow)
+	if err != nil {
+		return nil, fmt.Errorf("统计邮件发送次数失败: %v", err)
+	}
+	if count > s.accountConfig.codeIPLimit {
+		return nil, errors.New("邮件发送次数过多，请稍后再试")
+	}
+
+	err = s.userRepository.SetInRedis(ctx, s.accountConfig.resetIntervalPrefix+request.Email, 1, s.accountConfig.resetInterval)
+	if err != nil {
+		return nil, fmt.Errorf("设置密码重置申请间隔失败: %v", err)
+	}
+
+	// 邮箱未注册时同样返回成功,避免暴露账号是否存在
+	userInfo, err := s.userRepository.GetUserCredentialByEmail(ctx, request.Email)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户失败: %v", err)
+	}
+	if userInfo == nil {
+		return response, nil
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return nil, fmt.Errorf("生成重置令牌失败: %v", err)
+	}
+
+	// 仅保存令牌哈希,Redis泄露时无法直接使用
+	err = s.verificationRepo.SaveResetToken(ctx, hashResetToken(token), userInfo.UserID, s.accountConfig.resetTTL)
+	if err != nil {
+		return nil, fmt.Errorf("保存重置令牌失败: %v", err)
+	}
+
+	link := s.mailConfig.ResetPasswordURL + "?token=" + token
+	body := fmt.Sprintf("您正在重置 Zanime 账号密码,请在%v分钟内点击以下链接完成重置:\n%s\n链接仅可使用一次。如非本人操作,请忽略本邮件。", s.accountConfig.resetTTL.Minutes(), link)
+	if err := s.mailer.Send(ctx, request.Email, "Zanime 密码重置", body); err != nil {
+		return nil, fmt.Errorf("发送重置邮件失败: %v", err)
+	}
+
+	return response, nil
+}
+
+func (s *UserServiceImpl) ResetPassword(ctx context.Context, request *dto.ResetPasswordRequest) (*dto.ResetPasswordResponse, error) {
+	// 检查密码长度
+	if !password.CheckPasswordLength(request.Password) {
+		return nil, errors.New("密码长度必须在8-16位之间")
+	}
+
+	// 检查密码复杂度
+	if !password.CheckPasswordComplexity(request.Password) {
+		return nil, errors.New("密码必须包含大小写字母、数字和特殊字符")
+	}
+
+	// 消费重置令牌,令牌只能使用一次
+	userID, err := s.verificationRepo.ConsumeResetToken(ctx, hashResetToken(request.Token))
+	if err != nil {
+		return nil, fmt.Errorf("校验重置令牌失败: %v", err)
+	}
+	if userID == 0 {
+		return nil, errors.New("重置链接无效或已过期")
+	}
+
+	passwordHash, err := s.passwordHasher.Hash(request.Password)
+	if err != nil {
+		return nil, fmt.Errorf("生成密码哈希失败: %v", err)
+	}
+
+	if err := s.userRepository.UpdateUserPassword(ctx, userID, passwordHash); err != nil {
+		return nil, fmt.Errorf("更新密码失败: %v", err)
+	}
+
+	// 吊销该用户全部已登录会话
+	if err := s.tokenRepository.RevokeUserTokens(ctx, userID, time.Now(), s.jwtManager.GetRefreshTokenExpireTime()); err != nil {
+		return nil, fmt.Errorf("吊销用户令牌失败: %v", err)
+	}
+
+	// 个人访问令牌同样可能已泄露,重置密码时一并删除
+	if err := s.patRepository.DeleteTokensByUserID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("吊销个人访问令牌失败: %v", err)
+	}
+
+	if c, ok := ctx.(*gin.Context); ok {
+		s.cookieManager.ClearTokenCookie(c)
+		s.cookieManager.ClearRefreshTokenCookie(c)
+	}
+
+	return &dto.ResetPasswordResponse{
+		Code:    200,
+		Message: "密码重置成功,请重新登录",
+	}, nil
+}
+
+// generateResetToken 生成密码重置令牌
+func generateResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashResetToken 计算密码重置令牌的哈希值
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func (s *UserServiceImpl) Login(ctx context.Context, user *dto.LoginRequest) (*dto.LoginResponse, error) {
-	userInfo := &entity.UserInfo{
-		Email:    user.Email,
-		Password: user.Password,
+	// 检查IP和账号是否处于限流或锁定状态
+	if err := s.checkLoginThrottle(ctx, user.Email, user.UserIP); err != nil {
+		return nil, err
 	}
-	exist, err := s.userRepository.VerifyUser(ctx, userInfo)
+
+	userInfo, err := s.userRepository.GetUserCredentialByEmail(ctx, user.Email)
 	if err != nil {
 		return nil, fmt.Errorf("验证用户失败: %v", err)
 	}
-	if !exist {
-		return nil, fmt.Errorf("账号或密码错误")
+	if userInfo == nil {
+		return nil, s.recordLoginFailure(ctx, user.Email, user.UserIP)
 	}
 
-	token, err := s.jwtManager.GenerateToken(userInfo)
+	ok, err := s.passwordHasher.Verify(userInfo.Password, user.Password)
 	if err != nil {
-		return nil, fmt.Errorf("生成token失败: %v", err)
+		return nil, fmt.Errorf("验证用户失败: %v", err)
+	}
+	if !ok {
+		return nil, s.recordLoginFailure(ctx, user.Email, user.UserIP)
 	}
 
-	if c, ok := ctx.(*gin.Context); ok {
-		s.cookieManager.SetTokenCookie(c, token)
+	// 历史明文密码或哈希参数已变更时,密码校验通过后按当前配置重新哈希
+	if s.passwordHasher.NeedsRehash(userInfo.Password) {
+		s.rehashPassword(ctx, userInfo.UserID, user.Password)
+	}
+	userInfo.Password = ""
+
+	// 开启两步验证时仅签发待确认令牌,需通过LoginMfa校验验证码后才建立会话
+	mfa, err := s.mfaRepository.GetUserMfa(ctx, userInfo.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取两步验证状态失败: %v", err)
+	}
+	if mfa != nil && mfa.Enabled {
+		mfaToken, err := s.jwtManager.GenerateMfaPendingToken(&entity.UserInfo{UserID: userInfo.UserID, Email: userInfo.Email})
+		if err != nil {
+			return nil, fmt.Errorf("生成两步验证令牌失败: %v", err)
+		}
+		return &dto.LoginResponse{
+			Code:        200,
+			MfaRequired: true,
+			MfaToken:    mfaToken,
+		}, nil
+	}
+
+	s.clearLoginFailures(ctx, user.Email, user.UserIP)
+	s.rememberLoginDevice(ctx, user.Email)
+
+	if err := s.tokenIssuer.issueTokens(ctx, userInfo, uuid.New().String()); err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{
+		Code:     200,
+		UserInfo: userInfo,
+	}, nil
+}
+
+func (s *UserServiceImpl) LoginMfa(ctx context.Context, request *dto.LoginMfaRequest) (*dto.LoginResponse, error) {
+	claims, err := s.jwtManager.ParseMfaPendingToken(request.MfaToken)
+	if err != nil {
+		return nil, fmt.Errorf("两步验证令牌无效或已过期,请重新登录")
+	}
+	email := claims.UserInfo.Email
+
+	// 验证码错误与密码错误共用同一套失败计数,防止暴力枚举验证码
+	if err := s.checkLoginThrottle(ctx, email, request.UserIP); err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.mfaRepository.GetUserMfa(ctx, claims.UserInfo.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取两步验证状态失败: %v", err)
+	}
+	if mfa == nil || !mfa.Enabled {
+		return nil, fmt.Errorf("两步验证状态已变更,请重新登录")
+	}
+
+	ok, err := verifyMfaCode(ctx, s.mfaRepository, mfa, request.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.recordLoginFailure(ctx, email, request.UserIP); err != nil {
+			if _, throttled := err.(*domainService.LoginThrottledError); throttled {
+				return nil, err
+			}
+		}
+		return nil, fmt.Errorf("验证码错误")
+	}
+
+	userInfo, err := s.userRepository.GetUserByID(ctx, claims.UserInfo.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	if userInfo.Status != 1 {
+		return nil, fmt.Errorf("账号不可用")
+	}
+	userInfo.Password = ""
+
+	s.clearLoginFailures(ctx, email, request.UserIP)
+	s.rememberLoginDevice(ctx, email)
+
+	if err := s.tokenIssuer.issueTokens(ctx, userInfo, uuid.New().String()); err != nil {
+		return nil, err
 	}
 
 	return &dto.LoginResponse{
@@ -136,13 +537,275 @@ func (s *UserServiceImpl) Login(ctx context.Context, user *dto.LoginRequest) (*d
 	}, nil
 }
 
+// clearLoginFailures 登录成功后清除账号在该IP的失败计数,失败时仅记录日志
+func (s *UserServiceImpl) clearLoginFailures(ctx context.Context, email, ip string) {
+	if err := s.userRepository.DeleteInRedis(ctx, s.loginConfig.accountIPKey("fail:", email, ip)); err != nil {
+		logger.Log.Warn("清除登录失败计数失败", zap.String("email", email), zap.String("ip", ip), zap.Error(err))
+	}
+}
+
+// rememberLoginDevice 登录成功后记录当前设备,账号被临时锁定时该设备仍可登录,失败时仅记录日志
+func (s *UserServiceImpl) rememberLoginDevice(ctx context.Context, email string) {
+	c, ok := ctx.(*gin.Context)
+	if !ok {
+		return
+	}
+
+	token, err := s.cookieManager.GetDeviceCookie(c)
+	if err != nil || token == "" {
+		if token, err = identity.GenerateRandomString(32); err != nil {
+			logger.Log.Warn("生成登录设备标识失败", zap.Error(err))
+			return
+		}
+	}
+	if err := s.userRepository.SetInRedis(ctx, s.loginConfig.deviceKey(email, token), 1, s.loginConfig.deviceTTL); err != nil {
+		logger.Log.Warn("记录登录设备失败", zap.String("email", email), zap.Error(err))
+		return
+	}
+	s.cookieManager.SetDeviceCookie(c, token)
+}
+
+// isKnownLoginDevice 判断当前请求是否来自该账号曾经登录成功的设备
+func (s *UserServiceImpl) isKnownLoginDevice(ctx context.Context, email string) bool {
+	c, ok := ctx.(*gin.Context)
+	if !ok {
+		return false
+	}
+	token, err := s.cookieManager.GetDeviceCookie(c)
+	if err != nil || token == "" {
+		return false
+	}
+	exist, _, err := s.userRepository.CheckInRedis(ctx, s.loginConfig.deviceKey(email, token))
+	return err == nil && exist
+}
+
+// accountKey 生成账号的登录限制key
+// 数据库按不区分大小写的方式匹配邮箱,邮箱统一转为小写,避免改变大小写绕过失败计数
+func (c *loginConfig) accountKey(kind, email string) string {
+	return c.redisPrefix + kind + strings.ToLower(strings.TrimSpace(email))
+}
+
+// accountIPKey 生成账号在指定IP的登录限制key
+// 退避只作用于账号与IP的组合,他人在其他IP输错密码不会使账号本人等待
+func (c *loginConfig) accountIPKey(kind, email, ip string) string {
+	return c.accountKey(kind, email) + ":" + ip
+}
+
+// deviceKey 生成账号登录设备的记录key
+func (c *loginConfig) deviceKey(email, token string) string {
+	return c.accountKey("device:", email) + ":" + token
+}
+
+// checkLoginThrottle 检查IP、账号在该IP以及账号本身是否处于限流或锁定状态
+// 账号被锁定时,曾经登录成功的设备不受限制,避免他人通过大量失败登录使账号本人无法登录
+func (s *UserServiceImpl) checkLoginThrottle(ctx context.Context, email, ip string) error {
+	if ip != "" {
+		exist, ttl, err := s.userRepository.CheckInRedis(ctx, s.loginConfig.redisPrefix+"wait:ip:"+ip)
+		if err != nil {
+			return fmt.Errorf("检查IP登录限制失败: %v", err)
+		}
+		if exist {
+			return &domainService.LoginThrottledError{Message: "当前IP登录失败次数过多", RetryAfter: ttl}
+		}
+	}
+
+	exist, ttl, err := s.userRepository.CheckInRedis(ctx, s.loginConfig.accountIPKey("wait:", email, ip))
+	if err != nil {
+		return fmt.Errorf("检查账号登录限制失败: %v", err)
+	}
+	if exist {
+		return &domainService.LoginThrottledError{Message: "该账号登录失败次数过多", RetryAfter: ttl}
+	}
+
+	exist, ttl, err = s.userRepository.CheckInRedis(ctx, s.loginConfig.accountKey("wait:", email))
+	if err != nil {
+		return fmt.Errorf("检查账号登录限制失败: %v", err)
+	}
+	if exist && !s.isKnownLoginDevice(ctx, email) {
+		return &domainService.LoginThrottledError{Message: "该账号登录失败次数过多,已临时锁定", RetryAfter: ttl}
+	}
+	return nil
+}
+
+// recordLoginFailure 记录一次登录失败,按失败次数设置退避/锁定,并返回应告知客户端的错误
+// 同一账号在同一IP连续失败backoffAfter次后按指数退避,达到lockAfter次后锁定该账号在该IP的登录;
+// 同一IP失败次数超过ipFailLimit后对该IP单独限流,防止针对多个账号的撞库;
+// 账号在所有IP合计失败达到accountLockAfter次后临时锁定账号,防止从大量IP分散猜测密码,锁定期间已登录过的设备仍可登录
+func (s *UserServiceImpl) recordLoginFailure(ctx context.Context, email, ip string) error {
+	cfg := s.loginConfig
+
+	if ip != "" {
+		ipFails, err := s.userRepository.IncrInRedis(ctx, cfg.redisPrefix+"fail:ip:"+ip, cfg.failWindow)
+		if err != nil {
+			return fmt.Errorf("记录IP登录失败次数失败: %v", err)
+		}
+		if ipFails >= cfg.ipFailLimit {
+			if err := s.userRepository.SetInRedis(ctx, cfg.redisPrefix+"wait:ip:"+ip, ipFails, cfg.ipLockDuration); err != nil {
+				return fmt.Errorf("设置IP登录限制失败: %v", err)
+			}
+			if ipFails == cfg.ipFailLimit {
+				monitor.Warning("IP登录失败次数过多已限流: ip=%s 失败次数=%d 限流时长=%v", ip, ipFails, cfg.ipLockDuration)
+			}
+		}
+	}
+
+	accountFails, err := s.userRepository.IncrInRedis(ctx, cfg.accountKey("fail:", email), cfg.failWindow)
+	if err != nil {
+		return fmt.Errorf("记录账号登录失败次数失败: %v", err)
+	}
+	if accountFails >= cfg.accountLockAfter {
+		if err := s.userRepository.SetInRedis(ctx, cfg.accountKey("wait:", email), accountFails, cfg.accountLockDuration); err != nil {
+			return fmt.Errorf("设置账号登录限制失败: %v", err)
+		}
+		if accountFails == cfg.accountLockAfter {
+			monitor.Warning("账号疑似被暴力破解已临时锁定: email=%s 最近来源ip=%s 失败次数=%d 锁定时长=%v", email, ip, accountFails, cfg.accountLockDuration)
+		}
+	}
+
+	fails, err := s.userRepository.IncrInRedis(ctx, cfg.accountIPKey("fail:", email, ip), cfg.failWindow)
+	if err != nil {
+		return fmt.Errorf("记录账号登录失败次数失败: %v", err)
+	}
+
+	var wait time.Duration
+	switch {
+	case fails >= cfg.lockAfter:
+		wait = cfg.lockDuration
+		if fails == cfg.lockAfter {
+			monitor.Warning("账号在该IP登录失败次数过多已锁定: email=%s ip=%s 失败次数=%d 锁定时长=%v", email, ip, fails, wait)
+		}
+	case fails >= cfg.backoffAfter:
+		wait = cfg.backoffBase << (fails - cfg.backoffAfter)
+		if wait > cfg.backoffMax {
+			wait = cfg.backoffMax
+		}
+	default:
+		return fmt.Errorf("账号或密码错误")
+	}
+
+	if err := s.userRepository.SetInRedis(ctx, cfg.accountIPKey("wait:", email, ip), fails, wait); err != nil {
+		return fmt.Errorf("设置账号登录限制失败: %v", err)
+	}
+	return &domainService.LoginThrottledError{Message: "账号或密码错误", RetryAfter: wait}
+}
+
+// rehashPassword 按当前配置重新哈希用户密码,失败时仅记录日志不影响登录
+func (s *UserServiceImpl) rehashPassword(ctx context.Context, userID int, plain string) {
+	passwordHash, err := s.passwordHasher.Hash(plain)
+	if err == nil {
+		err = s.userRepository.UpdateUserPassword(ctx, userID, passwordHash)
+	}
+	if err != nil {
+		logger.Log.Warn("重新哈希用户密码失败", zap.Int("userID", userID), zap.Error(err))
+	}
+}
+
+func (s *UserServiceImpl) RefreshToken(ctx context.Context) (*dto.RefreshTokenResponse, error) {
+	c, ok := ctx.(*gin.Context)
+	if !ok {
+		return nil, fmt.Errorf("context is not a gin context")
+	}
+
+	refreshToken, err := s.cookieManager.GetRefreshTokenCookie(c)
+	if err != nil {
+		return nil, fmt.Errorf("获取刷新令牌失败: %v", err)
+	}
+
+	claims, err := s.jwtManager.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("解析刷新令牌失败: %v", err)
+	}
+
+	// 原子地校验并轮换家族中的当前令牌
+	newTokenID := uuid.New().String()
+	result, err := s.tokenRepository.RotateRefreshToken(ctx, claims.FamilyID, claims.ID, newTokenID, s.jwtManager.GetRefreshTokenExpireTime())
+	if err != nil {
+		return nil, fmt.Errorf("轮换刷新令牌失败: %v", err)
+	}
+
+	switch result {
+	case repository.RefreshTokenReused:
+		// 已轮换的令牌被再次使用,说明令牌可能已泄露,整个家族已被吊销
+		s.cookieManager.ClearTokenCookie(c)
+		s.cookieManager.ClearRefreshTokenCookie(c)
+		logger.Log.Warn("检测到刷新令牌重复使用", zap.Int("userID", claims.UserInfo.UserID), zap.String("familyID", claims.FamilyID))
+		return nil, fmt.Errorf("刷新令牌已被使用,请重新登录")
+	case repository.RefreshFamilyNotFound:
+		s.cookieManager.ClearTokenCookie(c)
+		s.cookieManager.ClearRefreshTokenCookie(c)
+		return nil, fmt.Errorf("刷新令牌已失效,请重新登录")
+	}
+
+	// 重新加载用户信息,使角色等变更体现在新令牌中
+	userInfo, err := s.userRepository.GetUserByID(ctx, claims.UserInfo.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	if userInfo.Status != 1 {
+		// 家族已轮换到未下发的新令牌,吊销失败只记录日志,家族在过期后自动失效
+		if err := s.tokenRepository.RevokeRefreshFamily(ctx, userInfo.UserID, claims.FamilyID); err != nil {
+			logger.Log.Warn("吊销失效账号的刷新令牌失败", zap.Int("userID", userInfo.UserID), zap.String("familyID", claims.FamilyID), zap.Error(err))
+		}
+		s.cookieManager.ClearTokenCookie(c)
+		s.cookieManager.ClearRefreshTokenCookie(c)
+		return nil, fmt.Errorf("账号已失效,请重新登录")
+	}
+	userInfo.Password = ""
+
+	if err := s.tokenIssuer.setTokenCookies(ctx, userInfo, claims.FamilyID, newTokenID); err != nil {
+		return nil, err
+	}
+
+	return &dto.RefreshTokenResponse{
+		Code:    200,
+		Message: "令牌刷新成功",
+	}, nil
+}
+
 func (s *UserServiceImpl) Logout(ctx context.Context) error {
 	if c, ok := ctx.(*gin.Context); ok {
+		// 将当前访问令牌加入黑名单直至过期
+		if claims, ok := c.MustGet("UserInfo").(*auth.CustomClaims); ok && claims.ExpiresAt != nil {
+			if err := s.tokenRepository.DenyToken(ctx, claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+				return fmt.Errorf("吊销访问令牌失败: %v", err)
+			}
+		}
+
+		// 吊销当前登录对应的刷新令牌家族
+		if refreshToken, err := s.cookieManager.GetRefreshTokenCookie(c); err == nil {
+			if claims, err := s.jwtManager.ParseRefreshToken(refreshToken); err == nil {
+				if err := s.tokenRepository.RevokeRefreshFamily(ctx, claims.UserInfo.UserID, claims.FamilyID); err != nil {
+					return fmt.Errorf("吊销刷新令牌失败: %v", err)
+				}
+			}
+		}
 		s.cookieManager.ClearTokenCookie(c)
+		s.cookieManager.ClearRefreshTokenCookie(c)
 	}
 	return nil
 }
 
+func (s *UserServiceImpl) LogoutAll(ctx context.Context) error {
+	c, ok := ctx.(*gin.Context)
+	if !ok {
+		return fmt.Errorf("context is not a gin context")
+	}
+
+	userID := c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+	if err := s.tokenRepository.RevokeUserTokens(ctx, userID, time.Now(), s.jwtManager.GetRefreshTokenExpireTime()); err != nil {
+		return fmt.Errorf("吊销用户令牌失败: %v", err)
+	}
+
+	// 个人访问令牌同样可以访问账号,退出全部设备时一并删除,与重置密码一致
+	if err := s.patRepository.DeleteTokensByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("吊销个人访问令牌失败: %v", err)
+	}
+
+	s.cookieManager.ClearTokenCookie(c)
+	s.cookieManager.ClearRefreshTokenCookie(c)
+	return nil
+}
+
 func (s *UserServiceImpl) VerifyUser(ctx context.Context, user *dto.VerifyUserRequest) (*dto.VerifyUserResponse, error) {
 	if c, ok := ctx.(*gin.Context); ok {
 		userInfo := c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo
@@ -229,17 +892,21 @@ func (s *UserServiceImpl) UpdateUserProfile(ctx context.Context, user *dto.Updat
 
 func (s *UserServiceImpl) GetUserStats(ctx context.Context, user *dto.UserStatsRequest) (*dto.UserStatsResponse, error) {
 	var (
-		postCount     int
-		favoriteCount int
-		commentCount  int
-		postErr       error
-		favoriteErr   error
-		commentErr    error
+		postCount      int
+		favoriteCount  int
+		commentCount   int
+		followingCount int
+		followerCount  int
+		postErr        error
+		favoriteErr    error
+		commentErr     error
+		followingErr   error
+		followerErr    error
 	)
 
 	// 使用WaitGroup等待所有goroutine完成
 	var wg sync.WaitGroup
-	wg.Add(3)
+	wg.Add(5)
 
 	// 并发获取用户发布的帖子数量
 	go func() {
@@ -259,6 +926,18 @@ func (s *UserServiceImpl) GetUserStats(ctx context.Context, user *dto.UserStatsR
 		commentCount, commentErr = s.postCommentRepository.GetUserCommentCount(ctx, user.UserID)
 	}()
 
+	// 并发获取用户关注数量
+	go func() {
+		defer wg.Done()
+		followingCount, followingErr = s.followRepository.GetFollowingCount(ctx, user.UserID)
+	}()
+
+	// 并发获取用户粉丝数量
+	go func() {
+		defer wg.Done()
+		followerCount, followerErr = s.followRepository.GetFollowerCount(ctx, user.UserID)
+	}()
+
 	// 等待所有goroutine完成
 	wg.Wait()
 
@@ -272,11 +951,18 @@ func (s *UserServiceImpl) GetUserStats(ctx context.Context, user *dto.UserStatsR
 	if commentErr != nil {
 		return nil, fmt.Errorf("获取用户评论数量失败: %v", commentErr)
 	}
+	if followingErr != nil {
+		return nil, fmt.Errorf("获取用户关注数量失败: %v", followingErr)
+	}
+	if followerErr != nil {
+		return nil, fmt.Errorf("获取用户粉丝数量失败: %v", followerErr)
+	}
 
 	response := &dto.UserStatsResponse{
 		Code: 200,
 	}
-	response.Data.FollowingCount = 0
+	response.Data.FollowingCount = followingCount
+	response.Data.FollowerCount = followerCount
 	response.Data.PostCount = postCount
 	response.Data.FavoritePostCount = favoriteCount
 	response.Data.CommentCount = commentCount
@@ -399,6 +1085,24 @@ func (s *UserServiceImpl) GetUserNotifications(ctx context.Context, user *dto.Us
 	}, nil
 }
 
+func (s *UserServiceImpl) UpdateUserRole(ctx context.Context, request *dto.UpdateUserRoleRequest) (*dto.UpdateUserRoleResponse, error) {
+	if c, ok := ctx.(*gin.Context); ok {
+		// 禁止管理员修改自己的角色,避免误操作导致无管理员可用
+		if c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID == request.UserID {
+			return nil, fmt.Errorf("不能修改自己的角色")
+		}
+	}
+
+	if err := s.userRepository.UpdateUserRole(ctx, request.UserID, request.Role); err != nil {
+		return nil, fmt.Errorf("修改用户角色失败: %v", err)
+	}
+
+	return &dto.UpdateUserRoleResponse{
+		Code:    200,
+		Message: "用户角色修改成功",
+	}, nil
+}
+
 func (s *UserServiceImpl) GetTestAccount(ctx context.Context, user *dto.TestAccountRequest) (*dto.TestAccountResponse, error) {
 	exist, ttl, err := s.userRepository.CheckInRedis(ctx, s.accountConfig.redisPrefix+user.UserIP)
 	if err != nil {
@@ -412,10 +1116,15 @@ func (s *UserServiceImpl) GetTestAccount(ctx context.Context, user *dto.TestAcco
 	uuid := strings.ReplaceAll(uuid.New().String(), "-", "")[:6]
 	timestamp := strconv.FormatInt(time.Now().UnixNano(), 10)
 	timestamp = timestamp[len(timestamp)-4:]
+	plainPassword := timestamp + uuid
+	passwordHash, err := s.passwordHasher.Hash(plainPassword)
+	if err != nil {
+		return nil, fmt.Errorf("生成密码哈希失败: %v", err)
+	}
 	testAccount := &entity.UserInfo{
 		Username:  fmt.Sprintf("%s_%s", uuid, timestamp),
-		Email:     fmt.Sprintf("%s_%s@example.com", uuid, timestamp),
-		Password:  timestamp + uuid,
+		Email:     fmt.Sprintf("%s_%s%s", uuid, timestamp, entity.TestAccountEmailSuffix),
+		Password:  passwordHash,
 		AvatarURL: "/src/static/picture/Ellipse_3.png",
 	}
 
@@ -448,7 +1157,102 @@ func (s *UserServiceImpl) GetTestAccount(ctx context.Context, user *dto.TestAcco
 		Code: 200,
 		Account: &dto.TestAccount{
 			Email:    testAccount.Email,
-			Password: testAccount.Password,
+			Password: plainPassword,
 		},
 	}, nil
 }
+
+func (s *UserServiceImpl) ConvertTestAccount(ctx context.Context, request *dto.ConvertTestAccountRequest) (*dto.ConvertTestAccountResponse, error) {
+	userInfo, err := s.userRepository.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	if userInfo.Status != 1 || !entity.IsTestAccountEmail(userInfo.Email) {
+		return nil, errors.New("当前账号不是体验账号")
+	}
+
+	// 检查邮箱格式,体验账号邮箱后缀不能作为正式邮箱
+	if !password.IsValidEmail(request.Email) || entity.IsTestAccountEmail(request.Email) {
+		return nil, errors.New("邮箱格式不正确")
+	}
+
+	// 检查密码长度
+	if !password.CheckPasswordLength(request.Password) {
+		return nil, errors.New("密码长度必须在8-16位之间")
+	}
+
+	// 检查密码复杂度
+	if !password.CheckPasswordComplexity(request.Password) {
+		return nil, errors.New("密码必须包含大小写字母、数字和特殊字符")
+	}
+
+	// 校验并消费邮箱验证码,验证码通过注册验证码接口发送
+	ok, err := s.verificationRepo.VerifyVerificationCode(ctx, verificationSceneRegister, request.Email, request.Code, s.accountConfig.codeMaxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("校验验证码失败: %v", err)
+	}
+	if !ok {
+		return nil, errors.New("验证码错误或已过期")
+	}
+
+	// 与注册一致,验证码通过后才检查邮箱是否已注册,避免未持有验证码时探测邮箱
+	exist, err := s.userRepository.IsExistUser(ctx, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, errors.New("该邮箱已被注册")
+	}
+
+	passwordHash, err := s.passwordHasher.Hash(request.Password)
+	if err != nil {
+		return nil, fmt.Errorf("生成密码哈希失败: %v", err)
+	}
+
+	// 数据库条件更新与延迟删除互斥,体验账号已被删除时转换失败
+	converted, err := s.userRepository.ConvertTestAccount(ctx, &entity.UserInfo{
+		UserID:   request.UserID,
+		Username: strings.SplitN(request.Email, "@", 2)[0],
+		Email:    request.Email,
+		Password: passwordHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("转换体验账号失败: %v", err)
+	}
+	if !converted {
+		return nil, errors.New("体验账号已过期")
+	}
+
+	// 标记已转正,延迟删除消息到达时直接忽略
+	if err := s.userRepository.SetInRedis(ctx, s.accountConfig.convertedPrefix+strconv.Itoa(request.UserID), 1, s.accountConfig.convertedTTL); err != nil {
+		logger.Log.Warn("设置体验账号转正标记失败", zap.Int("userID", request.UserID), zap.Error(err))
+	}
+
+	// 体验账号密码曾明文下发,转正后吊销此前的全部会话,仅为当前请求签发新会话
+	if c, ok := ctx.(*gin.Context); ok {
+		if claims, ok := c.MustGet("UserInfo").(*auth.CustomClaims); ok && claims.ExpiresAt != nil {
+			if err := s.tokenRepository.DenyToken(ctx, claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+				return nil, fmt.Errorf("吊销访问令牌失败: %v", err)
+			}
+		}
+	}
+	if err := s.tokenRepository.RevokeUserRefreshFamilies(ctx, request.UserID); err != nil {
+		return nil, fmt.Errorf("吊销用户会话失败: %v", err)
+	}
+
+	userInfo, err = s.userRepository.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	userInfo.Password = ""
+
+	if err := s.tokenIssuer.issueTokens(ctx, userInfo, uuid.New().String()); err != nil {
+		return nil, err
+	}
+
+	return &dto.ConvertTestAccountResponse{
+		Code:     200,
+		Message:  "体验账号已转为正式账号",
+		UserInfo: userInfo,
+	}, nil
+}
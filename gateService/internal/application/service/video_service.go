@@ -3,17 +3,26 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"gateService/internal/domain/entity"
 	"gateService/internal/domain/repository"
+	domainService "gateService/internal/domain/service"
 	"gateService/internal/grpc/client/recommend"
 	"gateService/internal/grpc/client/scrapeClient"
 	"gateService/internal/infrastructure/middleware/lock"
 	"gateService/internal/interfaces/dto"
+	"gateService/pkg/logger"
+	"gateService/pkg/monitor"
+	"gateService/pkg/mq/nsqpool"
 	"math/rand/v2"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
 )
 
 // VideoServiceImpl 实现了VideoService接口,提供视频相关的业务功能
@@ -24,6 +33,8 @@ type VideoServiceImpl struct {
 	videoRepositoty    repository.VideoRepository    // 视频仓储接口
 	progressRepository repository.ProgressRepository // 观看进度仓储接口
 
+	healthRepository repository.LinkHealthRepository // 播放地址健康检查仓储接口
+	producerPool     *nsqpool.ProducerPool           // 消息队列生产者池,用于投递下一集预取任务
 }
 
 // NewVideoServiceImpl 创建VideoServiceImpl的新实例
@@ -32,16 +43,20 @@ type VideoServiceImpl struct {
 //   - scrape: 视频爬虫客户端池
 //   - videoRepositoty: 视频仓储实现
 //   - progressRepository: 观看进度仓储实现
+//   - healthRepository: 播放地址健康检查仓储实现
+//   - producerPool: 消息队列生产者池
 //
 // 返回:
 //   - *VideoServiceImpl: 服务实例
-func NewVideoServiceImpl(rdb *redis.Client, scrapeClient *scrapeClient.GRPCClientPool, recommendClient *recommend.GRPCClientPool, videoRepositoty repository.VideoRepository, progressRepository repository.ProgressRepository) *VideoServiceImpl {
+func NewVideoServiceImpl(rdb *redis.Client, scrapeClient *scrapeClient.GRPCClientPool, recommendClient *recommend.GRPCClientPool, videoRepositoty repository.VideoRepository, progressRepository repository.ProgressRepository, healthRepository repository.LinkHealthRepository, producerPool *nsqpool.ProducerPool) *VideoServiceImpl {
 	return &VideoServiceImpl{
 		rdb:                rdb,
 		scrapeClient:       scrapeClient,
 		recommendClient:    recommendClient,
 		videoRepositoty:    videoRepositoty,
 		progressRepository: progressRepository,
+		healthRepository:   healthRepository,
+		producerPool:       producerPool,
 	}
 }
 
@@ -137,6 +152,7 @@ func (v *VideoServiceImpl) GetVideoFilters(ctx context.Context, request *dto.Get
 }
 
 // GetVideoURL 获取视频播放地址
+// 依次查找Redis、数据库中未过期的爬取结果或管理员指定的地址,都未命中时爬取
 // 参数:
 //   - ctx: 上下文信息,用于控制请求的生命周期
 //   - request: 包含用户ID、视频ID和集数的请求参数
@@ -148,7 +164,7 @@ func (v *VideoServiceImpl) GetVideoURL(ctx context.Context, request *dto.GetVide
 	// 获取用户观看进度
 	progress, err := v.progressRepository.GetUserWatchProgress(ctx, request.UserID, request.VideoID)
 	if err != nil {
-		return v.Response(500, ""), fmt.Errorf("获取用户历史记录失败: %v", err)
+		return v.Response(500, nil), fmt.Errorf("获取用户历史记录失败: %v", err)
 	}
 
 	// 如果请求中没有指定集数,则使用用户上次观看的集数
@@ -156,46 +172,379 @@ func (v *VideoServiceImpl) GetVideoURL(ctx context.Context, request *dto.GetVide
 		request.Episode = progress.Episode
 	}
 
-	// 构造Redis缓存key
-	URLKey := fmt.Sprintf("VideoURL:Video%d:Episode%s", request.VideoID, request.Episode)
-	// 尝试从缓存获取视频URL
-	URL, err := v.videoRepositoty.GetVideoURL(ctx, URLKey)
-	if err != nil && err != redis.Nil {
-		return v.Response(500, ""), fmt.Errorf("获取缓存视频链接失败: %v", err)
+	// 依次从Redis和数据库获取播放源
+	sources, err := v.lookupVideoSources(ctx, request.VideoID, request.Episode)
+	if err != nil {
+		return v.Response(500, nil), err
+	}
+	// 两级缓存都未命中时加锁爬取
+	if len(sources) == 0 {
+		sources, err = v.scrapeVideoSources(ctx, progress, request.VideoID, request.Episode, false)
+		if err != nil {
+			return v.Response(500, nil), err
+		}
+	}
+
+	// 异步预取后续剧集,不影响本次响应
+	go v.prefetchNextEpisodes(request.UserID, request.VideoID, request.Episode)
+
+	return v.Response(200, sources), nil
+}
+
+// prefetchNextEpisodes 投递后续剧集的预取任务,用户播放下一集时可以直接命中缓存
+// 默认预取下一集,看完上一集后立即开始本集的连续观看用户额外预取下下集
+// 预取只是优化,失败时只记录日志
+// 参数:
+//   - userID: 用户ID
+//   - videoID: 视频ID
+//   - episode: 本次播放的集数
+func (v *VideoServiceImpl) prefetchNextEpisodes(userID, videoID int, episode string) {
+	ctx, cancel := context.WithTimeout(context.Background(), videoPrefetchTimeout)
+	defer cancel()
+
+	video, err := v.videoRepositoty.GetVideoInfoWithEposidesByVideoID(ctx, videoID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.Log.Warn("获取视频剧集失败", zap.Int("videoID", videoID), zap.Error(err))
+		}
+		return
+	}
+	index := slices.Index(video.Episodes, episode)
+	if index < 0 || index == len(video.Episodes)-1 {
+		return
+	}
+
+	count := 1
+	if index > 0 {
+		// 每个用户每部视频只有一条观看进度,开始播放本集时进度仍为上一集
+		watched, err := v.progressRepository.GetRecentlyWatchedEpisode(ctx, userID, videoID, bingeWatchWindow)
+		if err != nil {
+			logger.Log.Warn("获取用户最近观看进度失败", zap.Int("userID", userID), zap.Int("videoID", videoID), zap.Error(err))
+		} else if watched == video.Episodes[index-1] {
+			count = 2
+		}
+	}
+
+	for _, next := range video.Episodes[index+1 : min(index+1+count, len(video.Episodes))] {
+		first, err := v.videoRepositoty.MarkVideoPrefetch(ctx, videoPrefetchKey(videoID, next), videoPrefetchWindow)
+		if err != nil {
+			logger.Log.Warn("标记预取任务失败", zap.Int("videoID", videoID), zap.String("episode", next), zap.Error(err))
+			return
+		}
+		if !first {
+			continue
+		}
+
+		jobJson, err := json.Marshal(&entity.VideoScrapeJob{VideoID: videoID, Episode: next, Reason: "prefetch"})
+		if err == nil {
+			err = v.producerPool.Publish(ctx, videoPrefetchTopic, jobJson)
+		}
+		if err != nil {
+			logger.Log.Warn("投递预取任务失败", zap.Int("videoID", videoID), zap.String("episode", next), zap.Error(err))
+		}
+	}
+}
+
+// ReportBrokenLink 上报一集视频的播放地址已失效
+// 同一集在去重窗口内只处理第一次上报: 删除缓存并要求爬虫重新爬取,其余上报等待并返回重新爬取的结果
+// 每个用户的上报次数受频率限制,统计窗口内失效次数达到阈值时上报监控服务
+// 参数:
+//   - ctx: 上下文信息
+//   - request: 包含用户ID、视频ID和集数的请求参数
+//
+// 返回:
+//   - *dto.GetVideoURLResponse: 重新爬取后的视频URL响应对象
+//   - error: 可能的错误信息,视频或剧集不存在时返回domainService.ErrVideoEpisodeNotFound,
+//     超过频率限制时返回domainService.ErrVideoReportLimited
+func (v *VideoServiceImpl) ReportBrokenLink(ctx context.Context, request *dto.ReportBrokenLinkRequest) (*dto.GetVideoURLResponse, error) {
+	video, err := v.videoRepositoty.GetVideoInfoWithEposidesByVideoID(ctx, request.VideoID)
+	if err == sql.ErrNoRows {
+		return v.Response(400, nil), domainService.ErrVideoEpisodeNotFound
 	}
-	// 如果缓存中存在,直接返回
-	if URL != "" {
-		return v.Response(200, URL), nil
+	if err != nil {
+		return v.Response(500, nil), fmt.Errorf("获取视频剧集失败: %v", err)
+	}
+	if !slices.Contains(video.Episodes, request.Episode) {
+		return v.Response(400, nil), domainService.ErrVideoEpisodeNotFound
 	}
 
+	// 在去重之前限制频率,避免单个用户轮流上报各集触发大量重新爬取
+	reports, err := v.videoRepositoty.IncrVideoReportCount(ctx, brokenLinkRateKey(request.UserID), brokenLinkRateWindow)
+	if err != nil {
+		return v.Response(500, nil), fmt.Errorf("记录上报次数失败: %v", err)
+	}
+	if reports > brokenLinkRateLimit {
+		return v.Response(429, nil), domainService.ErrVideoReportLimited
+	}
+
+	progress, err := v.progressRepository.GetUserWatchProgress(ctx, request.UserID, request.VideoID)
+	if err != nil {
+		return v.Response(500, nil), fmt.Errorf("获取用户历史记录失败: %v", err)
+	}
+
+	first, err := v.videoRepositoty.MarkBrokenLinkReport(ctx, brokenLinkReportKey(request.VideoID, request.Episode), brokenLinkReportWindow)
+	if err != nil {
+		return v.Response(500, nil), fmt.Errorf("记录失效上报失败: %v", err)
+	}
+	// 窗口内已有上报在处理,等待其重新爬取完成后读取缓存
+	if !first {
+		sources, err := v.scrapeVideoSources(ctx, progress, request.VideoID, request.Episode, false)
+		if err != nil {
+			return v.Response(500, nil), err
+		}
+		return v.Response(200, sources), nil
+	}
+
+	count, err := v.videoRepositoty.IncrBrokenLinkCount(ctx, brokenLinkCountKey(request.VideoID, request.Episode), brokenLinkCountWindow)
+	if err != nil {
+		return v.Response(500, nil), fmt.Errorf("记录失效次数失败: %v", err)
+	}
+	if count == brokenLinkAlertThreshold {
+		monitor.Warning("视频播放地址多次失效: video=%d(%s) episode=%s 失效次数=%d 统计窗口=%v", request.VideoID, progress.VideoName, request.Episode, count, brokenLinkCountWindow)
+	}
+
+	sources, err := v.scrapeVideoSources(ctx, progress, request.VideoID, request.Episode, true)
+	if err != nil {
+		monitor.Error("重新爬取失效的视频播放地址失败: video=%d(%s) episode=%s err=%v", request.VideoID, progress.VideoName, request.Episode, err)
+		return v.Response(500, nil), err
+	}
+	return v.Response(200, sources), nil
+}
+
+// RefreshVideoSources 在后台爬取一集视频的播放源并写入缓存,供消息队列中的爬取任务使用
+// 不要求重新爬取时,Redis或数据库中已有可用的播放源则直接返回
+// 参数:
+//   - ctx: 上下文信息
+//   - videoID: 视频ID
+//   - episode: 集数
+//   - replace: 是否删除缓存并要求爬虫重新爬取
+//
+// 返回:
+//   - error: 可能的错误信息
+func (v *VideoServiceImpl) RefreshVideoSources(ctx context.Context, videoID int, episode string, replace bool) error {
+	if !replace {
+		sources, err := v.lookupVideoSources(ctx, videoID, episode)
+		if err != nil {
+			return err
+		}
+		if len(sources) > 0 {
+			return nil
+		}
+	}
+
+	// 不关联具体用户,只需要视频名称、上映时间和地区
+	progress, err := v.progressRepository.GetUserWatchProgress(ctx, 0, videoID)
+	if err != nil {
+		return fmt.Errorf("获取视频信息失败: %v", err)
+	}
+
+	_, err = v.scrapeVideoSources(ctx, progress, videoID, episode, replace)
+	return err
+}
+
+// PrefetchVideoSources 执行下一集预取任务,爬取一集视频的播放源并写入缓存
+// Redis或数据库中已有可用的播放源时直接返回,需要爬取时先占用所有实例共享的预取频率配额
+// 参数:
+//   - ctx: 上下文信息
+//   - videoID: 视频ID
+//   - episode: 集数
+//
+// 返回:
+//   - error: 可能的错误信息,超过频率限制时返回domainService.ErrVideoPrefetchLimited
+func (v *VideoServiceImpl) PrefetchVideoSources(ctx context.Context, videoID int, episode string) error {
+	sources, err := v.lookupVideoSources(ctx, videoID, episode)
+	if err != nil {
+		return err
+	}
+	if len(sources) > 0 {
+		return nil
+	}
+
+	count, err := v.videoRepositoty.IncrVideoPrefetchCount(ctx, videoPrefetchRateKey(time.Now()), videoPrefetchRateWindow)
+	if err != nil {
+		return fmt.Errorf("记录预取次数失败: %v", err)
+	}
+	if count > videoPrefetchRateLimit {
+		return domainService.ErrVideoPrefetchLimited
+	}
+
+	progress, err := v.progressRepository.GetUserWatchProgress(ctx, 0, videoID)
+	if err != nil {
+		return fmt.Errorf("获取视频信息失败: %v", err)
+	}
+
+	// 与用户请求使用同一把锁,用户已在播放该集时等待其爬取结果
+	_, err = v.scrapeVideoSources(ctx, progress, videoID, episode, false)
+	return err
+}
+
+// GetLinkHealthStats 获取各来源站点的播放地址探测统计
+// 参数:
+//   - ctx: 上下文信息
+//   - request: 请求参数
+//
+// 返回:
+//   - *dto.GetLinkHealthStatsResponse: 按可用率从低到高排列的统计
+//   - error: 可能的错误信息
+func (v *VideoServiceImpl) GetLinkHealthStats(ctx context.Context, request *dto.GetLinkHealthStatsRequest) (*dto.GetLinkHealthStatsResponse, error) {
+	stats, err := v.healthRepository.GetLinkHealthStats(ctx)
+	if err != nil {
+		return &dto.GetLinkHealthStatsResponse{Code: 500}, fmt.Errorf("获取播放地址探测统计失败: %v", err)
+	}
+	return &dto.GetLinkHealthStatsResponse{
+		Code:  200,
+		Stats: stats,
+	}, nil
+}
+
+// lookupVideoSources 依次从Redis和数据库查找一集视频的播放源,数据库命中时回填Redis
+// 数据库读取失败只记录日志,按未命中处理,由调用方爬取
+// 参数:
+//   - ctx: 上下文信息
+//   - videoID: 视频ID
+//   - episode: 集数
+//
+// 返回:
+//   - []*entity.VideoSource: 按评分排序的播放源,两级缓存都未命中时为空
+//   - error: 可能的错误信息
+func (v *VideoServiceImpl) lookupVideoSources(ctx context.Context, videoID int, episode string) ([]*entity.VideoSource, error) {
+	URLKey := entity.VideoSourceKey(videoID, episode)
+	sources, err := v.videoRepositoty.GetVideoSources(ctx, URLKey)
+	if err != nil {
+		return nil, fmt.Errorf("获取缓存视频链接失败: %v", err)
+	}
+	if len(sources) > 0 {
+		return sources, nil
+	}
+
+	stored, err := v.videoRepositoty.GetStoredVideoSources(ctx, videoID, episode)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.Log.Warn("获取数据库视频链接失败", zap.Int("videoID", videoID), zap.String("episode", episode), zap.Error(err))
+		}
+		return nil, nil
+	}
+	sources = storedVideoSources(stored)
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	if err := v.videoRepositoty.CacheVideoSources(ctx, URLKey, sources); err != nil {
+		logger.Log.Warn("缓存视频链接失败", zap.String("key", URLKey), zap.Error(err))
+	}
+	return sources, nil
+}
+
+// scrapeVideoSources 在分布式锁内爬取一集视频的播放源并写入Redis和数据库
+// 参数:
+//   - ctx: 上下文信息
+//   - progress: 视频名称、上映时间和地区,用于爬取
+//   - videoID: 视频ID
+//   - episode: 集数
+//   - replace: 是否要求爬虫重新爬取并在成功后替换缓存,为false时优先使用其他请求已写入的缓存
+//
+// 返回:
+//   - []*entity.VideoSource: 按评分排序的播放源
+//   - error: 可能的错误信息
+func (v *VideoServiceImpl) scrapeVideoSources(ctx context.Context, progress *entity.Progress, videoID int, episode string, replace bool) ([]*entity.VideoSource, error) {
+	URLKey := entity.VideoSourceKey(videoID, episode)
+
 	// 构造分布式锁key
-	key := fmt.Sprintf("scrape_lock:%d:%s", request.VideoID, request.Episode)
+	key := fmt.Sprintf("scrape_lock:%d:%s", videoID, episode)
 	redisLock := lock.NewRedisLock(v.rdb, key, nil)
 	// 获取分布式锁,防止并发爬取
 	if err := redisLock.WaitLock(ctx); err != nil {
-		return v.Response(500, ""), fmt.Errorf("获取分布式锁%s失败: %v", key, err)
+		return nil, fmt.Errorf("获取分布式锁%s失败: %v", key, err)
 	}
 	defer redisLock.Unlock(ctx)
 
-	// 双重检查,再次尝试从缓存获取
-	URL, err = v.videoRepositoty.GetVideoURL(ctx, URLKey)
-	if err != nil && err != redis.Nil {
-		return v.Response(500, ""), fmt.Errorf("获取缓存视频链接失败: %v", err)
+	var VideoMsg *scrapeClient.VideoMsg
+	if replace {
+		// 先重新爬取,成功后再替换两级缓存中的播放源,重新爬取失败时保留原有的播放源
+		msg, err := v.scrapeClient.RescrapeVideoUrl(ctx, progress.VideoName, progress.Release, progress.Area, episode)
+		if err != nil {
+			return nil, fmt.Errorf("重新爬取视频链接失败: %v", err)
+		}
+		VideoMsg = msg
+	} else {
+		// 双重检查,再次尝试从缓存获取
+		sources, err := v.videoRepositoty.GetVideoSources(ctx, URLKey)
+		if err != nil {
+			return nil, fmt.Errorf("获取缓存视频链接失败: %v", err)
+		}
+		if len(sources) > 0 {
+			return sources, nil
+		}
+		msg, err := v.scrapeClient.ScrapeVideoUrl(ctx, progress.VideoName, progress.Release, progress.Area, episode)
+		if err != nil {
+			return nil, fmt.Errorf("爬取视频链接失败: %v", err)
+		}
+		VideoMsg = msg
 	}
-	if URL != "" {
-		return v.Response(200, URL), nil
+
+	sources := rankVideoSources(VideoMsg)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("爬取视频链接失败: 未找到可用的播放源")
 	}
 
-	// 爬取视频URL
-	VideoMsg, err := v.scrapeClient.ScrapeVideoUrl(ctx, progress.VideoName, progress.Release, progress.Area, request.Episode)
+	// 在锁内写入缓存,等待同一把锁的请求获取锁后即可读到新地址
+	// 写入会覆盖原有的播放源,替换时写入失败则删除原有的播放源,避免继续返回失效地址
+	if err := v.videoRepositoty.CacheVideoSources(ctx, URLKey, sources); err != nil {
+		logger.Log.Warn("缓存视频链接失败", zap.String("key", URLKey), zap.Error(err))
+		if replace {
+			if err := v.videoRepositoty.DeleteVideoSources(ctx, URLKey); err != nil {
+				logger.Log.Warn("删除缓存视频链接失败", zap.String("key", URLKey), zap.Error(err))
+			}
+		}
+	}
+	// 数据库作为第二级缓存,Redis清空后无需重新爬取
+	if err := v.videoRepositoty.SaveVideoSources(ctx, videoID, episode, sources); err != nil {
+		logger.Log.Warn("保存视频链接失败", zap.Int("videoID", videoID), zap.String("episode", episode), zap.Error(err))
+		if replace {
+			if err := v.videoRepositoty.ClearStoredVideoSources(ctx, videoID, episode); err != nil {
+				logger.Log.Warn("清空保存的视频链接失败", zap.Int("videoID", videoID), zap.String("episode", episode), zap.Error(err))
+			}
+		}
+	}
+	return sources, nil
+}
+
+// ReportVideoSource 上报播放失败的播放源
+// 降低该播放源的评分,使其排在其他播放源之后,并返回重新排序后的播放源
+// 同一用户在去重窗口内对同一播放源只降低一次评分,且每个用户的上报次数受频率限制
+// 参数:
+//   - ctx: 上下文信息
+//   - request: 包含用户ID、视频ID、集数和播放失败地址的请求参数
+//
+// 返回:
+//   - *dto.GetVideoURLResponse: 重新排序后的视频URL响应对象
+//   - error: 可能的错误信息,超过频率限制时返回domainService.ErrVideoReportLimited
+func (v *VideoServiceImpl) ReportVideoSource(ctx context.Context, request *dto.ReportVideoSourceRequest) (*dto.GetVideoURLResponse, error) {
+	count, err := v.videoRepositoty.IncrVideoReportCount(ctx, videoSourceReportRateKey(request.UserID), videoSourceReportRateWindow)
 	if err != nil {
-		return v.Response(500, ""), fmt.Errorf("爬取视频链接失败: %v", err)
+		return v.Response(500, nil), fmt.Errorf("记录上报次数失败: %v", err)
+	}
+	if count > videoSourceReportRateLimit {
+		return v.Response(429, nil), domainService.ErrVideoReportLimited
 	}
 
-	// 异步缓存视频URL
-	go v.videoRepositoty.CacheVideoURL(context.Background(), URLKey, VideoMsg.Url)
+	URLKey := entity.VideoSourceKey(request.VideoID, request.Episode)
+	first, err := v.videoRepositoty.MarkVideoSourceReport(ctx, videoSourceReportKey(request.UserID, request.VideoID, request.Episode, request.URL), videoSourceReportWindow)
+	if err != nil {
+		return v.Response(500, nil), fmt.Errorf("记录播放源上报失败: %v", err)
+	}
+	// 重复上报不再降低评分,避免单个用户把正常的播放源排到最后
+	if first {
+		if _, err := v.videoRepositoty.DemoteVideoSource(ctx, URLKey, request.URL, videoSourcePenalty); err != nil {
+			return v.Response(500, nil), fmt.Errorf("降低播放源评分失败: %v", err)
+		}
+	}
 
-	return v.Response(200, VideoMsg.Url), nil
+	sources, err := v.videoRepositoty.GetVideoSources(ctx, URLKey)
+	if err != nil {
+		return v.Response(500, nil), fmt.Errorf("获取缓存视频链接失败: %v", err)
+	}
+	return v.Response(200, sources), nil
 }
 
 // GetHomeAnimes 获取首页动漫列表
@@ -307,19 +656,28 @@ func (v *VideoServiceImpl) GetHomeAnimes(ctx context.Context, request *dto.GetHo
 // Response 生成视频URL响应
 // 参数:
 //   - code: 状态码
-//   - URL: 视频播放地址
+//   - sources: 按评分排序的播放源,第一个为默认播放源
 //
 // 返回:
 //   - *dto.GetVideoURLResponse: 视频URL响应对象
-func (v *VideoServiceImpl) Response(code int, URL string) *dto.GetVideoURLResponse {
+func (v *VideoServiceImpl) Response(code int, sources []*entity.VideoSource) *dto.GetVideoURLResponse {
+	videoFiles := make([]map[string]string, 0, len(sources))
+	for _, source := range sources {
+		label := source.Quality
+		if label == "" {
+			label = "默认"
+		}
+		videoFiles = append(videoFiles, map[string]string{
+			"file":   source.URL,
+			"label":  label,
+			"type":   source.Format,
+			"origin": source.Origin,
+		})
+	}
+
 	return &dto.GetVideoURLResponse{
-		Code: code,
-		VideoFiles: []map[string]string{
-			{
-				"file":  URL,
-				"label": "720p",
-			},
-		},
+		Code:       code,
+		VideoFiles: videoFiles,
 		PosterPath: "",
 	}
 }
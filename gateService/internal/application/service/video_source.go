@@ -0,0 +1,197 @@
+package service
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/grpc/client/scrapeClient"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 播放失败被上报一次降低的评分,大于任何清晰度的评分,使失败的播放源排在正常播放源之后
+const videoSourcePenalty = 10000
+
+const (
+	// 同一用户对同一播放源的上报在该窗口内只降低一次评分
+	videoSourceReportWindow = 24 * time.Hour
+	// 每个用户在统计窗口内允许的播放失败上报次数
+	videoSourceReportRateLimit  = 30
+	videoSourceReportRateWindow = time.Hour
+)
+
+const (
+	// 同一集的失效上报在该窗口内只处理一次
+	brokenLinkReportWindow = 10 * time.Minute
+	// 失效次数的统计窗口
+	brokenLinkCountWindow = 24 * time.Hour
+	// 统计窗口内失效次数达到该值时上报监控服务
+	brokenLinkAlertThreshold = 3
+	// 每个用户在统计窗口内允许的失效上报次数,失效上报会触发重新爬取
+	brokenLinkRateLimit  = 10
+	brokenLinkRateWindow = time.Hour
+)
+
+const (
+	// 数据库中保存的爬取结果的有效期,超过后重新爬取
+	storedVideoSourceTTL = 24 * time.Hour
+	// 管理员指定的播放源的来源站点
+	manualVideoSourceOrigin = "manual"
+)
+
+const (
+	// 下一集预取任务的消息队列主题,与其他后台爬取任务分开消费
+	videoPrefetchTopic = "video_prefetch_channel"
+	// 同一集的预取任务在该窗口内只投递一次
+	videoPrefetchWindow = 30 * time.Minute
+	// 用户在该时长内看过上一集后开始播放本集,视为连续观看,额外预取下下集
+	bingeWatchWindow = 30 * time.Minute
+	// 所有实例在统计窗口内合计允许的预取爬取次数
+	videoPrefetchRateLimit  = 20
+	videoPrefetchRateWindow = time.Minute
+	// 投递预取任务的超时时间
+	videoPrefetchTimeout = 5 * time.Second
+)
+
+// 无法从数字解析的清晰度名称对应的分辨率高度
+var qualityHeights = map[string]int{
+	"4k":   2160,
+	"2k":   1440,
+	"蓝光":   1080,
+	"超清":   1080,
+	"高清":   720,
+	"标清":   480,
+	"流畅":   360,
+	"auto": 0,
+}
+
+// videoSourceReportKey 生成用户对一个播放源的上报去重key,地址较长,使用摘要
+func videoSourceReportKey(userID, videoID int, episode, url string) string {
+	return fmt.Sprintf("VideoSourceReport:User%d:Video%d:Episode%s:%x", userID, videoID, episode, sha1.Sum([]byte(url)))
+}
+
+// videoSourceReportRateKey 生成用户播放失败上报次数的计数key
+func videoSourceReportRateKey(userID int) string {
+	return fmt.Sprintf("VideoSourceReport:Rate:User%d", userID)
+}
+
+// brokenLinkReportKey 生成一集视频失效上报的去重key
+func brokenLinkReportKey(videoID int, episode string) string {
+	return fmt.Sprintf("BrokenLink:Report:Video%d:Episode%s", videoID, episode)
+}
+
+// brokenLinkRateKey 生成用户失效上报次数的计数key
+func brokenLinkRateKey(userID int) string {
+	return fmt.Sprintf("BrokenLink:Rate:User%d", userID)
+}
+
+// brokenLinkCountKey 生成一集视频失效次数的计数key
+func brokenLinkCountKey(videoID int, episode string) string {
+	return fmt.Sprintf("BrokenLink:Count:Video%d:Episode%s", videoID, episode)
+}
+
+// videoPrefetchKey 生成一集视频预取任务的去重key
+func videoPrefetchKey(videoID int, episode string) string {
+	return fmt.Sprintf("VideoPrefetch:Video%d:Episode%s", videoID, episode)
+}
+
+// videoPrefetchRateKey 生成当前统计窗口的预取爬取计数key
+func videoPrefetchRateKey(now time.Time) string {
+	return fmt.Sprintf("VideoPrefetch:Rate:%d", now.Unix()/int64(videoPrefetchRateWindow/time.Second))
+}
+
+// manualVideoSource 将管理员指定的播放地址转换为播放源
+func manualVideoSource(url string) *entity.VideoSource {
+	return &entity.VideoSource{
+		URL:    url,
+		Origin: manualVideoSourceOrigin,
+		Format: videoFormat("", url),
+	}
+}
+
+// storedVideoSources 从数据库保存的记录中取出可以直接使用的播放源
+// 管理员指定的地址优先且不会过期,爬取结果超过有效期时返回空
+func storedVideoSources(stored *entity.StoredVideoSources) []*entity.VideoSource {
+	if stored.VideoURL != "" {
+		return []*entity.VideoSource{manualVideoSource(stored.VideoURL)}
+	}
+	if stored.ScrapedAt == "" || stored.Age > storedVideoSourceTTL {
+		return nil
+	}
+
+	// 评分不持久化,按保存时的顺序重新赋值
+	for i, source := range stored.Sources {
+		source.Score = float64(len(stored.Sources) - i)
+	}
+	return stored.Sources
+}
+
+// rankVideoSources 将爬取结果转换为播放源并计算初始评分
+// 清晰度越高评分越高,清晰度相同时保持爬虫返回的顺序
+// 旧版本爬虫只返回单个地址,此时作为唯一的播放源
+func rankVideoSources(msg *scrapeClient.VideoMsg) []*entity.VideoSource {
+	scraped := msg.GetSources()
+	if len(scraped) == 0 && msg.GetUrl() != "" {
+		scraped = []*scrapeClient.VideoSource{{Url: msg.GetUrl()}}
+	}
+
+	sources := make([]*entity.VideoSource, 0, len(scraped))
+	seen := make(map[string]bool, len(scraped))
+	for _, s := range scraped {
+		url := strings.TrimSpace(s.GetUrl())
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		sources = append(sources, &entity.VideoSource{
+			URL:     url,
+			Quality: strings.TrimSpace(s.GetQuality()),
+			Origin:  strings.TrimSpace(s.GetOrigin()),
+			Format:  videoFormat(s.GetFormat(), url),
+		})
+	}
+
+	// 顺序分数小于1,不会影响不同清晰度之间的排序
+	for i, source := range sources {
+		source.Score = float64(qualityHeight(source.Quality)) + float64(len(sources)-i)/float64(len(sources)+1)
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Score > sources[j].Score
+	})
+	return sources
+}
+
+// qualityHeight 解析清晰度对应的分辨率高度,如 "1080p" 为1080,无法识别时为0
+func qualityHeight(quality string) int {
+	quality = strings.ToLower(strings.TrimSpace(quality))
+	if height, ok := qualityHeights[quality]; ok {
+		return height
+	}
+	height, err := strconv.Atoi(strings.TrimSuffix(quality, "p"))
+	if err != nil || height < 0 {
+		return 0
+	}
+	return height
+}
+
+// videoFormat 规范化视频格式,爬虫未返回格式时根据地址判断
+func videoFormat(format, url string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "m3u8" {
+		return entity.VideoFormatHLS
+	}
+	if format != "" {
+		return format
+	}
+
+	path := strings.ToLower(url)
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if strings.HasSuffix(path, ".m3u8") {
+		return entity.VideoFormatHLS
+	}
+	return entity.VideoFormatMP4
+}
@@ -7,8 +7,13 @@ import (
 	"gateService/internal/infrastructure/database"
 	"gateService/internal/infrastructure/middleware/auth"
 	"gateService/internal/infrastructure/middleware/websocket"
+	"gateService/internal/infrastructure/search"
+	"gateService/pkg/identity"
 	"gateService/pkg/logger"
+	"gateService/pkg/mailer"
+	"gateService/pkg/monitor"
 	"gateService/pkg/mq/nsqpool"
+	"gateService/pkg/password"
 	"log"
 )
 
@@ -46,6 +51,25 @@ type bases struct {
 	// - 安全属性设置（HttpOnly/Secure等）
 	CookieManager *auth.CookieManager
 
+	// PasswordHasher 密码哈希器
+	// 功能包含：
+	// - 按配置算法生成密码哈希
+	// - 按前缀识别算法校验密码
+	// - 判断历史数据是否需要重新哈希
+	PasswordHasher *password.Hasher
+
+	// Mailer 邮件发送器
+	// 功能包含：
+	// - 发送注册验证码等通知邮件
+	// - 支持SMTP及本地文件/标准输出两种方式
+	Mailer mailer.Mailer
+
+	// IdentityProviders 第三方身份提供方,按名称索引
+	// 功能包含：
+	// - OpenID Connect服务发现与签名公钥缓存
+	// - 授权码(PKCE)换取并校验ID令牌
+	IdentityProviders map[string]identity.IdentityProvider
+
 	// ProducerPool NSQ消息队列生产者池
 	// 功能包含：
 	// - 异步消息发布
@@ -73,6 +97,20 @@ type bases struct {
 	// - 消息广播路由
 	// - 心跳检测机制
 	WebSocketManager *websocket.Manager
+
+	// VideoIndex 视频全文检索索引
+	// 功能包含：
+	// - 名称、别名、类型、简介的倒排索引
+	// - 中日韩文字二元组切分与BM25相关度排序
+	// - 由检索索引消费者构建并增量更新
+	VideoIndex *search.VideoIndex
+
+	// SuggestIndex 视频名称输入提示索引
+	// 功能包含：
+	// - 名称原文、拼音全拼、首字母前缀匹配
+	// - 按热度返回前N条结果
+	// - 由检索索引消费者定期刷新
+	SuggestIndex *search.SuggestIndex
 }
 
 // initBases 基础设施初始化工厂方法
@@ -99,6 +137,9 @@ func initBases(cfg *config.Config) *bases {
 		log.Fatalf("初始化NSQ生产者池失败: %v\n", err)
 	}
 
+	// 初始化监控日志上报（登录锁定等安全事件推送至监控服务）
+	monitor.Init(monitor.NewLogConfig())
+
 	// 初始化爬虫服务gRPC客户端池（元数据获取）
 	scrapeClient, err := scrapeClient.NewGRPCClientPool(cfg)
 	if err != nil {
@@ -111,18 +152,63 @@ func initBases(cfg *config.Config) *bases {
 		log.Fatalf("初始化推荐服务客户端失败: %v\n", err)
 	}
 
+	// 初始化密码哈希器（用户凭证存储）
+	passwordHasher := password.NewHasher(&password.HashOptions{
+		Algorithm:     cfg.Security.Password.Algorithm,
+		BcryptCost:    cfg.Security.Password.BcryptCost,
+		Argon2Time:    cfg.Security.Password.Argon2Time,
+		Argon2Memory:  cfg.Security.Password.Argon2Memory,
+		Argon2Threads: cfg.Security.Password.Argon2Threads,
+	})
+
+	// 初始化邮件发送器（验证码/通知邮件）
+	mailSender, err := mailer.New(&mailer.Options{
+		Driver:   cfg.Mail.Driver,
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		From:     cfg.Mail.From,
+		FilePath: cfg.Mail.FilePath,
+	})
+	if err != nil {
+		log.Fatalf("初始化邮件发送器失败: %v\n", err)
+	}
+
+	// 初始化第三方身份提供方（OpenID Connect登录）
+	identityProviders := make(map[string]identity.IdentityProvider)
+	for _, p := range cfg.OAuth.Providers {
+		provider, err := identity.NewOIDCProvider(&identity.OIDCOptions{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
+		if err != nil {
+			log.Fatalf("初始化身份提供方%s失败: %v\n", p.Name, err)
+		}
+		identityProviders[p.Name] = provider
+	}
+
 	// 初始化WebSocket连接管理器（实时通信）
 	websocketManager := websocket.NewManager(logger.Log)
 
 	return &bases{
-		DB:               database.NewDB(cfg),                // MySQL数据库连接（业务主存储）
-		RDB:              database.NewRDB(cfg),               // Redis连接（缓存/会话）
-		JwtManager:       auth.NewJWTManager(&cfg.JWT),       // JWT认证组件
-		CookieManager:    auth.NewCookieManager(&cfg.Cookie), // Cookie安全组件
-		ProducerPool:     producerPool,                       // 消息队列生产者
-		ScrapeClient:     scrapeClient,                       // 爬虫服务客户端
-		RecommendClient:  recommendClient,                    // 推荐服务客户端
-		WebSocketManager: websocketManager,                   // WebSocket管理器
+		DB:                database.NewDB(cfg),                // MySQL数据库连接（业务主存储）
+		RDB:               database.NewRDB(cfg),               // Redis连接（缓存/会话）
+		JwtManager:        auth.NewJWTManager(&cfg.JWT),       // JWT认证组件
+		CookieManager:     auth.NewCookieManager(&cfg.Cookie), // Cookie安全组件
+		PasswordHasher:    passwordHasher,                     // 密码哈希器
+		Mailer:            mailSender,                         // 邮件发送器
+		IdentityProviders: identityProviders,                  // 第三方身份提供方
+		ProducerPool:      producerPool,                       // 消息队列生产者
+		ScrapeClient:      scrapeClient,                       // 爬虫服务客户端
+		RecommendClient:   recommendClient,                    // 推荐服务客户端
+		WebSocketManager:  websocketManager,                   // WebSocket管理器
+		VideoIndex:        search.NewVideoIndex(),             // 视频全文检索索引
+		SuggestIndex:      search.NewSuggestIndex(),           // 视频名称输入提示索引
 	}
 }
 
@@ -137,4 +223,5 @@ func (b *bases) Close() {
 	b.ProducerPool.Close()    // 停止NSQ消息生产者
 	b.ScrapeClient.Close()    // 关闭爬虫服务gRPC连接池
 	b.RecommendClient.Close() // 关闭推荐服务gRPC连接池
+	monitor.Close()           // 停止监控日志上报
 }
@@ -9,13 +9,25 @@ type consumers struct {
 	OrderConsumer   *consumer.OrderConsumer
 	CommentConsumer *consumer.CommentConsumer
 	AccountConsumer *consumer.AccountConsumer
+	FollowConsumer  *consumer.FollowConsumer
+	SearchConsumer  *consumer.SearchIndexConsumer
+	StatsConsumer   *consumer.SearchAnalyticsConsumer
+	ScrapeConsumer  *consumer.VideoScrapeConsumer
+	LinkChecker     *consumer.LinkHealthChecker
+	Prefetcher      *consumer.VideoPrefetchConsumer
 }
 
-func initConsumers(cfg *config.Config, bases *bases, repositories *repositories) *consumers {
+func initConsumers(cfg *config.Config, bases *bases, repositories *repositories, services *services) *consumers {
 	return &consumers{
 		OrderConsumer:   consumer.NewOrderConsumer(repositories.OrderRepo),
-		CommentConsumer: consumer.NewCommentConsumer(repositories.PostRepo, repositories.PostCommentRepo, repositories.UserRepo, bases.WebSocketManager),
-		AccountConsumer: consumer.NewAccountConsumer(&cfg.JWT, repositories.UserRepo),
+		CommentConsumer: consumer.NewCommentConsumer(repositories.PostRepo, repositories.PostCommentRepo, repositories.UserRepo, repositories.BlockRepo, bases.WebSocketManager),
+		AccountConsumer: consumer.NewAccountConsumer(&cfg.JWT, &cfg.Storage.Export, repositories.UserRepo, repositories.TokenRepo, repositories.AccountDataRepo),
+		FollowConsumer:  consumer.NewFollowConsumer(repositories.FollowRepo, repositories.BlockRepo, repositories.UserRepo, bases.WebSocketManager),
+		SearchConsumer:  consumer.NewSearchIndexConsumer(repositories.VideoRepo, bases.VideoIndex, bases.SuggestIndex),
+		StatsConsumer:   consumer.NewSearchAnalyticsConsumer(repositories.SearchAnalyticsRepo),
+		ScrapeConsumer:  consumer.NewVideoScrapeConsumer(services.VideoService),
+		LinkChecker:     consumer.NewLinkHealthChecker(repositories.VideoRepo, repositories.ProgressRepo, repositories.LinkHealthRepo, bases.ProducerPool),
+		Prefetcher:      consumer.NewVideoPrefetchConsumer(services.VideoService),
 	}
 }
 
@@ -23,10 +35,22 @@ func (c *consumers) Start() {
 	c.OrderConsumer.Start()
 	c.CommentConsumer.Start()
 	c.AccountConsumer.Start()
+	c.FollowConsumer.Start()
+	c.SearchConsumer.Start()
+	c.StatsConsumer.Start()
+	c.ScrapeConsumer.Start()
+	c.LinkChecker.Start()
+	c.Prefetcher.Start()
 }
 
 func (c *consumers) Close() {
 	c.OrderConsumer.Stop()
 	c.CommentConsumer.Stop()
 	c.AccountConsumer.Stop()
+	c.FollowConsumer.Stop()
+	c.SearchConsumer.Stop()
+	c.StatsConsumer.Stop()
+	c.ScrapeConsumer.Stop()
+	c.LinkChecker.Stop()
+	c.Prefetcher.Stop()
 }
@@ -32,7 +32,7 @@ func NewContainer(configPath string) *Container {
 	services := initServices(cfg, bases, repositories)
 
 	// 初始化消费者
-	consumers := initConsumers(cfg, bases, repositories)
+	consumers := initConsumers(cfg, bases, repositories, services)
 
 	// 初始化接口层
 	interfaces := initInterfaces(cfg, bases, repositories, services)
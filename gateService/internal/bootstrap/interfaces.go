@@ -28,10 +28,10 @@ type interfaces struct {
 //   - 初始化完成的接口组件集合
 func initInterfaces(cfg *config.Config, bases *bases, repositories *repositories, services *services) *interfaces {
 	// 初始化 HTTP 路由控制器，注入所有依赖服务
-	router := router.NewController(cfg, bases.JwtManager, bases.CookieManager, repositories.UserRepo,
+	router := router.NewController(cfg, bases.JwtManager, bases.CookieManager, repositories.UserRepo, repositories.TokenRepo, repositories.PersonalAccessTokenRepo,
 		services.ProgressService, services.PostService, services.CommentService,
-		services.SearchService, services.UserService, services.ProductService,
-		services.OrderService, services.VideoService, services.WebSocketService)
+		services.SearchService, services.UserService, services.MfaService, services.OAuthService, services.SessionService, services.PersonalAccessTokenService, services.AccountService, services.FollowService, services.BlockService, services.ProductService,
+		services.OrderService, services.VideoService, services.CatalogService, services.WebSocketService)
 
 	// 创建 gRPC 服务器并注册 Token 服务
 	grpcServer := grpc.NewServer()
@@ -9,6 +9,26 @@ import (
 type repositories struct {
 	// UserRepo 用户仓储,处理用户数据的持久化
 	UserRepo repository.UserRepository
+	// TokenRepo 令牌仓储,管理刷新令牌家族等认证状态,仅使用Redis
+	TokenRepo repository.TokenRepository
+	// VerificationRepo 验证凭证仓储,管理邮箱验证码等一次性凭证,仅使用Redis
+	VerificationRepo repository.VerificationRepository
+	// MfaRepo 两步验证仓储,使用MySQL存储密钥和恢复码,Redis记录已使用的验证码
+	MfaRepo repository.MfaRepository
+	// IdentityRepo 第三方身份仓储,使用MySQL存储身份绑定,Redis保存授权请求状态
+	IdentityRepo repository.IdentityRepository
+	// PersonalAccessTokenRepo 个人访问令牌仓储,仅使用MySQL,只保存令牌哈希
+	PersonalAccessTokenRepo repository.PersonalAccessTokenRepository
+	// AccountDataRepo 用户数据导出与注销仓储,跨业务表读写MySQL,Redis保存导出任务状态
+	AccountDataRepo repository.AccountDataRepository
+	// FollowRepo 用户关注关系仓储,使用MySQL存储关注关系,Redis记录关注通知发送状态
+	FollowRepo repository.FollowRepository
+	// BlockRepo 用户屏蔽关系仓储,使用MySQL存储拉黑/静音关系,Redis缓存每个用户的屏蔽列表
+	BlockRepo repository.BlockRepository
+	// SearchAnalyticsRepo 搜索统计仓储,仅使用Redis,以有序集合保存按时间衰减的搜索热度
+	SearchAnalyticsRepo repository.SearchAnalyticsRepository
+	// LinkHealthRepo 播放地址健康检查仓储,仅使用Redis,记录抽样位置和各来源站点的探测统计
+	LinkHealthRepo repository.LinkHealthRepository
 	// PostRepo 帖子仓储,处理帖子内容的存储和查询
 	PostRepo repository.PostRepository
 	// PostTagRepo 帖子标签仓储,管理帖子标签数据
@@ -39,6 +59,26 @@ func initRepositories(bases *bases) *repositories {
 	return &repositories{
 		// 初始化用户仓储,仅使用MySQL
 		UserRepo: database.NewUserRepositoryImpl(bases.DB.GetDB(), bases.RDB.GetRDB()),
+		// 初始化令牌仓储,仅使用Redis
+		TokenRepo: database.NewTokenRepositoryImpl(bases.RDB.GetRDB()),
+		// 初始化验证凭证仓储,仅使用Redis
+		VerificationRepo: database.NewVerificationRepositoryImpl(bases.RDB.GetRDB()),
+		// 初始化两步验证仓储,同时使用MySQL和Redis
+		MfaRepo: database.NewMfaRepositoryImpl(bases.DB.GetDB(), bases.RDB.GetRDB()),
+		// 初始化第三方身份仓储,同时使用MySQL和Redis
+		IdentityRepo: database.NewIdentityRepositoryImpl(bases.DB.GetDB(), bases.RDB.GetRDB()),
+		// 初始化个人访问令牌仓储,仅使用MySQL
+		PersonalAccessTokenRepo: database.NewPersonalAccessTokenRepositoryImpl(bases.DB.GetDB()),
+		// 初始化用户数据导出与注销仓储,同时使用MySQL和Redis
+		AccountDataRepo: database.NewAccountDataRepositoryImpl(bases.DB.GetDB(), bases.RDB.GetRDB()),
+		// 初始化用户关注关系仓储,同时使用MySQL和Redis
+		FollowRepo: database.NewFollowRepositoryImpl(bases.DB.GetDB(), bases.RDB.GetRDB()),
+		// 初始化用户屏蔽关系仓储,同时使用MySQL和Redis
+		BlockRepo: database.NewBlockRepositoryImpl(bases.DB.GetDB(), bases.RDB.GetRDB()),
+		// 初始化搜索统计仓储,仅使用Redis
+		SearchAnalyticsRepo: database.NewSearchAnalyticsRepositoryImpl(bases.RDB.GetRDB()),
+		// 初始化播放地址健康检查仓储,仅使用Redis
+		LinkHealthRepo: database.NewLinkHealthRepositoryImpl(bases.RDB.GetRDB()),
 		// 初始化帖子仓储,仅使用MySQL
 		PostRepo: database.NewPostRepositoryImpl(bases.DB.GetDB()),
 		// 初始化帖子标签仓储,仅使用MySQL
@@ -17,6 +17,34 @@ type services struct {
 	// 功能包含：用户注册/登录、信息管理、权限验证等
 	UserService service.UserService
 
+	// MfaService 两步验证领域服务
+	// 功能包含：TOTP绑定/确认/关闭、一次性恢复码管理等
+	MfaService service.MfaService
+
+	// OAuthService 第三方登录领域服务
+	// 功能包含：OpenID Connect授权码登录、第三方身份绑定、自动创建账号等
+	OAuthService service.OAuthService
+
+	// SessionService 登录会话领域服务
+	// 功能包含：登录设备列表、移除指定会话、移除其他全部会话等
+	SessionService service.SessionService
+
+	// PersonalAccessTokenService 个人访问令牌领域服务
+	// 功能包含：令牌创建/列表/吊销、授权范围管理等
+	PersonalAccessTokenService service.PersonalAccessTokenService
+
+	// FollowService 用户关注领域服务
+	// 功能包含：关注/取消关注、粉丝列表、关注列表等
+	FollowService service.FollowService
+
+	// BlockService 用户屏蔽领域服务
+	// 功能包含：拉黑/静音、取消拉黑/静音、拉黑列表、静音列表等
+	BlockService service.BlockService
+
+	// AccountService 账号数据领域服务
+	// 功能包含：异步导出个人数据、注销账号等
+	AccountService service.AccountService
+
 	// PostService 社区帖子领域服务
 	// 功能包含：帖子CRUD、标签管理、评论互动等
 	PostService service.PostService
@@ -45,6 +73,10 @@ type services struct {
 	// 功能包含：元数据管理、推荐算法集成、资源地址生成等
 	VideoService service.VideoService
 
+	// CatalogService 视频目录管理领域服务
+	// 功能包含：视频增删改、类型和剧集列表维护、目录派生缓存清理等
+	CatalogService service.CatalogService
+
 	// TokenService 认证令牌gRPC服务
 	// 功能包含：JWT令牌签发/验证、令牌刷新、吊销列表管理等
 	TokenService *tokenService.Server
@@ -69,13 +101,67 @@ type services struct {
 func initServices(cfg *config.Config, bases *bases, repos *repositories) *services {
 	return &services{
 		UserService: serviceImpl.NewUserServiceImpl(
-			&cfg.Storage,          // 文件存储配置
+			&cfg.Storage,                  // 文件存储配置
+			&cfg.Mail,                     // 邮件发送配置
+			repos.UserRepo,                // 用户数据仓储
+			repos.TokenRepo,               // 令牌状态仓储
+			repos.VerificationRepo,        // 验证凭证仓储
+			repos.MfaRepo,                 // 两步验证仓储
+			repos.PersonalAccessTokenRepo, // 个人访问令牌仓储
+			repos.FollowRepo,              // 用户关注关系仓储
+			repos.PostRepo,                // 帖子数据仓储
+			repos.PostCommentRepo,         // 帖子评论数据仓储
+			bases.JwtManager,              // JWT认证组件
+			bases.CookieManager,           // Cookie管理组件
+			bases.PasswordHasher,          // 密码哈希器
+			bases.Mailer,                  // 邮件发送器
+			bases.ProducerPool,            // 消息队列生产者池
+		),
+		MfaService: serviceImpl.NewMfaServiceImpl(
+			cfg.Security.Mfa.Issuer, // 验证器应用中显示的签发方名称
+			repos.UserRepo,          // 用户数据仓储
+			repos.MfaRepo,           // 两步验证仓储
+			bases.PasswordHasher,    // 密码哈希器
+		),
+		OAuthService: serviceImpl.NewOAuthServiceImpl(
+			&cfg.OAuth,              // 第三方登录配置
+			bases.IdentityProviders, // 第三方身份提供方
+			repos.UserRepo,          // 用户数据仓储
+			repos.IdentityRepo,      // 第三方身份仓储
+			repos.MfaRepo,           // 两步验证仓储
+			repos.TokenRepo,         // 令牌状态仓储
+			bases.JwtManager,        // JWT认证组件
+			bases.CookieManager,     // Cookie管理组件
+			bases.PasswordHasher,    // 密码哈希器
+		),
+		SessionService: serviceImpl.NewSessionServiceImpl(
+			repos.TokenRepo, // 令牌状态仓储（会话即刷新令牌家族）
+		),
+		PersonalAccessTokenService: serviceImpl.NewPersonalAccessTokenServiceImpl(
+			repos.UserRepo,                // 用户数据仓储
+			repos.PersonalAccessTokenRepo, // 个人访问令牌仓储
+		),
+		FollowService: serviceImpl.NewFollowServiceImpl(
+			repos.FollowRepo,   // 用户关注关系仓储
+			repos.BlockRepo,    // 用户屏蔽关系仓储
+			repos.UserRepo,     // 用户数据仓储
+			bases.ProducerPool, // 消息队列生产者池（用于异步关注通知）
+		),
+		BlockService: serviceImpl.NewBlockServiceImpl(
+			repos.BlockRepo,  // 用户屏蔽关系仓储
+			repos.FollowRepo, // 用户关注关系仓储（拉黑时解除关注）
+			repos.UserRepo,   // 用户数据仓储
+		),
+		AccountService: serviceImpl.NewAccountServiceImpl(
+			&cfg.Storage.Export,   // 数据导出文件存储配置
 			repos.UserRepo,        // 用户数据仓储
-			repos.PostRepo,        // 帖子数据仓储
-			repos.PostCommentRepo, // 帖子评论数据仓储
+			repos.TokenRepo,       // 令牌状态仓储
+			repos.MfaRepo,         // 两步验证仓储
+			repos.AccountDataRepo, // 用户数据导出与注销仓储
 			bases.JwtManager,      // JWT认证组件
 			bases.CookieManager,   // Cookie管理组件
-			bases.ProducerPool,    // 消息队列生产者池
+			bases.PasswordHasher,  // 密码哈希器
+			bases.ProducerPool,    // 消息队列生产者池（用于异步导出）
 		),
 		PostService: serviceImpl.NewPostServiceImpl(
 			&cfg.Storage,              // 文件存储配置
@@ -84,6 +170,7 @@ func initServices(cfg *config.Config, bases *bases, repos *repositories) *servic
 			repos.PostTagRelationRepo, // 标签关系仓储
 			repos.PostCommentRepo,     // 帖子评论仓储
 			repos.UserRepo,            // 用户信息仓储
+			repos.BlockRepo,           // 用户屏蔽关系仓储
 			bases.ProducerPool,        // NSQ消息生产者池
 		),
 		ProgressService: serviceImpl.NewProgressServiceImpl(
@@ -95,7 +182,11 @@ func initServices(cfg *config.Config, bases *bases, repos *repositories) *servic
 			repos.UserRepo,    // 用户信息仓储
 		),
 		SearchService: serviceImpl.NewSearchServiceImpl(
-			repos.VideoRepo, // 视频元数据仓储
+			repos.VideoRepo,           // 视频元数据仓储（索引未就绪时回退到数据库查询）
+			repos.SearchAnalyticsRepo, // 搜索统计仓储（热门搜索、无结果搜索词）
+			bases.VideoIndex,          // 视频全文检索索引
+			bases.SuggestIndex,        // 视频名称输入提示索引
+			bases.ProducerPool,        // 消息生产者（发布搜索行为消息）
 		),
 		ProductService: serviceImpl.NewProductServiceImpl(
 			repos.ProductRepo, // 商品数据仓储
@@ -113,9 +204,16 @@ func initServices(cfg *config.Config, bases *bases, repos *repositories) *servic
 			bases.RecommendClient, // 推荐算法服务客户端
 			repos.VideoRepo,       // 视频元数据仓储
 			repos.ProgressRepo,    // 进度数据仓储（关联查询）
+			repos.LinkHealthRepo,  // 播放地址健康检查仓储（探测统计）
+			bases.ProducerPool,    // 消息队列生产者池（用于下一集预取）
+		),
+		CatalogService: serviceImpl.NewCatalogServiceImpl(
+			repos.VideoRepo,    // 视频元数据仓储（同时负责清理目录派生缓存）
+			bases.ProducerPool, // 消息生产者（发布目录变更,更新检索索引）
 		),
 		TokenService: tokenService.NewServer(
 			bases.JwtManager, // JWT管理器（签名/验证）
+			repos.TokenRepo,  // 令牌状态仓储（吊销检查）
 		),
 		WebSocketService: connection.NewWebSocketServiceImpl(
 			bases.WebSocketManager, // WebSocket连接管理器
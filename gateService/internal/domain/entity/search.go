@@ -0,0 +1,23 @@
+package entity
+
+// SearchQueryEvent 搜索行为消息,每次搜索后发布,由搜索统计消费者汇总
+type SearchQueryEvent struct {
+	Query       string `json:"query"`        // 归一化后的搜索词
+	UserID      int    `json:"user_id"`      // 搜索用户ID
+	ResultCount int    `json:"result_count"` // 命中的视频数量
+	Source      string `json:"source"`       // 搜索入口: search-搜索框 detail-搜索结果页
+	SearchedAt  int64  `json:"searched_at"`  // 搜索时间(Unix秒)
+}
+
+// 搜索入口
+const (
+	SearchSourceBox    = "search" // 搜索框输入过程中的实时搜索,只计入无结果统计
+	SearchSourceDetail = "detail" // 提交搜索后的搜索结果页
+)
+
+// SearchQueryStat 搜索词统计
+// 分数随时间衰减,每过一个半衰期减半,近期搜索越多分数越高
+type SearchQueryStat struct {
+	Query string  `json:"query"` // 搜索词
+	Score float64 `json:"score"` // 衰减后的搜索热度
+}
@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"strings"
+	"time"
+)
+
 // UserInfo 用户信息结构体
 // 对应数据库表 user_infos
 type UserInfo struct {
@@ -15,6 +20,144 @@ type UserInfo struct {
 	CreatedAt   string `json:"created_at"`    // 创建时间,自动生成
 	LastLoginAt string `json:"last_login_at"` // 最后登录时间,自动更新
 	Status      int8   `json:"status"`        // 用户状态:0-删除,1-正常
+	Role        string `json:"role"`          // 用户角色:user/moderator/admin,默认user
+}
+
+// 用户角色
+const (
+	RoleUser      = "user"      // 普通用户
+	RoleModerator = "moderator" // 版主
+	RoleAdmin     = "admin"     // 管理员
+)
+
+// TestAccountEmailSuffix 体验账号邮箱后缀,体验账号转为正式账号后邮箱替换为真实邮箱
+const TestAccountEmailSuffix = "@example.com"
+
+// IsTestAccountEmail 判断邮箱是否为体验账号邮箱
+// 忽略大小写,与数据库按不区分大小写的排序规则匹配体验账号一致
+func IsTestAccountEmail(email string) bool {
+	return len(email) >= len(TestAccountEmailSuffix) &&
+		strings.EqualFold(email[len(email)-len(TestAccountEmailSuffix):], TestAccountEmailSuffix)
+}
+
+// UserMfa 用户两步验证配置结构体
+// 对应数据库表 user_mfa
+type UserMfa struct {
+	UserID    int    `json:"user_id"`    // 用户ID,主键
+	Secret    string `json:"-"`          // TOTP密钥(base32),不对外返回
+	Enabled   bool   `json:"enabled"`    // 是否已启用,未确认前为false
+	CreatedAt string `json:"created_at"` // 创建时间,自动生成
+	UpdatedAt string `json:"updated_at"` // 更新时间,自动更新
+}
+
+// UserIdentity 第三方身份绑定结构体
+// 对应数据库表 user_identities
+type UserIdentity struct {
+	ID        int    `json:"id"`         // 自增主键
+	UserID    int    `json:"user_id"`    // 用户ID
+	Provider  string `json:"provider"`   // 身份提供方名称
+	Subject   string `json:"subject"`    // 用户在身份提供方处的唯一标识
+	Email     string `json:"email"`      // 绑定时身份提供方返回的邮箱
+	CreatedAt string `json:"created_at"` // 创建时间,自动生成
+}
+
+// OAuthState 第三方登录授权请求的临时状态,保存在Redis中,回调时一次性取出
+type OAuthState struct {
+	Provider     string `json:"provider"`      // 身份提供方名称
+	Nonce        string `json:"nonce"`         // ID令牌nonce
+	CodeVerifier string `json:"code_verifier"` // PKCE校验码
+	RedirectPath string `json:"redirect_path"` // 登录完成后跳转的前端路径
+}
+
+// OAuthPendingLink 待确认的第三方身份绑定,保存在Redis中,用户输入本地账号密码确认后一次性取出
+// 身份提供方返回的邮箱与已有账号相同时不直接绑定,防止邮箱校验存在漏洞的身份提供方接管本地账号
+type OAuthPendingLink struct {
+	UserID       int    `json:"user_id"`       // 邮箱对应的本地账号ID
+	Provider     string `json:"provider"`      // 身份提供方名称
+	Subject      string `json:"subject"`       // 用户在身份提供方处的唯一标识
+	Email        string `json:"email"`         // 身份提供方返回的邮箱
+	RedirectPath string `json:"redirect_path"` // 登录完成后跳转的前端路径
+}
+
+// Session 登录会话结构体
+// 对应Redis中的刷新令牌家族,一次登录产生一个会话,刷新令牌轮换不会产生新会话
+type Session struct {
+	SessionID  string `json:"session_id"`   // 会话ID,即刷新令牌家族ID
+	UserID     int    `json:"user_id"`      // 用户ID
+	Device     string `json:"device"`       // 设备描述,由User-Agent解析
+	UserAgent  string `json:"user_agent"`   // 登录时的User-Agent
+	IP         string `json:"ip"`           // 登录时的IP地址
+	CreatedAt  string `json:"created_at"`   // 登录时间
+	LastSeenAt string `json:"last_seen_at"` // 最近活跃时间
+	Current    bool   `json:"current"`      // 是否为当前请求所在的会话
+}
+
+// PersonalAccessToken 个人访问令牌结构体
+// 对应数据库表 personal_access_tokens,令牌明文仅在创建时返回一次
+type PersonalAccessToken struct {
+	TokenID     int64      `json:"token_id"`     // 令牌ID,自增主键
+	UserID      int        `json:"user_id"`      // 所属用户ID
+	Name        string     `json:"name"`         // 令牌名称
+	TokenHash   string     `json:"-"`            // 令牌SHA-256哈希,不对外返回
+	TokenPrefix string     `json:"token_prefix"` // 令牌前缀,用于识别令牌
+	Scopes      []string   `json:"scopes"`       // 授权范围
+	ExpiresAt   *time.Time `json:"expires_at"`   // 过期时间,为空表示永不过期
+	LastUsedAt  *time.Time `json:"last_used_at"` // 最近使用时间,为空表示从未使用
+	CreatedAt   time.Time  `json:"created_at"`   // 创建时间
+}
+
+// 个人访问令牌授权范围,与需要认证的路由分组一一对应
+const (
+	ScopeVideo    = "video"    // 视频服务及动漫收藏
+	ScopeComment  = "comment"  // 视频评论
+	ScopeOrder    = "order"    // 商品与订单
+	ScopeSearch   = "search"   // 搜索
+	ScopeProgress = "progress" // 观看进度
+	ScopePost     = "post"     // 社区帖子与帖子评论
+	ScopeUser     = "user"     // 用户资料与通知
+)
+
+// PersonalAccessTokenScopes 个人访问令牌可申请的全部授权范围
+var PersonalAccessTokenScopes = []string{ScopeVideo, ScopeComment, ScopeOrder, ScopeSearch, ScopeProgress, ScopePost, ScopeUser}
+
+// IsExpired 判断令牌在指定时间是否已过期
+func (t *PersonalAccessToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// HasScope 判断令牌是否拥有指定授权范围
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// DataExportJob 用户数据导出任务,保存在Redis中,由消息队列异步执行
+type DataExportJob struct {
+	JobID      string `json:"job_id"`                // 任务ID
+	UserID     int    `json:"user_id"`               // 用户ID
+	Status     string `json:"status"`                // 任务状态:pending/running/done/failed
+	FileName   string `json:"-"`                     // 导出文件名,仅服务端使用
+	Error      string `json:"error,omitempty"`       // 失败原因
+	CreatedAt  string `json:"created_at"`            // 创建时间
+	FinishedAt string `json:"finished_at,omitempty"` // 完成时间
+}
+
+// 用户数据导出任务状态
+const (
+	DataExportPending = "pending" // 等待执行
+	DataExportRunning = "running" // 执行中
+	DataExportDone    = "done"    // 已完成,可下载
+	DataExportFailed  = "failed"  // 执行失败
+)
+
+// UserDataSection 用户数据导出中的一类数据,对应导出ZIP中的一个JSON文件
+type UserDataSection struct {
+	Name string                   // 分类名称,即JSON文件名
+	Rows []map[string]interface{} // 数据行,键为字段名
 }
 
 // UserNotification 用户通知结构体
@@ -30,3 +173,44 @@ type UserNotification struct {
 	IsRead           bool   `json:"is_read"`           // 是否已读,默认为0(未读)
 	CreatedAt        string `json:"created_at"`        // 创建时间,自动生成
 }
+
+// 用户通知类型,与 user_notifications.notification_type 对应
+const (
+	NotificationTypeCommentLike  int8 = 1 // 点赞评论
+	NotificationTypeCommentReply int8 = 2 // 回复评论
+	NotificationTypePostFavorite int8 = 3 // 收藏帖子
+	NotificationTypePostLike     int8 = 4 // 点赞帖子
+	NotificationTypeFollow       int8 = 5 // 关注
+	NotificationTypeSystem       int8 = 6 // 系统消息
+)
+
+// UserFollow 用户关注关系结构体
+// 对应数据库表 user_follows
+type UserFollow struct {
+	FollowerID int    `json:"follower_id"` // 关注者用户ID
+	FolloweeID int    `json:"followee_id"` // 被关注者用户ID
+	CreatedAt  string `json:"created_at"`  // 关注时间
+}
+
+// FollowUser 关注列表或粉丝列表中的用户
+type FollowUser struct {
+	UserID     int    `json:"user_id"`     // 用户ID
+	Username   string `json:"username"`    // 用户名
+	AvatarURL  string `json:"avatar_url"`  // 头像URL
+	Signature  string `json:"signature"`   // 个性签名
+	FollowedAt string `json:"followed_at"` // 关注时间
+}
+
+// 用户屏蔽类型
+const (
+	BlockTypeBlock int8 = 1 // 拉黑,对方不能回复或关注自己,也不会收到对方的通知
+	BlockTypeMute  int8 = 2 // 静音,不会收到对方的通知,可选择隐藏对方的评论
+)
+
+// BlockedUser 拉黑列表或静音列表中的用户
+type BlockedUser struct {
+	UserID    int    `json:"user_id"`    // 用户ID
+	Username  string `json:"username"`   // 用户名
+	AvatarURL string `json:"avatar_url"` // 头像URL
+	BlockedAt string `json:"blocked_at"` // 屏蔽时间
+}
@@ -1,5 +1,12 @@
 package entity
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Video 表示视频实体
 type Video struct {
 	// 数据库原生字段
@@ -27,6 +34,85 @@ type Video struct {
 	CollectedAt string `json:"collected_at"`
 }
 
+// VideoEpisode 表示视频的一集
+// 对应数据库表 video_urls
+type VideoEpisode struct {
+	VideoID  int    `json:"video_id"`  // 视频ID
+	Episode  string `json:"episode"`   // 集数
+	VideoURL string `json:"video_url"` // 播放地址,为空时播放时再爬取
+	Status   int8   `json:"status"`    // 状态: 1-有效 0-无效
+}
+
+// 播放源的视频格式
+const (
+	VideoFormatMP4 = "mp4"
+	VideoFormatHLS = "hls"
+)
+
+// VideoSource 表示一集视频的一个播放源
+// 同一集可能有多个来源站点和清晰度,按评分从高到低返回
+type VideoSource struct {
+	URL     string  `json:"url"`     // 播放地址
+	Quality string  `json:"quality"` // 清晰度,如1080p、720p
+	Origin  string  `json:"origin"`  // 来源站点
+	Format  string  `json:"format"`  // 视频格式: mp4、hls
+	Score   float64 `json:"-"`       // 排序评分,播放失败被上报后降低
+}
+
+// StoredVideoSources 表示video_urls表中保存的一集视频的播放地址,作为Redis之后的第二级缓存
+type StoredVideoSources struct {
+	VideoID   int            `json:"video_id"`   // 视频ID
+	Episode   string         `json:"episode"`    // 集数
+	VideoURL  string         `json:"video_url"`  // 管理员指定的播放地址,不为空时优先于爬取结果
+	Sources   []*VideoSource `json:"sources"`    // 最近一次爬取到的播放源,按评分从高到低排列
+	ScrapedAt string         `json:"scraped_at"` // 最近一次爬取时间,从未爬取时为空
+	Age       time.Duration  `json:"-"`          // 距最近一次爬取的时长
+}
+
+// VideoSourceKey 生成一集视频播放源的缓存key
+func VideoSourceKey(videoID int, episode string) string {
+	return fmt.Sprintf("VideoURL:Video%d:Episode%s", videoID, episode)
+}
+
+// ParseVideoSourceKey 从播放源缓存key中解析视频ID和集数
+func ParseVideoSourceKey(key string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(key, "VideoURL:Video")
+	if !ok {
+		return 0, "", false
+	}
+	id, episode, ok := strings.Cut(rest, ":Episode")
+	if !ok || episode == "" {
+		return 0, "", false
+	}
+	videoID, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, "", false
+	}
+	return videoID, episode, true
+}
+
+// VideoScrapeJob 表示一个后台爬取任务,通过消息队列投递
+type VideoScrapeJob struct {
+	VideoID int    `json:"video_id"` // 视频ID
+	Episode string `json:"episode"`  // 集数
+	Replace bool   `json:"replace"`  // 是否要求爬虫忽略已保存的结果重新爬取
+	Reason  string `json:"reason"`   // 任务来源,如 link_check、prefetch
+}
+
+// WatchedEpisode 表示最近被观看的一集视频
+type WatchedEpisode struct {
+	VideoID int    `json:"video_id"` // 视频ID
+	Episode string `json:"episode"`  // 集数
+}
+
+// LinkHealthStat 表示一个来源站点的播放地址探测统计
+type LinkHealthStat struct {
+	Origin      string  `json:"origin"`       // 来源站点,爬虫未返回时为地址的域名
+	Alive       int64   `json:"alive"`        // 探测可用次数
+	Dead        int64   `json:"dead"`         // 探测失效次数
+	SuccessRate float64 `json:"success_rate"` // 可用率
+}
+
 // VideoFilters 表示视频筛选选项
 type VideoFilters struct {
 	Regions []string `json:"regions"` // 可用地区
@@ -44,3 +130,84 @@ type UserAnimeCollection struct {
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
+
+// CatalogRecord 表示批量导入/导出中的一部视频
+// 名称、上映时间和地区三者唯一确定一部视频
+type CatalogRecord struct {
+	VideoID       int      `json:"video_id,omitempty"` // 视频ID,导入时可选,指定时按ID更新,否则按名称、上映时间和地区匹配
+	Name          string   `json:"video_name"`         // 视频名称
+	ReleaseDate   string   `json:"release_date"`       // 上映时间
+	Area          string   `json:"area"`               // 地区
+	Description   string   `json:"description"`        // 简介
+	CoverImageUrl string   `json:"cover_image_url"`    // 封面图片URL
+	Genres        []string `json:"genres"`             // 类型列表
+}
+
+// IdentityKey 返回由名称、上映时间和地区组成的唯一标识
+func (r *CatalogRecord) IdentityKey() string {
+	return r.Name + "\x00" + r.ReleaseDate + "\x00" + r.Area
+}
+
+// 批量导入时每条记录的处理结果
+const (
+	CatalogActionCreate    = "create"    // 新建视频
+	CatalogActionUpdate    = "update"    // 更新已有视频
+	CatalogActionUnchanged = "unchanged" // 与已有视频一致,无需修改
+	CatalogActionConflict  = "conflict"  // 与文件中其他记录或已有视频冲突
+	CatalogActionInvalid   = "invalid"   // 记录校验失败
+)
+
+// CatalogFieldChange 表示更新记录中一个字段的变化
+type CatalogFieldChange struct {
+	Field string `json:"field"` // 字段名
+	Old   string `json:"old"`   // 原值,类型列表以"|"连接
+	New   string `json:"new"`   // 新值,类型列表以"|"连接
+}
+
+// CatalogImportItem 表示批量导入中一条记录的处理结果
+type CatalogImportItem struct {
+	Row       int                   `json:"row"`               // 记录位置,CSV为文件行号,JSON为数组下标(从1开始)
+	Action    string                `json:"action"`            // 处理结果
+	VideoID   int                   `json:"video_id"`          // 匹配到或新建的视频ID
+	VideoName string                `json:"video_name"`        // 视频名称
+	Changes   []*CatalogFieldChange `json:"changes,omitempty"` // 更新记录的字段变化
+	Message   string                `json:"message,omitempty"` // 冲突或校验失败的原因
+}
+
+// CatalogImportReport 表示批量导入的差异报告
+type CatalogImportReport struct {
+	DryRun    bool                 `json:"dry_run"`   // 是否为试运行,试运行不写入数据库
+	Applied   bool                 `json:"applied"`   // 变更是否已写入数据库
+	Total     int                  `json:"total"`     // 记录总数
+	Created   int                  `json:"created"`   // 新建数量
+	Updated   int                  `json:"updated"`   // 更新数量
+	Unchanged int                  `json:"unchanged"` // 无变化数量
+	Conflicts int                  `json:"conflicts"` // 冲突数量
+	Invalid   int                  `json:"invalid"`   // 校验失败数量
+	Items     []*CatalogImportItem `json:"items"`     // 各条记录的处理结果,不包含无变化的记录
+}
+
+// VideoSearchDocument 表示全文检索索引中的一部视频
+// 名称、别名、类型和简介参与检索,其余字段用于直接返回搜索结果
+type VideoSearchDocument struct {
+	VideoID       int      `json:"video_id"`
+	Name          string   `json:"video_name"`
+	Aliases       []string `json:"aliases"`
+	Genres        []string `json:"genres"`
+	Description   string   `json:"description"`
+	CoverImageUrl string   `json:"cover_image_url"`
+}
+
+// CatalogChangeEvent 视频目录变更消息,消费者按视频ID重新加载检索索引,视频已删除时从索引中移除
+type CatalogChangeEvent struct {
+	VideoIDs []int `json:"video_ids"`
+}
+
+// VideoSuggestion 表示输入提示中的一部视频
+type VideoSuggestion struct {
+	VideoID       int    `json:"video_id"`
+	Name          string `json:"video_name"`
+	CoverImageUrl string `json:"cover_image_url"`
+	Views         int    `json:"views"`
+	Likes         int    `json:"likes"`
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"gateService/internal/domain/entity"
+	"time"
+)
+
+// AccountDataRepository 定义了跨业务表的用户数据导出与注销的数据访问层接口
+type AccountDataRepository interface {
+	// SaveExportJob 保存数据导出任务,并记录为该用户最近一次导出任务
+	// 参数:
+	// - ctx: 上下文
+	// - job: 导出任务
+	// - ttl: 任务保留时间
+	// 返回:
+	// - error: 错误信息
+	SaveExportJob(ctx context.Context, job *entity.DataExportJob, ttl time.Duration) error
+
+	// GetExportJob 获取数据导出任务
+	// 参数:
+	// - ctx: 上下文
+	// - jobID: 任务ID
+	// 返回:
+	// - *entity.DataExportJob: 导出任务,不存在或已过期时返回nil
+	// - error: 错误信息
+	GetExportJob(ctx context.Context, jobID string) (*entity.DataExportJob, error)
+
+	// GetLatestExportJob 获取用户最近一次数据导出任务
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - *entity.DataExportJob: 导出任务,不存在或已过期时返回nil
+	// - error: 错误信息
+	GetLatestExportJob(ctx context.Context, userID int) (*entity.DataExportJob, error)
+
+	// ExportUserData 读取用户在各业务表中的全部数据
+	// 包含个人资料、帖子、帖子评论、视频评论、观看进度、动漫收藏、订单、关注关系和通知
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - []*entity.UserDataSection: 按分类组织的用户数据
+	// - error: 错误信息
+	ExportUserData(ctx context.Context, userID int) ([]*entity.UserDataSection, error)
+
+	// DeleteUserData 在同一事务中注销用户
+	// 他人可见的内容做匿名化处理,仅属于该用户的数据直接删除,用户记录本身保留为已删除状态
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - error: 错误信息
+	DeleteUserData(ctx context.Context, userID int) error
+}
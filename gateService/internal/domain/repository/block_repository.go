@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"gateService/internal/domain/entity"
+)
+
+// BlockRepository 定义了用户屏蔽关系(拉黑/静音)数据访问层的接口
+type BlockRepository interface {
+	// AddBlock 添加屏蔽关系
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 发起屏蔽的用户ID
+	// - targetUserID: 被屏蔽的用户ID
+	// - blockType: 屏蔽类型,拉黑或静音
+	// 返回:
+	// - bool: 是否新建了屏蔽关系,已屏蔽时返回false
+	// - error: 错误信息
+	AddBlock(ctx context.Context, userID, targetUserID int, blockType int8) (bool, error)
+
+	// RemoveBlock 移除屏蔽关系
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 发起屏蔽的用户ID
+	// - targetUserID: 被屏蔽的用户ID
+	// - blockType: 屏蔽类型,拉黑或静音
+	// 返回:
+	// - bool: 是否移除了屏蔽关系,未屏蔽时返回false
+	// - error: 错误信息
+	RemoveBlock(ctx context.Context, userID, targetUserID int, blockType int8) (bool, error)
+
+	// IsBlocked 判断用户是否屏蔽了目标用户,优先读取Redis缓存
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 发起屏蔽的用户ID
+	// - targetUserID: 被屏蔽的用户ID
+	// - blockType: 屏蔽类型,拉黑或静音
+	// 返回:
+	// - bool: 是否已屏蔽
+	// - error: 错误信息
+	IsBlocked(ctx context.Context, userID, targetUserID int, blockType int8) (bool, error)
+
+	// GetBlockedUserIDs 获取用户屏蔽的全部用户ID,优先读取Redis缓存
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 发起屏蔽的用户ID
+	// - blockType: 屏蔽类型,拉黑或静音
+	// 返回:
+	// - map[int]bool: 被屏蔽的用户ID集合
+	// - error: 错误信息
+	GetBlockedUserIDs(ctx context.Context, userID int, blockType int8) (map[int]bool, error)
+
+	// ListBlockedUsers 分页获取用户的拉黑列表或静音列表,按屏蔽时间倒序
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 发起屏蔽的用户ID
+	// - blockType: 屏蔽类型,拉黑或静音
+	// - page: 页码
+	// - pageSize: 每页数量
+	// 返回:
+	// - []*entity.BlockedUser: 被屏蔽的用户列表
+	// - error: 错误信息
+	ListBlockedUsers(ctx context.Context, userID int, blockType int8, page, pageSize int) ([]*entity.BlockedUser, error)
+}
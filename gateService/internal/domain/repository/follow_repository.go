@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"gateService/internal/domain/entity"
+	"time"
+)
+
+// FollowRepository 定义了用户关注关系数据访问层的接口
+type FollowRepository interface {
+	// CreateFollow 创建关注关系
+	// 参数:
+	// - ctx: 上下文
+	// - followerID: 关注者用户ID
+	// - followeeID: 被关注者用户ID
+	// 返回:
+	// - bool: 是否新建了关注关系,已关注时返回false
+	// - error: 错误信息
+	CreateFollow(ctx context.Context, followerID, followeeID int) (bool, error)
+
+	// DeleteFollow 删除关注关系
+	// 参数:
+	// - ctx: 上下文
+	// - followerID: 关注者用户ID
+	// - followeeID: 被关注者用户ID
+	// 返回:
+	// - bool: 是否删除了关注关系,未关注时返回false
+	// - error: 错误信息
+	DeleteFollow(ctx context.Context, followerID, followeeID int) (bool, error)
+
+	// IsFollowing 判断是否已关注
+	// 参数:
+	// - ctx: 上下文
+	// - followerID: 关注者用户ID
+	// - followeeID: 被关注者用户ID
+	// 返回:
+	// - bool: 是否已关注
+	// - error: 错误信息
+	IsFollowing(ctx context.Context, followerID, followeeID int) (bool, error)
+
+	// GetFollowers 分页获取用户的粉丝列表,按关注时间倒序
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - page: 页码
+	// - pageSize: 每页数量
+	// 返回:
+	// - []*entity.FollowUser: 粉丝列表
+	// - error: 错误信息
+	GetFollowers(ctx context.Context, userID int, page, pageSize int) ([]*entity.FollowUser, error)
+
+	// GetFollowing 分页获取用户的关注列表,按关注时间倒序
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - page: 页码
+	// - pageSize: 每页数量
+	// 返回:
+	// - []*entity.FollowUser: 关注列表
+	// - error: 错误信息
+	GetFollowing(ctx context.Context, userID int, page, pageSize int) ([]*entity.FollowUser, error)
+
+	// GetFollowerCount 获取用户的粉丝数量
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - int: 粉丝数量
+	// - error: 错误信息
+	GetFollowerCount(ctx context.Context, userID int) (int, error)
+
+	// GetFollowingCount 获取用户的关注数量
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - int: 关注数量
+	// - error: 错误信息
+	GetFollowingCount(ctx context.Context, userID int) (int, error)
+
+	// MarkFollowNotified 标记关注通知已发送,用于防止反复关注/取消关注刷通知
+	// 参数:
+	// - ctx: 上下文
+	// - followerID: 关注者用户ID
+	// - followeeID: 被关注者用户ID
+	// - ttl: 标记有效期
+	// 返回:
+	// - bool: 是否首次标记,有效期内已标记过时返回false
+	// - error: 错误信息
+	MarkFollowNotified(ctx context.Context, followerID, followeeID int, ttl time.Duration) (bool, error)
+}
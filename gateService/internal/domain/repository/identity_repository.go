@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"gateService/internal/domain/entity"
+	"time"
+)
+
+// IdentityRepository 定义了第三方身份数据访问层的接口
+type IdentityRepository interface {
+	// SaveOAuthState 保存授权请求状态
+	// 参数:
+	// - ctx: 上下文
+	// - state: 授权请求的state参数
+	// - data: 授权请求状态
+	// - ttl: 有效期
+	// 返回:
+	// - error: 错误信息
+	SaveOAuthState(ctx context.Context, state string, data *entity.OAuthState, ttl time.Duration) error
+
+	// ConsumeOAuthState 取出并删除授权请求状态,每个state只能使用一次
+	// 参数:
+	// - ctx: 上下文
+	// - state: 授权请求的state参数
+	// 返回:
+	// - *entity.OAuthState: 授权请求状态,不存在或已过期时返回nil
+	// - error: 错误信息
+	ConsumeOAuthState(ctx context.Context, state string) (*entity.OAuthState, error)
+
+	// SavePendingLink 保存待确认的第三方身份绑定
+	// 参数:
+	// - ctx: 上下文
+	// - token: 确认令牌
+	// - data: 待确认的身份绑定
+	// - ttl: 有效期
+	// 返回:
+	// - error: 错误信息
+	SavePendingLink(ctx context.Context, token string, data *entity.OAuthPendingLink, ttl time.Duration) error
+
+	// ConsumePendingLink 取出并删除待确认的第三方身份绑定,每个确认令牌只能使用一次
+	// 参数:
+	// - ctx: 上下文
+	// - token: 确认令牌
+	// 返回:
+	// - *entity.OAuthPendingLink: 待确认的身份绑定,不存在或已过期时返回nil
+	// - error: 错误信息
+	ConsumePendingLink(ctx context.Context, token string) (*entity.OAuthPendingLink, error)
+
+	// GetUserIdentity 获取第三方身份绑定
+	// 参数:
+	// - ctx: 上下文
+	// - provider: 身份提供方名称
+	// - subject: 用户在身份提供方处的唯一标识
+	// 返回:
+	// - *entity.UserIdentity: 身份绑定,未绑定时返回nil
+	// - error: 错误信息
+	GetUserIdentity(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+
+	// CreateUserIdentity 创建第三方身份绑定
+	// 参数:
+	// - ctx: 上下文
+	// - identity: 身份绑定信息
+	// 返回:
+	// - error: 错误信息
+	CreateUserIdentity(ctx context.Context, identity *entity.UserIdentity) error
+}
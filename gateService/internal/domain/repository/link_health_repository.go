@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"gateService/internal/domain/entity"
+	"time"
+)
+
+// LinkHealthRepository 定义了播放地址健康检查数据访问层的接口
+// 负责抽样缓存的播放源key、协调多实例的检查周期以及记录各来源站点的探测统计
+type LinkHealthRepository interface {
+	// AcquireCheckRound 尝试获取本轮检查的执行权,多个实例同时调用时每个周期只有一个实例成功
+	// 参数:
+	// - ctx: 上下文
+	// - interval: 检查周期
+	// 返回:
+	// - bool: 是否获取到执行权
+	// - error: 错误信息
+	AcquireCheckRound(ctx context.Context, interval time.Duration) (bool, error)
+
+	// SampleVideoSourceKeys 从上次的扫描位置继续扫描播放源缓存key,扫描完一遍后从头开始
+	// 参数:
+	// - ctx: 上下文
+	// - count: 期望返回的数量
+	// 返回:
+	// - []string: 播放源缓存key,数量可能少于count
+	// - error: 错误信息
+	SampleVideoSourceKeys(ctx context.Context, count int) ([]string, error)
+
+	// RecordProbeResults 累加各来源站点的探测结果
+	// 参数:
+	// - ctx: 上下文
+	// - alive: 来源站点到可用次数的映射
+	// - dead: 来源站点到失效次数的映射
+	// 返回:
+	// - error: 错误信息
+	RecordProbeResults(ctx context.Context, alive, dead map[string]int) error
+
+	// UpdateFailureStreaks 更新播放地址的连续探测失败次数
+	// 参数:
+	// - ctx: 上下文
+	// - failed: 本轮确认失效的地址,连续失败次数加1
+	// - recovered: 本轮探测可用的地址,连续失败次数清零
+	// 返回:
+	// - map[string]int64: failed中各地址的连续失败次数
+	// - error: 错误信息
+	UpdateFailureStreaks(ctx context.Context, failed, recovered []string) (map[string]int64, error)
+
+	// GetLinkHealthStats 获取各来源站点的探测统计
+	// 参数:
+	// - ctx: 上下文
+	// 返回:
+	// - []*entity.LinkHealthStat: 按可用率从低到高排列的统计
+	// - error: 错误信息
+	GetLinkHealthStats(ctx context.Context) ([]*entity.LinkHealthStat, error)
+}
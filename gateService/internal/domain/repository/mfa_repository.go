@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"gateService/internal/domain/entity"
+	"time"
+)
+
+// MfaRepository 定义了用户两步验证数据访问层的接口
+type MfaRepository interface {
+	// GetUserMfa 获取用户两步验证配置
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - *entity.UserMfa: 两步验证配置,未设置时返回nil
+	// - error: 错误信息
+	GetUserMfa(ctx context.Context, userID int) (*entity.UserMfa, error)
+
+	// SaveUserMfaSecret 保存待确认的TOTP密钥
+	// 已存在未启用的配置时覆盖其密钥
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - secret: TOTP密钥
+	// 返回:
+	// - error: 错误信息
+	SaveUserMfaSecret(ctx context.Context, userID int, secret string) error
+
+	// EnableUserMfa 启用两步验证,并替换用户的全部恢复码
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - recoveryCodeHashes: 恢复码哈希列表
+	// 返回:
+	// - error: 错误信息
+	EnableUserMfa(ctx context.Context, userID int, recoveryCodeHashes []string) error
+
+	// DisableUserMfa 关闭两步验证,删除密钥及全部恢复码
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - error: 错误信息
+	DisableUserMfa(ctx context.Context, userID int) error
+
+	// UseRecoveryCode 使用恢复码,每个恢复码只能使用一次
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - codeHash: 恢复码哈希
+	// 返回:
+	// - bool: 恢复码是否有效且未使用
+	// - error: 错误信息
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+
+	// MarkTotpStepUsed 标记用户某个TOTP时间步的验证码已被使用,用于防止验证码重放
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - step: TOTP时间步
+	// - ttl: 标记的过期时间
+	// 返回:
+	// - bool: 标记成功返回true,该时间步已被使用过返回false
+	// - error: 错误信息
+	MarkTotpStepUsed(ctx context.Context, userID int, step int64, ttl time.Duration) (bool, error)
+}
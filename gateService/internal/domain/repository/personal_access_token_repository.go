@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"gateService/internal/domain/entity"
+	"time"
+)
+
+// PersonalAccessTokenRepository 定义了个人访问令牌数据访问层的接口
+type PersonalAccessTokenRepository interface {
+	// CreateToken 创建个人访问令牌
+	// 参数:
+	// - ctx: 上下文
+	// - token: 令牌信息,仅包含令牌哈希,创建成功后回填令牌ID
+	// 返回:
+	// - error: 错误信息
+	CreateToken(ctx context.Context, token *entity.PersonalAccessToken) error
+
+	// CountTokensByUserID 统计用户的个人访问令牌数量
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - int: 令牌数量
+	// - error: 错误信息
+	CountTokensByUserID(ctx context.Context, userID int) (int, error)
+
+	// ListTokensByUserID 获取用户的全部个人访问令牌,按创建时间倒序
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - []*entity.PersonalAccessToken: 令牌列表
+	// - error: 错误信息
+	ListTokensByUserID(ctx context.Context, userID int) ([]*entity.PersonalAccessToken, error)
+
+	// GetTokenByHash 通过令牌哈希获取个人访问令牌
+	// 参数:
+	// - ctx: 上下文
+	// - tokenHash: 令牌SHA-256哈希
+	// 返回:
+	// - *entity.PersonalAccessToken: 令牌信息,不存在时返回nil
+	// - error: 错误信息
+	GetTokenByHash(ctx context.Context, tokenHash string) (*entity.PersonalAccessToken, error)
+
+	// UpdateTokenLastUsed 更新令牌最近使用时间
+	// 参数:
+	// - ctx: 上下文
+	// - tokenID: 令牌ID
+	// - usedAt: 使用时间
+	// 返回:
+	// - error: 错误信息
+	UpdateTokenLastUsed(ctx context.Context, tokenID int64, usedAt time.Time) error
+
+	// DeleteToken 删除用户的指定个人访问令牌
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID,只能删除该用户自己的令牌
+	// - tokenID: 令牌ID
+	// 返回:
+	// - bool: 是否删除成功,令牌不存在或不属于该用户时返回false
+	// - error: 错误信息
+	DeleteToken(ctx context.Context, userID int, tokenID int64) (bool, error)
+
+	// DeleteTokensByUserID 删除用户的全部个人访问令牌
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - error: 错误信息
+	DeleteTokensByUserID(ctx context.Context, userID int) error
+}
@@ -395,10 +395,11 @@ type PostCommentRepository interface {
 	// - postID: 帖子ID
 	// - page: 分页页码,从1开始
 	// - pageSize: 每页评论数量
+	// - excludeUserIDs: 需要排除的评论作者ID,为空时不排除
 	// 返回:
 	// - []*entity.PostComment: 评论列表
 	// - error: 获取过程中的错误信息
-	GetPostCommentsByPostID(ctx context.Context, postID int64, page int, pageSize int) ([]*entity.PostComment, error)
+	GetPostCommentsByPostID(ctx context.Context, postID int64, page int, pageSize int, excludeUserIDs []int) ([]*entity.PostComment, error)
 
 	// GetPostCommentsByRootID 获取指定根评论下的所有子评论
 	// 参数:
@@ -406,10 +407,11 @@ type PostCommentRepository interface {
 	// - rootID: 根评论ID
 	// - page: 分页页码,从1开始
 	// - pageSize: 每页评论数量
+	// - excludeUserIDs: 需要排除的评论作者ID,为空时不排除
 	// 返回:
 	// - []*entity.PostComment: 子评论列表
 	// - error: 获取过程中的错误信息
-	GetPostCommentsByRootID(ctx context.Context, rootID int64, page int, pageSize int) ([]*entity.PostComment, error)
+	GetPostCommentsByRootID(ctx context.Context, rootID int64, page int, pageSize int, excludeUserIDs []int) ([]*entity.PostComment, error)
 
 	// GetPostCommentLikesByUserID 获取用户对评论的点赞状态
 	// 参数:
@@ -425,10 +427,11 @@ type PostCommentRepository interface {
 	// - ctx: 上下文
 	// - postID: 帖子ID
 	// - pageSize: 每页评论数量
+	// - excludeUserIDs: 需要排除的评论作者ID,为空时不排除
 	// 返回:
 	// - int: 评论总页数
 	// - error: 获取过程中的错误信息
-	GetCommentTotalPage(ctx context.Context, postID int64, pageSize int) (int, error)
+	GetCommentTotalPage(ctx context.Context, postID int64, pageSize int, excludeUserIDs []int) (int, error)
 
 	// UpdateReplyCount 更新评论的回复数量
 	// 参数:
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"gateService/internal/domain/entity"
+	"time"
 )
 
 // ProgressRepository 定义了观看进度数据访问层的接口
@@ -43,4 +44,25 @@ type ProgressRepository interface {
 	//   - *entity.Progress: 观看进度记录
 	//   - error: 可能的错误信息
 	GetUserWatchProgress(ctx context.Context, userID, videoID int) (*entity.Progress, error)
+
+	// ListRecentlyWatchedEpisodes 获取最近被观看的剧集,按最近观看时间倒序
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - window: 只统计该时长内更新的观看进度
+	//   - limit: 返回数量
+	// 返回:
+	//   - []*entity.WatchedEpisode: 剧集列表
+	//   - error: 可能的错误信息
+	ListRecentlyWatchedEpisodes(ctx context.Context, window time.Duration, limit int) ([]*entity.WatchedEpisode, error)
+
+	// GetRecentlyWatchedEpisode 获取用户在指定时长内观看过的特定视频的集数
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - userID: 用户ID
+	//   - videoID: 视频ID
+	//   - window: 只查找该时长内更新的观看进度
+	// 返回:
+	//   - string: 最近观看的集数,没有观看进度或进度更新时间超出时长时为空
+	//   - error: 可能的错误信息
+	GetRecentlyWatchedEpisode(ctx context.Context, userID, videoID int, window time.Duration) (string, error)
 }
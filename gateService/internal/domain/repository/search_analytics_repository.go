@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"gateService/internal/domain/entity"
+	"time"
+)
+
+// SearchAnalyticsRepository 定义了搜索统计数据访问层的接口
+// 搜索热度按时间衰减,近期的搜索权重更高
+type SearchAnalyticsRepository interface {
+	// RecordSearch 记录一次搜索
+	// 同一用户在一段时间内重复搜索同一词只统计一次
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 搜索用户ID
+	// - query: 归一化后的搜索词
+	// - zeroResult: 是否没有搜索结果,无结果的搜索词计入无结果统计,不计入热门搜索
+	// 返回:
+	// - error: 错误信息
+	RecordSearch(ctx context.Context, userID int, query string, zeroResult bool) error
+
+	// GetTrendingQueries 获取热门搜索词
+	// 参数:
+	// - ctx: 上下文
+	// - limit: 返回数量
+	// - minUsers: 最少搜索用户数,近期搜索用户不足的搜索词不返回,避免少数用户刷热门
+	// 返回:
+	// - []*entity.SearchQueryStat: 按热度从高到低排列的搜索词
+	// - error: 错误信息
+	GetTrendingQueries(ctx context.Context, limit, minUsers int) ([]*entity.SearchQueryStat, error)
+
+	// GetZeroResultQueries 分页获取无结果的搜索词
+	// 参数:
+	// - ctx: 上下文
+	// - offset: 跳过的数量
+	// - limit: 返回数量
+	// 返回:
+	// - []*entity.SearchQueryStat: 按热度从高到低排列的搜索词
+	// - int64: 无结果搜索词总数
+	// - error: 错误信息
+	GetZeroResultQueries(ctx context.Context, offset, limit int) ([]*entity.SearchQueryStat, int64, error)
+
+	// DecayScores 按经过的时间衰减全部搜索热度,并清理热度过低的搜索词
+	// 多个实例同时调用时,每个周期只有一个实例执行衰减
+	// 参数:
+	// - ctx: 上下文
+	// - interval: 衰减周期
+	// - halfLife: 半衰期
+	// 返回:
+	// - bool: 本次是否执行了衰减
+	// - error: 错误信息
+	DecayScores(ctx context.Context, interval, halfLife time.Duration) (bool, error)
+}
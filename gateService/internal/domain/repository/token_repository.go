@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"gateService/internal/domain/entity"
+	"time"
+)
+
+// RefreshRotateResult 刷新令牌轮换结果
+type RefreshRotateResult int
+
+const (
+	RefreshRotated        RefreshRotateResult = iota // 轮换成功
+	RefreshFamilyNotFound                            // 令牌家族不存在(已过期或已吊销)
+	RefreshTokenReused                               // 已轮换的旧令牌被再次使用,令牌家族已被吊销
+)
+
+// TokenRepository 定义了认证令牌状态存储的接口
+// 刷新令牌以家族(一次登录派生出的所有刷新令牌)为单位存储于Redis,
+// 每个家族只记录当前有效的令牌ID,用于轮换和重用检测,同时作为登录会话记录设备信息;
+// 访问令牌通过jti黑名单和用户级"吊销此前签发的全部令牌"标记实现服务端吊销
+type TokenRepository interface {
+	// CreateRefreshFamily 创建刷新令牌家族,同时记录登录会话信息
+	// 参数:
+	// - ctx: 上下文
+	// - session: 会话信息,包含会话ID(即令牌家族ID)、用户ID和设备信息
+	// - tokenID: 当前有效的刷新令牌ID
+	// - ttl: 过期时间
+	// 返回:
+	// - error: 错误信息
+	CreateRefreshFamily(ctx context.Context, session *entity.Session, tokenID string, ttl time.Duration) error
+
+	// ListSessions 获取用户的全部登录会话,按最近活跃时间倒序
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - []*entity.Session: 会话列表
+	// - error: 错误信息
+	ListSessions(ctx context.Context, userID int) ([]*entity.Session, error)
+
+	// TouchSession 检查会话是否有效,并按最小间隔更新最近活跃时间
+	// 参数:
+	// - ctx: 上下文
+	// - familyID: 会话ID(令牌家族ID)
+	// - now: 当前时间
+	// - interval: 最近活跃时间的最小更新间隔,避免每次请求都写入
+	// 返回:
+	// - bool: 会话是否仍然有效
+	// - error: 错误信息
+	TouchSession(ctx context.Context, familyID string, now time.Time, interval time.Duration) (bool, error)
+
+	// RotateRefreshToken 轮换刷新令牌
+	// 仅当tokenID为家族当前令牌时才替换为newTokenID,否则视为重用并吊销整个家族
+	// 参数:
+	// - ctx: 上下文
+	// - familyID: 令牌家族ID
+	// - tokenID: 请求携带的刷新令牌ID
+	// - newTokenID: 新的刷新令牌ID
+	// - ttl: 过期时间
+	// 返回:
+	// - RefreshRotateResult: 轮换结果
+	// - error: 错误信息
+	RotateRefreshToken(ctx context.Context, familyID, tokenID, newTokenID string, ttl time.Duration) (RefreshRotateResult, error)
+
+	// RevokeRefreshFamily 吊销刷新令牌家族
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - familyID: 令牌家族ID
+	// 返回:
+	// - error: 错误信息
+	RevokeRefreshFamily(ctx context.Context, userID int, familyID string) error
+
+	// RevokeUserRefreshFamilies 吊销用户的全部刷新令牌家族
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - error: 错误信息
+	RevokeUserRefreshFamilies(ctx context.Context, userID int) error
+
+	// RevokeOtherRefreshFamilies 吊销用户除指定家族外的全部刷新令牌家族
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - keepFamilyID: 需要保留的令牌家族ID
+	// 返回:
+	// - int: 吊销的家族数量
+	// - error: 错误信息
+	RevokeOtherRefreshFamilies(ctx context.Context, userID int, keepFamilyID string) (int, error)
+
+	// DenyToken 将访问令牌加入黑名单
+	// 参数:
+	// - ctx: 上下文
+	// - tokenID: 令牌ID(jti)
+	// - ttl: 黑名单保留时间,应不短于令牌剩余有效期
+	// 返回:
+	// - error: 错误信息
+	DenyToken(ctx context.Context, tokenID string, ttl time.Duration) error
+
+	// RevokeUserTokens 吊销用户在指定时间之前签发的全部令牌,同时吊销其全部刷新令牌家族
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - before: 吊销时间点
+	// - ttl: 吊销标记保留时间,应不短于令牌最长有效期
+	// 返回:
+	// - error: 错误信息
+	RevokeUserTokens(ctx context.Context, userID int, before time.Time, ttl time.Duration) error
+
+	// IsTokenRevoked 检查令牌是否已被吊销
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - tokenID: 令牌ID(jti),为空时仅检查用户级吊销标记
+	// - issuedAt: 令牌签发时间
+	// 返回:
+	// - bool: 是否已被吊销
+	// - error: 错误信息
+	IsTokenRevoked(ctx context.Context, userID int, tokenID string, issuedAt time.Time) (bool, error)
+}
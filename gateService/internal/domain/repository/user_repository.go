@@ -79,14 +79,70 @@ type UserRepository interface {
 	// - error: 错误信息
 	IsExistUser(ctx context.Context, email string) (bool, error)
 
-	// VerifyUser 验证用户信息
+	// GetUserCredentialByEmail 通过邮箱查询正常状态用户的登录凭证
 	// 参数:
 	// - ctx: 上下文
-	// - user: 待验证的用户信息
+	// - email: 用户邮箱
+	// 返回:
+	// - *entity.UserInfo: 用户信息(含密码哈希),用户不存在时返回nil
+	// - error: 错误信息
+	GetUserCredentialByEmail(ctx context.Context, email string) (*entity.UserInfo, error)
+
+	// UpdateUserPassword 更新用户密码哈希
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - passwordHash: 密码哈希
+	// 返回:
+	// - error: 错误信息
+	UpdateUserPassword(ctx context.Context, userID int, passwordHash string) error
+
+	// ConvertTestAccount 将体验账号转为正式账号,更新用户名、邮箱和密码
+	// 仅当用户仍为正常状态的体验账号时更新,与DeleteTestAccount互斥
+	// 参数:
+	// - ctx: 上下文
+	// - user: 用户信息,包含用户ID、新用户名、新邮箱和密码哈希
+	// 返回:
+	// - bool: 是否转换成功,体验账号已被删除或已转换时返回false
+	// - error: 错误信息
+	ConvertTestAccount(ctx context.Context, user *entity.UserInfo) (bool, error)
+
+	// DeleteTestAccount 删除体验账号
+	// 仅当用户仍为体验账号时删除,已转为正式账号的用户不受影响
+	// 参数:
+	// - ctx: 上下文
+	// - tx: 事务
+	// - userID: 用户ID
+	// 返回:
+	// - bool: 是否删除成功,用户已转为正式账号或已删除时返回false
+	// - error: 错误信息
+	DeleteTestAccount(ctx context.Context, tx *sql.Tx, userID int) (bool, error)
+
+	// GetUserRole 获取用户当前角色
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
 	// 返回:
-	// - bool: 验证是否通过
+	// - string: 用户角色,用户不存在或已删除时返回空字符串
 	// - error: 错误信息
-	VerifyUser(ctx context.Context, user *entity.UserInfo) (bool, error)
+	GetUserRole(ctx context.Context, userID int) (string, error)
+
+	// UpdateUserRole 更新用户角色,角色变更立即生效
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// - role: 新角色
+	// 返回:
+	// - error: 错误信息
+	UpdateUserRole(ctx context.Context, userID int, role string) error
+
+	// DeleteUserRoleCache 删除用户角色缓存,用户被删除后立即不再具有原角色
+	// 参数:
+	// - ctx: 上下文
+	// - userID: 用户ID
+	// 返回:
+	// - error: 错误信息
+	DeleteUserRoleCache(ctx context.Context, userID int) error
 
 	// GetUsersByIDs 通过用户ID列表批量查询用户信息
 	// 参数:
@@ -146,4 +202,22 @@ type UserRepository interface {
 	// 返回:
 	// - error: 错误信息
 	SetInRedis(ctx context.Context, key string, value int, ttl time.Duration) error
+
+	// IncrInRedis 对Redis中的计数键加一,首次创建时设置过期时间
+	// 参数:
+	// - ctx: 上下文
+	// - key: 键
+	// - ttl: 过期时间
+	// 返回:
+	// - int: 加一后的计数
+	// - error: 错误信息
+	IncrInRedis(ctx context.Context, key string, ttl time.Duration) (int, error)
+
+	// DeleteInRedis 删除Redis中的键
+	// 参数:
+	// - ctx: 上下文
+	// - key: 键
+	// 返回:
+	// - error: 错误信息
+	DeleteInRedis(ctx context.Context, key string) error
 }
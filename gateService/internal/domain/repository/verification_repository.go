@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// VerificationRepository 定义了邮箱验证码等一次性凭证存储的接口,仅使用Redis
+type VerificationRepository interface {
+	// SaveVerificationCode 保存验证码,覆盖同一场景下该邮箱此前的验证码
+	// 参数:
+	// - ctx: 上下文
+	// - scene: 验证码使用场景,如register
+	// - email: 邮箱
+	// - code: 验证码
+	// - ttl: 有效期
+	// 返回:
+	// - error: 错误信息
+	SaveVerificationCode(ctx context.Context, scene, email, code string, ttl time.Duration) error
+
+	// VerifyVerificationCode 校验并消费验证码
+	// 校验成功后验证码立即失效;连续失败达到maxAttempts次后验证码同样失效
+	// 参数:
+	// - ctx: 上下文
+	// - scene: 验证码使用场景
+	// - email: 邮箱
+	// - code: 待校验的验证码
+	// - maxAttempts: 最大尝试次数
+	// 返回:
+	// - bool: 是否校验通过
+	// - error: 错误信息
+	VerifyVerificationCode(ctx context.Context, scene, email, code string, maxAttempts int) (bool, error)
+
+	// IncrSendCount 累加某个来源在时间窗口内的发送次数
+	// 参数:
+	// - ctx: 上下文
+	// - source: 发送来源,如客户端IP
+	// - window: 统计窗口
+	// 返回:
+	// - int64: 当前窗口内的发送次数(含本次)
+	// - error: 错误信息
+	IncrSendCount(ctx context.Context, source string, window time.Duration) (int64, error)
+
+	// SaveResetToken 保存密码重置令牌,同时使该用户此前未使用的重置令牌失效
+	// 参数:
+	// - ctx: 上下文
+	// - tokenHash: 重置令牌的哈希值
+	// - userID: 用户ID
+	// - ttl: 有效期
+	// 返回:
+	// - error: 错误信息
+	SaveResetToken(ctx context.Context, tokenHash string, userID int, ttl time.Duration) error
+
+	// ConsumeResetToken 消费密码重置令牌,令牌只能使用一次
+	// 参数:
+	// - ctx: 上下文
+	// - tokenHash: 重置令牌的哈希值
+	// 返回:
+	// - int: 令牌对应的用户ID,令牌不存在或已失效时返回0
+	// - error: 错误信息
+	ConsumeResetToken(ctx context.Context, tokenHash string) (int, error)
+}
@@ -3,19 +3,21 @@ package repository
 import (
 	"context"
 	"gateService/internal/domain/entity"
+	"time"
 )
 
 // VideoRepository 定义了视频仓储的接口规范
 type VideoRepository interface {
 	// 数据库相关操作
 
-	// CreateVideo 创建新的视频记录
+	// CreateVideo 在同一事务中创建新的视频记录及其类型,创建成功后回填视频ID
 	// 参数:
 	//   - ctx: 上下文信息
 	//   - video: 要创建的视频实体
+	//   - genres: 视频类型列表
 	// 返回:
 	//   - error: 可能的错误信息
-	CreateVideo(ctx context.Context, video *entity.Video) error
+	CreateVideo(ctx context.Context, video *entity.Video, genres []string) error
 
 	// UpdateVideo 更新视频信息
 	// 参数:
@@ -25,7 +27,7 @@ type VideoRepository interface {
 	//   - error: 可能的错误信息
 	UpdateVideo(ctx context.Context, video *entity.Video) error
 
-	// DeleteVideo 删除指定ID的视频
+	// DeleteVideo 删除指定ID的视频及其剧集和类型,视频已被评论或收藏时不允许删除
 	// 参数:
 	//   - ctx: 上下文信息
 	//   - videoID: 要删除的视频ID
@@ -33,6 +35,113 @@ type VideoRepository interface {
 	//   - error: 可能的错误信息
 	DeleteVideo(ctx context.Context, videoID int) error
 
+	// GetVideoIDByIdentity 根据名称、上映时间和地区查找视频ID,三者唯一确定一部视频
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - name: 视频名称
+	//   - releaseDate: 上映时间
+	//   - area: 地区
+	// 返回:
+	//   - int: 视频ID,不存在时返回0
+	//   - error: 可能的错误信息
+	GetVideoIDByIdentity(ctx context.Context, name, releaseDate, area string) (int, error)
+
+	// ReplaceAnimeGenres 用给定的类型列表替换视频的全部类型
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	//   - genres: 新的类型列表
+	// 返回:
+	//   - error: 可能的错误信息
+	ReplaceAnimeGenres(ctx context.Context, videoID int, genres []string) error
+
+	// GetAnimeAliases 获取视频的别名列表
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	// 返回:
+	//   - []string: 别名列表
+	//   - error: 可能的错误信息
+	GetAnimeAliases(ctx context.Context, videoID int) ([]string, error)
+
+	// ReplaceAnimeAliases 用给定的别名列表替换视频的全部别名
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	//   - aliases: 新的别名列表
+	// 返回:
+	//   - error: 可能的错误信息
+	ReplaceAnimeAliases(ctx context.Context, videoID int, aliases []string) error
+
+	// GetVideoEpisodes 获取视频的剧集列表,按添加顺序排列
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	// 返回:
+	//   - []*entity.VideoEpisode: 剧集列表
+	//   - error: 可能的错误信息
+	GetVideoEpisodes(ctx context.Context, videoID int) ([]*entity.VideoEpisode, error)
+
+	// ReplaceVideoEpisodes 用给定的剧集列表替换视频的全部剧集
+	// 已存在的剧集只更新地址以保留用户观看进度,列表中没有的剧集会被删除
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	//   - episodes: 新的剧集列表
+	// 返回:
+	//   - error: 可能的错误信息
+	ReplaceVideoEpisodes(ctx context.Context, videoID int, episodes []*entity.VideoEpisode) error
+
+	// GetStoredVideoSources 获取数据库中保存的一集视频的播放地址和最近一次爬取到的播放源
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	//   - episode: 集数
+	// 返回:
+	//   - *entity.StoredVideoSources: 保存的播放地址
+	//   - error: 可能的错误信息,剧集不存在时返回sql.ErrNoRows
+	GetStoredVideoSources(ctx context.Context, videoID int, episode string) (*entity.StoredVideoSources, error)
+
+	// SaveVideoSources 保存一集视频爬取到的播放源并记录爬取时间,剧集不存在时不做任何修改
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	//   - episode: 集数
+	//   - sources: 按评分排序的播放源
+	// 返回:
+	//   - error: 可能的错误信息
+	SaveVideoSources(ctx context.Context, videoID int, episode string, sources []*entity.VideoSource) error
+
+	// RemoveStoredVideoSources 从数据库保存的爬取结果中移除指定地址的播放源
+	// 全部移除时清空爬取结果和爬取时间,避免Redis缓存清空后重新读取到失效的播放源
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	//   - episode: 集数
+	//   - urls: 需要移除的播放地址
+	// 返回:
+	//   - error: 可能的错误信息
+	RemoveStoredVideoSources(ctx context.Context, videoID int, episode string, urls []string) error
+
+	// ClearStoredVideoSources 清空数据库保存的一集视频的爬取结果和爬取时间,不影响管理员指定的播放地址
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	//   - episode: 集数
+	// 返回:
+	//   - error: 可能的错误信息
+	ClearStoredVideoSources(ctx context.Context, videoID int, episode string) error
+
+	// UpdateEpisodeVideoURL 修改一集视频由管理员指定的播放地址
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 视频ID
+	//   - episode: 集数
+	//   - videoURL: 播放地址,为空时取消指定,播放时使用爬取结果
+	// 返回:
+	//   - error: 可能的错误信息
+	UpdateEpisodeVideoURL(ctx context.Context, videoID int, episode string, videoURL string) error
+
 	// GetVideoByID 根据ID获取视频信息
 	// 参数:
 	//   - ctx: 上下文信息
@@ -111,23 +220,197 @@ type VideoRepository interface {
 
 	// Redis相关操作
 
-	// CacheVideoURL 缓存视频URL
+	// CacheVideoSources 缓存一集视频的全部播放源,覆盖已有缓存
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - key: 缓存key
+	//   - sources: 播放源列表,按各自的评分排序
+	// 返回:
+	//   - error: 可能的错误信息
+	CacheVideoSources(ctx context.Context, key string, sources []*entity.VideoSource) error
+
+	// GetVideoSources 获取缓存的播放源,按评分从高到低排序
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - key: 缓存key
+	// 返回:
+	//   - []*entity.VideoSource: 播放源列表,未缓存时为空
+	//   - error: 可能的错误信息
+	GetVideoSources(ctx context.Context, key string) ([]*entity.VideoSource, error)
+
+	// DemoteVideoSource 降低指定播放源的评分
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - key: 缓存key
+	//   - url: 播放地址
+	//   - penalty: 降低的评分
+	// 返回:
+	//   - bool: 播放源是否存在于缓存中
+	//   - error: 可能的错误信息
+	DemoteVideoSource(ctx context.Context, key string, url string, penalty float64) (bool, error)
+
+	// RemoveVideoSources 从缓存中移除指定地址的播放源
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - key: 缓存key
+	//   - urls: 要移除的播放地址
+	// 返回:
+	//   - int64: 剩余的播放源数量
+	//   - error: 可能的错误信息
+	RemoveVideoSources(ctx context.Context, key string, urls []string) (int64, error)
+
+	// DeleteVideoSources 删除缓存的播放源
 	// 参数:
-	//   - context: 上下文信息
-	//   - string: 缓存key
-	//   - string: 视频URL
+	//   - ctx: 上下文信息
+	//   - key: 缓存key
 	// 返回:
 	//   - error: 可能的错误信息
-	CacheVideoURL(context.Context, string, string) error
+	DeleteVideoSources(ctx context.Context, key string) error
 
-	// GetVideoURL 获取缓存的视频URL
+	// MarkBrokenLinkReport 标记一集视频的失效上报,窗口期内只有第一次标记成功
 	// 参数:
-	//   - context: 上下文信息
-	//   - string: 缓存key
+	//   - ctx: 上下文信息
+	//   - key: 上报标记key
+	//   - window: 去重窗口
+	// 返回:
+	//   - bool: 是否为窗口期内的第一次上报
+	//   - error: 可能的错误信息
+	MarkBrokenLinkReport(ctx context.Context, key string, window time.Duration) (bool, error)
+
+	// IncrBrokenLinkCount 对一集视频的失效次数加一,首次计数时设置统计窗口
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - key: 计数key
+	//   - window: 统计窗口
+	// 返回:
+	//   - int: 加一后的失效次数
+	//   - error: 可能的错误信息
+	IncrBrokenLinkCount(ctx context.Context, key string, window time.Duration) (int, error)
+
+	// MarkVideoSourceReport 标记用户对一个播放源的失败上报,窗口期内只有第一次标记成功
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - key: 上报标记key
+	//   - window: 去重窗口
+	// 返回:
+	//   - bool: 是否为窗口期内该用户的第一次上报
+	//   - error: 可能的错误信息
+	MarkVideoSourceReport(ctx context.Context, key string, window time.Duration) (bool, error)
+
+	// IncrVideoReportCount 对用户的上报次数加一,首次计数时设置统计窗口
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - key: 计数key
+	//   - window: 统计窗口
+	// 返回:
+	//   - int: 加一后的上报次数
+	//   - error: 可能的错误信息
+	IncrVideoReportCount(ctx context.Context, key string, window time.Duration) (int, error)
+
+	// MarkVideoPrefetch 标记一集视频已投递预取任务,窗口期内只有第一次标记成功
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - key: 预取标记key
+	//   - window: 去重窗口
+	// 返回:
+	//   - bool: 是否为窗口期内的第一次标记
+	//   - error: 可能的错误信息
+	MarkVideoPrefetch(ctx context.Context, key string, window time.Duration) (bool, error)
+
+	// IncrVideoPrefetchCount 对预取爬取次数加一,首次计数时设置统计窗口
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - key: 计数key,每个统计窗口使用不同的key
+	//   - window: 统计窗口
+	// 返回:
+	//   - int: 加一后的预取爬取次数
+	//   - error: 可能的错误信息
+	IncrVideoPrefetchCount(ctx context.Context, key string, window time.Duration) (int, error)
+
+	// GetCatalogRecordsByIDs 根据视频ID批量获取目录记录,包含类型列表
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoIDs: 视频ID列表
+	// 返回:
+	//   - map[int]*entity.CatalogRecord: 视频ID到目录记录的映射,不存在的视频不包含在内
+	//   - error: 可能的错误信息
+	GetCatalogRecordsByIDs(ctx context.Context, videoIDs []int) (map[int]*entity.CatalogRecord, error)
+
+	// GetCatalogRecordsByIdentities 根据名称、上映时间和地区批量获取目录记录,包含类型列表
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - records: 待匹配的记录,只使用名称、上映时间和地区
+	// 返回:
+	//   - map[string]*entity.CatalogRecord: 唯一标识到已有目录记录的映射,未匹配的记录不包含在内
+	//   - error: 可能的错误信息
+	GetCatalogRecordsByIdentities(ctx context.Context, records []*entity.CatalogRecord) (map[string]*entity.CatalogRecord, error)
+
+	// SaveCatalogRecords 在同一事务中批量保存目录记录
+	// 视频ID为0的记录新建视频并回填ID,其余记录更新基本信息,类型列表不为nil时替换类型列表
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - records: 待保存的记录
+	//   - uploaderID: 新建视频的上传者ID
+	// 返回:
+	//   - error: 可能的错误信息
+	SaveCatalogRecords(ctx context.Context, records []*entity.CatalogRecord, uploaderID int) error
+
+	// ListCatalogRecords 按视频ID升序分页获取目录记录,用于导出
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - afterID: 上一页最后一条记录的视频ID,首页传0
+	//   - limit: 每页数量
+	// 返回:
+	//   - []*entity.CatalogRecord: 目录记录列表
+	//   - error: 可能的错误信息
+	ListCatalogRecords(ctx context.Context, afterID, limit int) ([]*entity.CatalogRecord, error)
+
+	// InvalidateCatalogCache 清除由视频目录派生的缓存
+	// 包括筛选条件、热门类型以及指定视频的全部视频URL缓存
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoID: 发生变更的视频ID,为0时不清除视频URL缓存
+	// 返回:
+	//   - error: 可能的错误信息
+	InvalidateCatalogCache(ctx context.Context, videoID int) error
+
+	// ListSearchDocuments 按视频ID升序分页获取全文检索文档,用于构建索引
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - afterID: 上一页最后一条记录的视频ID,首页传0
+	//   - limit: 每页数量
+	// 返回:
+	//   - []*entity.VideoSearchDocument: 检索文档列表
+	//   - error: 可能的错误信息
+	ListSearchDocuments(ctx context.Context, afterID, limit int) ([]*entity.VideoSearchDocument, error)
+
+	// GetSearchDocumentsByIDs 根据视频ID批量获取全文检索文档,用于增量更新索引
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoIDs: 视频ID列表
+	// 返回:
+	//   - map[int]*entity.VideoSearchDocument: 视频ID到检索文档的映射,不存在的视频不包含在内
+	//   - error: 可能的错误信息
+	GetSearchDocumentsByIDs(ctx context.Context, videoIDs []int) (map[int]*entity.VideoSearchDocument, error)
+
+	// GetVideosWithEpisodesByIDs 根据视频ID批量获取视频详情及剧集,结果顺序与传入的ID顺序一致
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - videoIDs: 视频ID列表
+	// 返回:
+	//   - []*entity.Video: 视频详情列表,不存在的视频不包含在内
+	//   - error: 可能的错误信息
+	GetVideosWithEpisodesByIDs(ctx context.Context, videoIDs []int) ([]*entity.Video, error)
+
+	// ListVideoSuggestions 按视频ID升序分页获取输入提示所需的视频名称和热度
+	// 参数:
+	//   - ctx: 上下文信息
+	//   - afterID: 上一页最后一条记录的视频ID,首页传0
+	//   - limit: 每页数量
 	// 返回:
-	//   - string: 视频URL
+	//   - []*entity.VideoSuggestion: 视频列表
 	//   - error: 可能的错误信息
-	GetVideoURL(context.Context, string) (string, error)
+	ListVideoSuggestions(ctx context.Context, afterID, limit int) ([]*entity.VideoSuggestion, error)
 
 	// AddAnimeCollection 添加用户动漫收藏记录
 	// 参数:
@@ -0,0 +1,48 @@
+// package service 提供了与账号数据导出和注销相关的业务逻辑服务
+package service
+
+import (
+	"context"
+	"gateService/internal/interfaces/dto"
+)
+
+// AccountService 定义了账号数据导出与注销服务的接口
+type AccountService interface {
+	// RequestDataExport 发起用户数据导出
+	// 导出任务通过消息队列异步执行,生成包含各类数据JSON文件的ZIP
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 发起导出请求参数,包含用户ID
+	// 返回:
+	// - *dto.RequestDataExportResponse: 发起导出响应数据,包含导出任务
+	// - error: 发起过程中的错误信息
+	RequestDataExport(ctx context.Context, request *dto.RequestDataExportRequest) (*dto.RequestDataExportResponse, error)
+
+	// GetDataExport 查询用户数据导出任务状态
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 查询请求参数,包含用户ID和任务ID
+	// 返回:
+	// - *dto.GetDataExportResponse: 导出任务响应数据
+	// - error: 查询过程中的错误信息
+	GetDataExport(ctx context.Context, request *dto.GetDataExportRequest) (*dto.GetDataExportResponse, error)
+
+	// DownloadDataExport 获取已完成的用户数据导出文件
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 下载请求参数,包含用户ID和任务ID
+	// 返回:
+	// - *dto.DownloadDataExportResponse: 导出文件路径和下载文件名
+	// - error: 获取过程中的错误信息
+	DownloadDataExport(ctx context.Context, request *dto.DownloadDataExportRequest) (*dto.DownloadDataExportResponse, error)
+
+	// DeleteAccount 注销账号
+	// 校验密码及两步验证后,在同一事务中匿名化或删除该用户在各业务表中的数据,并吊销全部令牌
+	// 参数:
+	// - ctx: 上下文信息,需为gin.Context以清除Cookie
+	// - request: 注销请求参数,包含用户ID、密码和两步验证码
+	// 返回:
+	// - *dto.DeleteAccountResponse: 注销响应数据
+	// - error: 注销过程中的错误信息
+	DeleteAccount(ctx context.Context, request *dto.DeleteAccountRequest) (*dto.DeleteAccountResponse, error)
+}
@@ -0,0 +1,66 @@
+// package service 提供了与用户屏蔽关系相关的业务逻辑服务
+package service
+
+import (
+	"context"
+	"gateService/internal/interfaces/dto"
+)
+
+// BlockService 定义了用户拉黑与静音服务的接口
+type BlockService interface {
+	// Block 拉黑用户
+	// 拉黑后对方不能回复或关注自己,双方已有的关注关系会被解除,也不再收到对方的通知
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 拉黑请求参数,包含当前用户ID和目标用户ID
+	// 返回:
+	// - *dto.BlockResponse: 拉黑响应数据
+	// - error: 拉黑过程中的错误信息
+	Block(ctx context.Context, request *dto.BlockRequest) (*dto.BlockResponse, error)
+
+	// Unblock 取消拉黑用户
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 取消拉黑请求参数,包含当前用户ID和目标用户ID
+	// 返回:
+	// - *dto.BlockResponse: 取消拉黑响应数据
+	// - error: 取消拉黑过程中的错误信息
+	Unblock(ctx context.Context, request *dto.BlockRequest) (*dto.BlockResponse, error)
+
+	// Mute 静音用户
+	// 静音后不再收到对方的通知,浏览评论时可选择隐藏对方的内容
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 静音请求参数,包含当前用户ID和目标用户ID
+	// 返回:
+	// - *dto.BlockResponse: 静音响应数据
+	// - error: 静音过程中的错误信息
+	Mute(ctx context.Context, request *dto.BlockRequest) (*dto.BlockResponse, error)
+
+	// Unmute 取消静音用户
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 取消静音请求参数,包含当前用户ID和目标用户ID
+	// 返回:
+	// - *dto.BlockResponse: 取消静音响应数据
+	// - error: 取消静音过程中的错误信息
+	Unmute(ctx context.Context, request *dto.BlockRequest) (*dto.BlockResponse, error)
+
+	// GetBlockedUsers 获取拉黑列表
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 列表请求参数,包含当前用户ID和分页信息
+	// 返回:
+	// - *dto.BlockListResponse: 拉黑列表响应数据
+	// - error: 获取过程中的错误信息
+	GetBlockedUsers(ctx context.Context, request *dto.BlockListRequest) (*dto.BlockListResponse, error)
+
+	// GetMutedUsers 获取静音列表
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 列表请求参数,包含当前用户ID和分页信息
+	// 返回:
+	// - *dto.BlockListResponse: 静音列表响应数据
+	// - error: 获取过程中的错误信息
+	GetMutedUsers(ctx context.Context, request *dto.BlockListRequest) (*dto.BlockListResponse, error)
+}
@@ -0,0 +1,124 @@
+// package service 提供了视频目录管理相关的业务逻辑服务
+package service
+
+import (
+	"context"
+	"gateService/internal/interfaces/dto"
+	"io"
+)
+
+// CatalogService 定义了管理后台维护视频目录的服务接口
+// 所有修改操作完成后都会清除由目录派生的缓存
+type CatalogService interface {
+	// ListVideos 按名称搜索视频
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 列表请求参数,包含名称关键字和页码
+	// 返回:
+	// - *dto.CatalogVideoListResponse: 视频列表响应数据
+	// - error: 获取过程中的错误信息
+	ListVideos(ctx context.Context, request *dto.CatalogVideoListRequest) (*dto.CatalogVideoListResponse, error)
+
+	// GetVideoDetail 获取视频详情,包含类型和带播放地址的剧集列表
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 请求参数,包含视频ID
+	// 返回:
+	// - *dto.CatalogVideoDetailResponse: 视频详情响应数据
+	// - error: 获取过程中的错误信息
+	GetVideoDetail(ctx context.Context, request *dto.CatalogVideoRequest) (*dto.CatalogVideoDetailResponse, error)
+
+	// CreateVideo 创建视频
+	// 名称、上映时间和地区相同的视频已存在或未提供类型时创建失败
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 创建请求参数,包含视频基本信息和类型列表
+	// 返回:
+	// - *dto.CatalogVideoSaveResponse: 创建响应数据,包含新视频ID
+	// - error: 创建过程中的错误信息
+	CreateVideo(ctx context.Context, request *dto.CatalogVideoSaveRequest) (*dto.CatalogVideoSaveResponse, error)
+
+	// UpdateVideo 更新视频基本信息,类型列表不为空时一并替换
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 更新请求参数,包含视频ID和新的基本信息
+	// 返回:
+	// - *dto.CatalogVideoSaveResponse: 更新响应数据
+	// - error: 更新过程中的错误信息
+	UpdateVideo(ctx context.Context, request *dto.CatalogVideoSaveRequest) (*dto.CatalogVideoSaveResponse, error)
+
+	// DeleteVideo 删除视频及其剧集和类型,已被评论或收藏的视频不允许删除
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 请求参数,包含视频ID
+	// 返回:
+	// - *dto.CatalogResponse: 删除响应数据
+	// - error: 删除过程中的错误信息
+	DeleteVideo(ctx context.Context, request *dto.CatalogVideoRequest) (*dto.CatalogResponse, error)
+
+	// UpdateGenres 替换视频的类型列表
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 请求参数,包含视频ID和新的类型列表
+	// 返回:
+	// - *dto.CatalogResponse: 响应数据
+	// - error: 替换过程中的错误信息
+	UpdateGenres(ctx context.Context, request *dto.CatalogGenresRequest) (*dto.CatalogResponse, error)
+
+	// UpdateAliases 替换视频的别名列表,别名参与全文检索
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 请求参数,包含视频ID和新的别名列表
+	// 返回:
+	// - *dto.CatalogResponse: 响应数据
+	// - error: 替换过程中的错误信息
+	UpdateAliases(ctx context.Context, request *dto.CatalogAliasesRequest) (*dto.CatalogResponse, error)
+
+	// UpdateEpisodes 替换视频的剧集列表
+	// 保留的剧集不影响用户观看进度,被移除剧集的观看进度会一并删除
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 请求参数,包含视频ID和新的剧集列表
+	// 返回:
+	// - *dto.CatalogResponse: 响应数据
+	// - error: 替换过程中的错误信息
+	UpdateEpisodes(ctx context.Context, request *dto.CatalogEpisodesRequest) (*dto.CatalogResponse, error)
+
+	// GetEpisodeURL 查看一集视频的播放地址,包含数据库中保存的地址和Redis中缓存的播放源
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 请求参数,包含视频ID和集数
+	// 返回:
+	// - *dto.CatalogEpisodeURLResponse: 播放地址响应数据
+	// - error: 查询过程中的错误信息
+	GetEpisodeURL(ctx context.Context, request *dto.CatalogEpisodeURLRequest) (*dto.CatalogEpisodeURLResponse, error)
+
+	// UpdateEpisodeURL 指定一集视频的播放地址,指定的地址优先于爬取结果且立即生效
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 请求参数,包含视频ID、集数和播放地址,地址为空时取消指定
+	// 返回:
+	// - *dto.CatalogResponse: 响应数据
+	// - error: 修改过程中的错误信息
+	UpdateEpisodeURL(ctx context.Context, request *dto.CatalogEpisodeURLUpdateRequest) (*dto.CatalogResponse, error)
+
+	// ImportCatalog 从CSV或JSON文件批量导入视频及其类型
+	// 按视频ID或名称、上映时间和地区匹配已有视频,生成新建、更新和冲突的差异报告
+	// 非试运行且没有无效或冲突的记录时,分批在事务中写入全部变更
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 导入请求参数,包含导入文件、文件格式和是否试运行
+	// 返回:
+	// - *dto.CatalogImportResponse: 导入响应数据,包含差异报告
+	// - error: 导入过程中的错误信息
+	ImportCatalog(ctx context.Context, request *dto.CatalogImportRequest) (*dto.CatalogImportResponse, error)
+
+	// ExportCatalog 将全部视频及其类型按CSV或JSON格式写出,导出的文件可直接用于导入
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 导出请求参数,包含文件格式
+	// - writer: 导出内容的写入目标
+	// 返回:
+	// - error: 导出过程中的错误信息
+	ExportCatalog(ctx context.Context, request *dto.CatalogExportRequest, writer io.Writer) error
+}
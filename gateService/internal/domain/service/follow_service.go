@@ -0,0 +1,47 @@
+// package service 提供了与用户关注关系相关的业务逻辑服务
+package service
+
+import (
+	"context"
+	"gateService/internal/interfaces/dto"
+)
+
+// FollowService 定义了用户关注服务的接口
+type FollowService interface {
+	// Follow 关注用户
+	// 首次关注时通过消息队列异步创建关注通知并实时推送给被关注者
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 关注请求参数,包含当前用户ID和被关注的用户ID
+	// 返回:
+	// - *dto.FollowResponse: 关注响应数据
+	// - error: 关注过程中的错误信息
+	Follow(ctx context.Context, request *dto.FollowRequest) (*dto.FollowResponse, error)
+
+	// Unfollow 取消关注用户
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 取消关注请求参数,包含当前用户ID和被取消关注的用户ID
+	// 返回:
+	// - *dto.FollowResponse: 取消关注响应数据
+	// - error: 取消关注过程中的错误信息
+	Unfollow(ctx context.Context, request *dto.FollowRequest) (*dto.FollowResponse, error)
+
+	// GetFollowers 获取粉丝列表
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 列表请求参数,包含查询的用户ID和分页信息
+	// 返回:
+	// - *dto.FollowListResponse: 粉丝列表响应数据
+	// - error: 获取过程中的错误信息
+	GetFollowers(ctx context.Context, request *dto.FollowListRequest) (*dto.FollowListResponse, error)
+
+	// GetFollowing 获取关注列表
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 列表请求参数,包含查询的用户ID和分页信息
+	// 返回:
+	// - *dto.FollowListResponse: 关注列表响应数据
+	// - error: 获取过程中的错误信息
+	GetFollowing(ctx context.Context, request *dto.FollowListRequest) (*dto.FollowListResponse, error)
+}
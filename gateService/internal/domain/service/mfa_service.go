@@ -0,0 +1,50 @@
+// package service 提供了与两步验证相关的业务逻辑服务
+package service
+
+import (
+	"context"
+	"gateService/internal/interfaces/dto"
+)
+
+// MfaService 定义了两步验证服务的接口
+// 提供TOTP绑定、确认、关闭以及状态查询功能
+type MfaService interface {
+	// GetMfaStatus 获取用户两步验证状态
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 获取状态请求参数,包含用户ID
+	// 返回:
+	// - *dto.GetMfaStatusResponse: 两步验证状态响应数据
+	// - error: 获取过程中的错误信息
+	GetMfaStatus(ctx context.Context, request *dto.GetMfaStatusRequest) (*dto.GetMfaStatusResponse, error)
+
+	// SetupMfa 开始绑定两步验证
+	// 生成新的TOTP密钥和otpauth地址,确认前不会生效
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 绑定请求参数,包含用户ID
+	// 返回:
+	// - *dto.SetupMfaResponse: 绑定响应数据,包含密钥和otpauth地址
+	// - error: 绑定过程中的错误信息
+	SetupMfa(ctx context.Context, request *dto.SetupMfaRequest) (*dto.SetupMfaResponse, error)
+
+	// ConfirmMfa 确认绑定两步验证
+	// 校验验证器应用生成的动态验证码,通过后启用两步验证并生成一次性恢复码
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 确认请求参数,包含用户ID和动态验证码
+	// 返回:
+	// - *dto.ConfirmMfaResponse: 确认响应数据,包含恢复码
+	// - error: 确认过程中的错误信息
+	ConfirmMfa(ctx context.Context, request *dto.ConfirmMfaRequest) (*dto.ConfirmMfaResponse, error)
+
+	// DisableMfa 关闭两步验证
+	// 需同时校验当前密码和动态验证码(或恢复码)
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 关闭请求参数,包含用户ID、密码和验证码
+	// 返回:
+	// - *dto.DisableMfaResponse: 关闭响应数据
+	// - error: 关闭过程中的错误信息
+	DisableMfa(ctx context.Context, request *dto.DisableMfaRequest) (*dto.DisableMfaResponse, error)
+}
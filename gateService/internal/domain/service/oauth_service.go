@@ -0,0 +1,51 @@
+// package service 提供了与第三方登录相关的业务逻辑服务
+package service
+
+import (
+	"context"
+	"gateService/internal/interfaces/dto"
+)
+
+// OAuthService 定义了第三方登录服务的接口
+// 基于OAuth2授权码模式(PKCE)和OpenID Connect,登录成功后与密码登录一样签发会话令牌
+type OAuthService interface {
+	// GetProviders 获取已启用的身份提供方
+	// 参数:
+	// - ctx: 上下文信息
+	// 返回:
+	// - *dto.OAuthProvidersResponse: 身份提供方名称列表
+	// - error: 获取过程中的错误信息
+	GetProviders(ctx context.Context) (*dto.OAuthProvidersResponse, error)
+
+	// GetAuthorizationURL 发起第三方登录
+	// 生成state、nonce和PKCE校验码并保存到Redis,返回身份提供方授权地址
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 发起登录请求参数,包含身份提供方名称和登录后跳转路径
+	// 返回:
+	// - *dto.OAuthLoginResponse: 发起登录响应数据,包含授权地址
+	// - error: 发起过程中的错误信息
+	GetAuthorizationURL(ctx context.Context, request *dto.OAuthLoginRequest) (*dto.OAuthLoginResponse, error)
+
+	// HandleCallback 处理身份提供方回调
+	// 校验state并换取身份,已绑定时直接登录,未绑定且邮箱未注册时创建新账号;
+	// 邮箱已注册时返回绑定确认令牌,需调用ConfirmLink输入本地账号密码后才绑定;
+	// 开启两步验证的账号仅返回待确认令牌
+	// 参数:
+	// - ctx: 上下文信息,需为gin.Context以写入Cookie
+	// - request: 回调请求参数,包含授权码和state
+	// 返回:
+	// - *dto.OAuthCallbackResponse: 回调响应数据,包含跳转地址
+	// - error: 处理过程中的错误信息
+	HandleCallback(ctx context.Context, request *dto.OAuthCallbackRequest) (*dto.OAuthCallbackResponse, error)
+
+	// ConfirmLink 确认将第三方身份绑定到邮箱相同的已有账号
+	// 校验本地账号密码后绑定并登录,确认令牌只能使用一次;开启两步验证的账号仅返回待确认令牌
+	// 参数:
+	// - ctx: 上下文信息,需为gin.Context以写入Cookie
+	// - request: 确认请求参数,包含确认令牌和本地账号密码
+	// 返回:
+	// - *dto.OAuthLinkConfirmResponse: 确认响应数据,包含跳转地址
+	// - error: 确认过程中的错误信息
+	ConfirmLink(ctx context.Context, request *dto.OAuthLinkConfirmRequest) (*dto.OAuthLinkConfirmResponse, error)
+}
@@ -0,0 +1,39 @@
+// package service 提供了与个人访问令牌相关的业务逻辑服务
+package service
+
+import (
+	"context"
+	"gateService/internal/interfaces/dto"
+)
+
+// PersonalAccessTokenService 定义了个人访问令牌服务的接口
+// 个人访问令牌用于脚本通过Authorization: Bearer请求头调用接口,访问范围受授权范围限制
+type PersonalAccessTokenService interface {
+	// CreateToken 创建个人访问令牌
+	// 令牌明文仅在创建时返回一次,服务端只保存哈希
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 创建令牌请求参数,包含用户ID、名称、授权范围和有效天数
+	// 返回:
+	// - *dto.CreatePersonalAccessTokenResponse: 创建令牌响应数据,包含令牌明文
+	// - error: 创建过程中的错误信息
+	CreateToken(ctx context.Context, request *dto.CreatePersonalAccessTokenRequest) (*dto.CreatePersonalAccessTokenResponse, error)
+
+	// ListTokens 获取当前用户的全部个人访问令牌
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 获取令牌请求参数,包含用户ID
+	// 返回:
+	// - *dto.ListPersonalAccessTokensResponse: 令牌列表响应数据
+	// - error: 获取过程中的错误信息
+	ListTokens(ctx context.Context, request *dto.ListPersonalAccessTokensRequest) (*dto.ListPersonalAccessTokensResponse, error)
+
+	// RevokeToken 吊销当前用户的指定个人访问令牌,吊销后立即失效
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 吊销令牌请求参数,包含用户ID和令牌ID
+	// 返回:
+	// - *dto.RevokePersonalAccessTokenResponse: 吊销令牌响应数据
+	// - error: 吊销过程中的错误信息
+	RevokeToken(ctx context.Context, request *dto.RevokePersonalAccessTokenRequest) (*dto.RevokePersonalAccessTokenResponse, error)
+}
@@ -26,4 +26,31 @@ type SearchService interface {
 	// - *dto.SearchDetailResponse: 详细搜索结果响应,包含视频的完整信息
 	// - error: 搜索过程中的错误信息
 	SearchVideosDetail(ctx context.Context, request *dto.SearchDetailRequest) (*dto.SearchDetailResponse, error)
+
+	// SuggestVideos 根据已输入的文本返回输入提示
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 输入提示请求参数,包含已输入的文本和返回数量
+	// 返回:
+	// - *dto.SearchSuggestResponse: 名称匹配的视频列表,按热度排序
+	// - error: 查询过程中的错误信息
+	SuggestVideos(ctx context.Context, request *dto.SearchSuggestRequest) (*dto.SearchSuggestResponse, error)
+
+	// GetTrendingSearches 获取热门搜索词
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 请求参数,包含返回数量
+	// 返回:
+	// - *dto.SearchTrendingResponse: 按近期搜索热度排序的搜索词
+	// - error: 查询过程中的错误信息
+	GetTrendingSearches(ctx context.Context, request *dto.SearchTrendingRequest) (*dto.SearchTrendingResponse, error)
+
+	// GetZeroResultSearches 分页获取没有搜索结果的搜索词,用于发现目录中缺失的视频
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 请求参数,包含页码
+	// 返回:
+	// - *dto.SearchZeroResultResponse: 按近期搜索热度排序的无结果搜索词
+	// - error: 查询过程中的错误信息
+	GetZeroResultSearches(ctx context.Context, request *dto.SearchZeroResultRequest) (*dto.SearchZeroResultResponse, error)
 }
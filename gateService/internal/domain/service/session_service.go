@@ -0,0 +1,39 @@
+// package service 提供了与登录会话相关的业务逻辑服务
+package service
+
+import (
+	"context"
+	"gateService/internal/interfaces/dto"
+)
+
+// SessionService 定义了登录会话服务的接口
+// 会话即一次登录产生的刷新令牌家族,移除会话后该会话的访问令牌和刷新令牌立即失效
+type SessionService interface {
+	// ListSessions 获取当前用户的全部登录会话
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 获取会话请求参数,包含用户ID和当前会话ID
+	// 返回:
+	// - *dto.ListSessionsResponse: 会话列表响应数据
+	// - error: 获取过程中的错误信息
+	ListSessions(ctx context.Context, request *dto.ListSessionsRequest) (*dto.ListSessionsResponse, error)
+
+	// RevokeSession 移除指定的登录会话
+	// 只能移除当前用户自己的其他会话,退出当前会话请使用登出接口
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 移除会话请求参数,包含用户ID、当前会话ID和需要移除的会话ID
+	// 返回:
+	// - *dto.RevokeSessionResponse: 移除会话响应数据
+	// - error: 移除过程中的错误信息
+	RevokeSession(ctx context.Context, request *dto.RevokeSessionRequest) (*dto.RevokeSessionResponse, error)
+
+	// RevokeOtherSessions 移除除当前会话外的全部登录会话
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 移除会话请求参数,包含用户ID和当前会话ID
+	// 返回:
+	// - *dto.RevokeOtherSessionsResponse: 移除会话响应数据
+	// - error: 移除过程中的错误信息
+	RevokeOtherSessions(ctx context.Context, request *dto.RevokeOtherSessionsRequest) (*dto.RevokeOtherSessionsResponse, error)
+}
@@ -3,9 +3,27 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"gateService/internal/interfaces/dto"
+	"math"
+	"time"
 )
 
+// LoginThrottledError 登录因失败次数过多被限制时返回的错误
+type LoginThrottledError struct {
+	Message    string        // 错误描述
+	RetryAfter time.Duration // 再次尝试前需等待的时间
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s，请%v秒后再试", e.Message, e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds 再次尝试前需等待的秒数,向上取整
+func (e *LoginThrottledError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
 // UserService 定义了用户服务的接口
 // 提供用户注册、登录、登出和验证等基本功能
 type UserService interface {
@@ -18,7 +36,39 @@ type UserService interface {
 	// - error: 注册过程中的错误信息
 	Register(ctx context.Context, user *dto.RegisterRequest) (*dto.RegisterResponse, error)
 
+	// SendVerificationCode 发送注册验证码
+	// 同一邮箱和同一IP均有发送频率限制,邮箱已注册时发送提醒邮件,响应与未注册时一致
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 发送验证码请求数据,包含邮箱和用户IP地址
+	// 返回:
+	// - *dto.SendVerificationCodeResponse: 发送验证码响应数据
+	// - error: 发送过程中的错误信息
+	SendVerificationCode(ctx context.Context, request *dto.SendVerificationCodeRequest) (*dto.SendVerificationCodeResponse, error)
+
+	// ForgotPassword 申请密码重置
+	// 向已注册邮箱发送一次性重置链接,邮箱未注册时同样返回成功
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 申请密码重置请求数据,包含邮箱和用户IP地址
+	// 返回:
+	// - *dto.ForgotPasswordResponse: 申请密码重置响应数据
+	// - error: 申请过程中的错误信息
+	ForgotPassword(ctx context.Context, request *dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, error)
+
+	// ResetPassword 使用重置令牌设置新密码
+	// 重置成功后吊销该用户全部已登录会话
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 重置密码请求数据,包含重置令牌和新密码
+	// 返回:
+	// - *dto.ResetPasswordResponse: 重置密码响应数据
+	// - error: 重置过程中的错误信息
+	ResetPassword(ctx context.Context, request *dto.ResetPasswordRequest) (*dto.ResetPasswordResponse, error)
+
 	// Login 用户登录
+	// 连续失败时按账号和IP分别限流,被限制时返回*LoginThrottledError;
+	// 开启两步验证的用户仅返回待确认令牌,需继续调用LoginMfa
 	// 参数:
 	// - ctx: 上下文信息
 	// - user: 登录请求数据,包含用户名和密码
@@ -27,6 +77,16 @@ type UserService interface {
 	// - error: 登录过程中的错误信息
 	Login(ctx context.Context, user *dto.LoginRequest) (*dto.LoginResponse, error)
 
+	// LoginMfa 两步验证登录
+	// 使用Login返回的待确认令牌和动态验证码(或恢复码)换取正式会话,失败次数计入登录限流
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 两步验证登录请求数据,包含待确认令牌和验证码
+	// 返回:
+	// - *dto.LoginResponse: 登录响应数据,包含用户信息
+	// - error: 登录过程中的错误信息
+	LoginMfa(ctx context.Context, request *dto.LoginMfaRequest) (*dto.LoginResponse, error)
+
 	// Logout 用户登出
 	// 参数:
 	// - ctx: 上下文信息
@@ -34,6 +94,23 @@ type UserService interface {
 	// - error: 登出过程中的错误信息
 	Logout(ctx context.Context) error
 
+	// LogoutAll 退出全部设备
+	// 吊销当前用户此前签发的全部访问令牌和刷新令牌,并删除全部个人访问令牌
+	// 参数:
+	// - ctx: 上下文信息
+	// 返回:
+	// - error: 登出过程中的错误信息
+	LogoutAll(ctx context.Context) error
+
+	// RefreshToken 使用刷新令牌换取新的访问令牌
+	// 刷新令牌每次使用后轮换,已轮换的令牌再次使用时吊销整个令牌家族
+	// 参数:
+	// - ctx: 上下文信息,需为gin.Context以读写Cookie
+	// 返回:
+	// - *dto.RefreshTokenResponse: 刷新响应数据
+	// - error: 刷新过程中的错误信息
+	RefreshToken(ctx context.Context) (*dto.RefreshTokenResponse, error)
+
 	// VerifyUser 验证用户信息
 	// 参数:
 	// - ctx: 上下文信息
@@ -97,6 +174,15 @@ type UserService interface {
 	// - error: 获取用户通知过程中的错误信息
 	GetUserNotifications(ctx context.Context, user *dto.UserNotificationRequest) (*dto.UserNotificationResponse, error)
 
+	// UpdateUserRole 修改用户角色
+	// 参数:
+	// - ctx: 上下文信息
+	// - request: 修改用户角色请求参数,包含用户ID和新角色
+	// 返回:
+	// - *dto.UpdateUserRoleResponse: 修改用户角色响应数据
+	// - error: 修改过程中的错误信息
+	UpdateUserRole(ctx context.Context, request *dto.UpdateUserRoleRequest) (*dto.UpdateUserRoleResponse, error)
+
 	// GetTestAccount 获取体验账号
 	// 参数:
 	// - ctx: 上下文信息
@@ -105,4 +191,14 @@ type UserService interface {
 	// - *dto.TestAccountResponse: 获取体验账号响应数据,包含体验账号邮箱和密码
 	// - error: 获取体验账号过程中的错误信息
 	GetTestAccount(ctx context.Context, user *dto.TestAccountRequest) (*dto.TestAccountResponse, error)
+
+	// ConvertTestAccount 将体验账号转为正式账号
+	// 保留体验期间产生的全部数据,转换后不再被延迟删除,并吊销此前的全部会话后为当前请求签发新会话
+	// 参数:
+	// - ctx: 上下文信息,需为gin.Context以读写Cookie
+	// - request: 转换请求参数,包含用户ID、新邮箱、邮箱验证码和新密码
+	// 返回:
+	// - *dto.ConvertTestAccountResponse: 转换响应数据,包含转换后的用户信息
+	// - error: 转换过程中的错误信息
+	ConvertTestAccount(ctx context.Context, request *dto.ConvertTestAccountRequest) (*dto.ConvertTestAccountResponse, error)
 }
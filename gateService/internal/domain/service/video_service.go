@@ -2,9 +2,20 @@ package service
 
 import (
 	"context"
+	"errors"
+	"gateService/internal/domain/entity"
 	"gateService/internal/interfaces/dto"
 )
 
+// ErrVideoEpisodeNotFound 上报的视频或剧集不存在
+var ErrVideoEpisodeNotFound = errors.New("视频或剧集不存在")
+
+// ErrVideoPrefetchLimited 预取爬取超过全局频率限制
+var ErrVideoPrefetchLimited = errors.New("预取爬取超过频率限制")
+
+// ErrVideoReportLimited 用户上报播放失败或播放地址失效超过频率限制
+var ErrVideoReportLimited = errors.New("上报过于频繁,请稍后再试")
+
 // VideoService 定义了视频服务的接口
 type VideoService interface {
 	// GetVideoInfo 获取视频详细信息
@@ -31,6 +42,39 @@ type VideoService interface {
 	// 返回: 视频URL响应和可能的错误
 	GetVideoURL(ctx context.Context, request *dto.GetVideoURLRequest) (*dto.GetVideoURLResponse, error)
 
+	// ReportVideoSource 上报播放失败的播放源,同一用户对同一播放源只计一次
+	// ctx: 上下文信息
+	// request: 包含用户ID、视频ID、集数和播放失败地址的请求参数
+	// 返回: 重新排序后的视频URL响应和可能的错误,超过频率限制时返回ErrVideoReportLimited
+	ReportVideoSource(ctx context.Context, request *dto.ReportVideoSourceRequest) (*dto.GetVideoURLResponse, error)
+
+	// ReportBrokenLink 上报播放地址失效,删除缓存并重新爬取
+	// ctx: 上下文信息
+	// request: 包含用户ID、视频ID和集数的请求参数
+	// 返回: 重新爬取后的视频URL响应和可能的错误,超过频率限制时返回ErrVideoReportLimited
+	ReportBrokenLink(ctx context.Context, request *dto.ReportBrokenLinkRequest) (*dto.GetVideoURLResponse, error)
+
+	// RefreshVideoSources 在后台爬取一集视频的播放源并写入缓存
+	// ctx: 上下文信息
+	// videoID: 视频ID
+	// episode: 集数
+	// replace: 是否删除缓存并要求爬虫重新爬取
+	// 返回: 可能的错误
+	RefreshVideoSources(ctx context.Context, videoID int, episode string, replace bool) error
+
+	// PrefetchVideoSources 执行下一集预取任务,需要爬取时受全局频率限制
+	// ctx: 上下文信息
+	// videoID: 视频ID
+	// episode: 集数
+	// 返回: 可能的错误,超过频率限制时返回ErrVideoPrefetchLimited
+	PrefetchVideoSources(ctx context.Context, videoID int, episode string) error
+
+	// GetLinkHealthStats 获取各来源站点的播放地址探测统计
+	// ctx: 上下文信息
+	// request: 请求参数
+	// 返回: 探测统计响应和可能的错误
+	GetLinkHealthStats(ctx context.Context, request *dto.GetLinkHealthStatsRequest) (*dto.GetLinkHealthStatsResponse, error)
+
 	// GetHomeAnimes 获取首页动漫列表
 	// ctx: 上下文信息
 	// request: 包含用户ID的请求参数
@@ -39,9 +83,9 @@ type VideoService interface {
 
 	// Response 生成视频URL响应
 	// 参数1: 状态码
-	// 参数2: 视频播放地址
+	// 参数2: 按评分排序的播放源
 	// 返回: 视频URL响应对象
-	Response(int, string) *dto.GetVideoURLResponse
+	Response(int, []*entity.VideoSource) *dto.GetVideoURLResponse
 
 	// AddAnimeCollection 添加动漫收藏
 	// ctx: 上下文信息
@@ -163,6 +163,28 @@ func (p *GRPCClientPool) releaseConn(conn *grpcConn) {
 
 // ScrapeVideoUrl 获取视频URL
 func (p *GRPCClientPool) ScrapeVideoUrl(ctx context.Context, name, release, area, episode string) (*VideoMsg, error) {
+	return p.scrapeVideoUrl(ctx, &VideoParms{
+		Name:    name,
+		Release: release,
+		Area:    area,
+		Episode: episode,
+	})
+}
+
+// RescrapeVideoUrl 重新爬取视频URL
+// 设置replace标记,要求爬虫忽略已保存的结果重新爬取,用于已缓存的地址失效时
+func (p *GRPCClientPool) RescrapeVideoUrl(ctx context.Context, name, release, area, episode string) (*VideoMsg, error) {
+	return p.scrapeVideoUrl(ctx, &VideoParms{
+		Name:    name,
+		Release: release,
+		Area:    area,
+		Episode: episode,
+		Replace: "true",
+	})
+}
+
+// scrapeVideoUrl 从连接池获取连接并执行爬取调用
+func (p *GRPCClientPool) scrapeVideoUrl(ctx context.Context, request *VideoParms) (*VideoMsg, error) {
 	startTime := time.Now()
 	atomic.AddInt64(&p.requestCount, 1)
 
@@ -173,13 +195,6 @@ func (p *GRPCClientPool) ScrapeVideoUrl(ctx context.Context, name, release, area
 	}
 	defer p.releaseConn(conn)
 
-	request := &VideoParms{
-		Name:    name,
-		Release: release,
-		Area:    area,
-		Episode: episode,
-	}
-
 	// 设置超时时间
 	ctx, cancel := context.WithTimeout(ctx, p.cfg.Connection.Timeout)
 	defer cancel()
@@ -102,7 +102,8 @@ type VideoMsg struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Url     string         `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Sources []*VideoSource `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
 }
 
 func (x *VideoMsg) Reset() {
@@ -142,6 +143,82 @@ func (x *VideoMsg) GetUrl() string {
 	return ""
 }
 
+func (x *VideoMsg) GetSources() []*VideoSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type VideoSource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url     string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Quality string `protobuf:"bytes,2,opt,name=quality,proto3" json:"quality,omitempty"`
+	Origin  string `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	Format  string `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+}
+
+func (x *VideoSource) Reset() {
+	*x = VideoSource{}
+	mi := &file_scrape_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VideoSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VideoSource) ProtoMessage() {}
+
+func (x *VideoSource) ProtoReflect() protoreflect.Message {
+	mi := &file_scrape_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VideoSource.ProtoReflect.Descriptor instead.
+func (*VideoSource) Descriptor() ([]byte, []int) {
+	return file_scrape_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VideoSource) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *VideoSource) GetQuality() string {
+	if x != nil {
+		return x.Quality
+	}
+	return ""
+}
+
+func (x *VideoSource) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *VideoSource) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
 var File_scrape_proto protoreflect.FileDescriptor
 
 var file_scrape_proto_rawDesc = []byte{
@@ -154,15 +231,24 @@ var file_scrape_proto_rawDesc = []byte{
 	0x09, 0x52, 0x04, 0x61, 0x72, 0x65, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x70, 0x69, 0x73, 0x6f,
 	0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x70, 0x69, 0x73, 0x6f, 0x64,
 	0x65, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x22, 0x1c, 0x0a, 0x08, 0x76,
+	0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x22, 0x4b, 0x0a, 0x08, 0x76,
 	0x69, 0x64, 0x65, 0x6f, 0x4d, 0x73, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x32, 0x41, 0x0a, 0x05, 0x56, 0x69, 0x64,
-	0x65, 0x6f, 0x12, 0x38, 0x0a, 0x0e, 0x53, 0x63, 0x72, 0x61, 0x70, 0x65, 0x56, 0x69, 0x64, 0x65,
-	0x6f, 0x55, 0x72, 0x6c, 0x12, 0x12, 0x2e, 0x73, 0x63, 0x72, 0x61, 0x70, 0x65, 0x2e, 0x76, 0x69,
-	0x64, 0x65, 0x6f, 0x50, 0x61, 0x72, 0x6d, 0x73, 0x1a, 0x10, 0x2e, 0x73, 0x63, 0x72, 0x61, 0x70,
-	0x65, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x4d, 0x73, 0x67, 0x22, 0x00, 0x42, 0x10, 0x5a, 0x0e,
-	0x2e, 0x2f, 0x73, 0x63, 0x72, 0x61, 0x70, 0x65, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x2d, 0x0a, 0x07, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x63, 0x72,
+	0x61, 0x70, 0x65, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x22, 0x69, 0x0a, 0x0b, 0x76, 0x69, 0x64, 0x65,
+	0x6f, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x71, 0x75, 0x61,
+	0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x71, 0x75, 0x61, 0x6c,
+	0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x66,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72,
+	0x6d, 0x61, 0x74, 0x32, 0x41, 0x0a, 0x05, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x12, 0x38, 0x0a, 0x0e,
+	0x53, 0x63, 0x72, 0x61, 0x70, 0x65, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x55, 0x72, 0x6c, 0x12, 0x12,
+	0x2e, 0x73, 0x63, 0x72, 0x61, 0x70, 0x65, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x50, 0x61, 0x72,
+	0x6d, 0x73, 0x1a, 0x10, 0x2e, 0x73, 0x63, 0x72, 0x61, 0x70, 0x65, 0x2e, 0x76, 0x69, 0x64, 0x65,
+	0x6f, 0x4d, 0x73, 0x67, 0x22, 0x00, 0x42, 0x10, 0x5a, 0x0e, 0x2e, 0x2f, 0x73, 0x63, 0x72, 0x61,
+	0x70, 0x65, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -177,19 +263,21 @@ func file_scrape_proto_rawDescGZIP() []byte {
 	return file_scrape_proto_rawDescData
 }
 
-var file_scrape_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_scrape_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_scrape_proto_goTypes = []any{
-	(*VideoParms)(nil), // 0: scrape.videoParms
-	(*VideoMsg)(nil),   // 1: scrape.videoMsg
+	(*VideoParms)(nil),  // 0: scrape.videoParms
+	(*VideoMsg)(nil),    // 1: scrape.videoMsg
+	(*VideoSource)(nil), // 2: scrape.videoSource
 }
 var file_scrape_proto_depIdxs = []int32{
-	0, // 0: scrape.Video.ScrapeVideoUrl:input_type -> scrape.videoParms
-	1, // 1: scrape.Video.ScrapeVideoUrl:output_type -> scrape.videoMsg
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: scrape.videoMsg.sources:type_name -> scrape.videoSource
+	0, // 1: scrape.Video.ScrapeVideoUrl:input_type -> scrape.videoParms
+	1, // 2: scrape.Video.ScrapeVideoUrl:output_type -> scrape.videoMsg
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_scrape_proto_init() }
@@ -203,7 +291,7 @@ func file_scrape_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_scrape_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -2,25 +2,51 @@ package tokenService
 
 import (
 	"context"
+	"errors"
+	"gateService/internal/domain/repository"
 	"gateService/internal/infrastructure/middleware/auth"
+	"time"
 )
 
+var ErrTokenRevoked = errors.New("令牌已被吊销")
+
 type Server struct {
 	UnimplementedTokenServer
-	JWTManager *auth.JWTManager
+	JWTManager      *auth.JWTManager
+	TokenRepository repository.TokenRepository
 }
 
-func NewServer(jwtManager *auth.JWTManager) *Server {
+func NewServer(jwtManager *auth.JWTManager, tokenRepository repository.TokenRepository) *Server {
 	return &Server{
-		JWTManager: jwtManager,
+		JWTManager:      jwtManager,
+		TokenRepository: tokenRepository,
 	}
 }
 
 func (s *Server) TokenVerification(ctx context.Context, in *TokenRequest) (*TokenResponse, error) {
-	Claims, err := s.JWTManager.ParseToken(in.Token)
+	Claims, err := s.JWTManager.ParseAccessToken(in.Token)
+	if err != nil {
+		return &TokenResponse{Error: err.Error()}, err
+	}
+
+	revoked, err := s.TokenRepository.IsTokenRevoked(ctx, Claims.UserInfo.UserID, Claims.ID, Claims.IssuedAtTime())
 	if err != nil {
 		return &TokenResponse{Error: err.Error()}, err
 	}
+	if revoked {
+		return &TokenResponse{Error: ErrTokenRevoked.Error()}, ErrTokenRevoked
+	}
+
+	// 令牌所属会话被移除后同样视为已吊销
+	if Claims.FamilyID != "" {
+		active, err := s.TokenRepository.TouchSession(ctx, Claims.FamilyID, time.Now(), time.Minute)
+		if err != nil {
+			return &TokenResponse{Error: err.Error()}, err
+		}
+		if !active {
+			return &TokenResponse{Error: ErrTokenRevoked.Error()}, ErrTokenRevoked
+		}
+	}
 
 	return &TokenResponse{
 		UserID:    int32(Claims.UserInfo.UserID),
@@ -22,6 +22,8 @@ type Config struct {
 	Cookie            CookieConfig                  `yaml:"cookie"`
 	Storage           StorageConfig                 `yaml:"storage"`
 	Security          SecurityConfig                `yaml:"security"`
+	Mail              MailConfig                    `yaml:"mail"`
+	OAuth             OAuthConfig                   `yaml:"oauth"`
 }
 
 // ServerConfig 服务器配置
@@ -116,6 +118,7 @@ type JWTConfig struct {
 	Issuer       string         `yaml:"issuer"`
 	AccessToken  JWTTokenConfig `yaml:"access_token"`
 	RefreshToken JWTTokenConfig `yaml:"refresh_token"`
+	MfaPending   JWTTokenConfig `yaml:"mfa_pending_token"`
 	TokenType    string         `yaml:"token_type"`
 }
 
@@ -126,12 +129,13 @@ type JWTTokenConfig struct {
 
 // CookieConfig Cookie配置
 type CookieConfig struct {
-	Domain   string `yaml:"domain"`
-	Path     string `yaml:"path"`
-	MaxAge   int    `yaml:"max_age"`
-	Secure   bool   `yaml:"secure"`
-	HTTPOnly bool   `yaml:"http_only"`
-	SameSite string `yaml:"same_site"`
+	Domain        string `yaml:"domain"`
+	Path          string `yaml:"path"`
+	MaxAge        int    `yaml:"max_age"`
+	RefreshMaxAge int    `yaml:"refresh_max_age"`
+	Secure        bool   `yaml:"secure"`
+	HTTPOnly      bool   `yaml:"http_only"`
+	SameSite      string `yaml:"same_site"`
 }
 
 // SecurityConfig 安全配置
@@ -140,6 +144,8 @@ type SecurityConfig struct {
 	CSRF      CSRFConfig      `yaml:"csrf"`
 	XSS       XSSConfig       `yaml:"xss"`
 	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Password  PasswordConfig  `yaml:"password"`
+	Mfa       MfaConfig       `yaml:"mfa"`
 }
 
 type CORSConfig struct {
@@ -166,6 +172,20 @@ type RateLimitConfig struct {
 	RequestsPerSecond int  `yaml:"requests_per_second"`
 }
 
+// PasswordConfig 密码哈希配置
+type PasswordConfig struct {
+	Algorithm     string `yaml:"algorithm"`      // 哈希算法: argon2id/bcrypt
+	BcryptCost    int    `yaml:"bcrypt_cost"`    // bcrypt计算成本
+	Argon2Time    uint32 `yaml:"argon2_time"`    // argon2id迭代次数
+	Argon2Memory  uint32 `yaml:"argon2_memory"`  // argon2id内存占用(KB)
+	Argon2Threads uint8  `yaml:"argon2_threads"` // argon2id并行度
+}
+
+// MfaConfig 两步验证配置
+type MfaConfig struct {
+	Issuer string `yaml:"issuer"` // 验证器应用中显示的签发方名称
+}
+
 // 单个gRPC服务配置
 type GrpcServiceConfig struct {
 	Enabled    bool        `yaml:"enabled"`      // 是否启用该服务
@@ -214,6 +234,7 @@ type PoolConfig struct {
 type StorageConfig struct {
 	Avatar AvatarConfig `yaml:"avatar"`     // 用户头像存储配置
 	Post   PostConfig   `yaml:"post_image"` // 帖子图片存储配置
+	Export ExportConfig `yaml:"export"`     // 用户数据导出文件存储配置
 }
 
 type AvatarConfig struct {
@@ -231,6 +252,41 @@ type PostConfig struct {
 	MaxFiles     int      `yaml:"max_files"`     // 单个帖子最大图片数量
 }
 
+type ExportConfig struct {
+	Path string        `yaml:"path"` // 导出文件存储路径,不对外静态暴露,只能通过下载接口获取
+	TTL  time.Duration `yaml:"ttl"`  // 导出任务及文件保留时间
+}
+
+// MailConfig 邮件发送配置
+type MailConfig struct {
+	Driver   string `yaml:"driver"`    // 发送方式: smtp/file
+	Host     string `yaml:"host"`      // SMTP服务器地址
+	Port     int    `yaml:"port"`      // SMTP服务器端口
+	Username string `yaml:"username"`  // SMTP认证用户名
+	Password string `yaml:"password"`  // SMTP认证密码
+	From     string `yaml:"from"`      // 发件人地址
+	FilePath string `yaml:"file_path"` // file方式下的输出文件路径,为空时输出到标准输出
+
+	ResetPasswordURL string `yaml:"reset_password_url"` // 密码重置页面地址,重置令牌以token参数附加在其后
+}
+
+// OAuthConfig 第三方登录配置
+type OAuthConfig struct {
+	FrontendURL string               `yaml:"frontend_url"` // 登录完成后跳转的前端地址
+	StateTTL    time.Duration        `yaml:"state_ttl"`    // 授权请求状态有效期
+	Providers   []OIDCProviderConfig `yaml:"providers"`    // OpenID Connect身份提供方列表
+}
+
+// OIDCProviderConfig OpenID Connect身份提供方配置
+type OIDCProviderConfig struct {
+	Name         string   `yaml:"name"`          // 身份提供方名称,用于路由 /api/oauth/:provider/login
+	Issuer       string   `yaml:"issuer"`        // 签发方地址,用于服务发现
+	ClientID     string   `yaml:"client_id"`     // 客户端ID
+	ClientSecret string   `yaml:"client_secret"` // 客户端密钥
+	RedirectURL  string   `yaml:"redirect_url"`  // 授权回调地址,需指向 /api/oauth/:provider/callback
+	Scopes       []string `yaml:"scopes"`        // 申请的权限范围,默认openid email profile
+}
+
 var globalConfig *Config
 
 // LoadConfig 加载配置文件
@@ -0,0 +1,265 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AccountDataRepositoryImpl 实现了用户数据导出与注销仓储接口
+// 业务数据保存在MySQL,导出任务状态保存在Redis
+type AccountDataRepositoryImpl struct {
+	db  *sql.DB
+	rdb *redis.Client
+}
+
+// NewAccountDataRepositoryImpl 创建一个新的用户数据导出与注销仓储实现实例
+// 参数:
+// - db: 数据库连接对象
+// - rdb: Redis客户端
+// 返回:
+// - *AccountDataRepositoryImpl: 用户数据导出与注销仓储实现实例
+func NewAccountDataRepositoryImpl(db *sql.DB, rdb *redis.Client) *AccountDataRepositoryImpl {
+	return &AccountDataRepositoryImpl{
+		db:  db,
+		rdb: rdb,
+	}
+}
+
+// exportJobKey 数据导出任务的Redis键
+func exportJobKey(jobID string) string {
+	return "user_export:" + jobID
+}
+
+// userExportJobKey 用户最近一次数据导出任务ID的Redis键
+func userExportJobKey(userID int) string {
+	return "user_export:user:" + strconv.Itoa(userID)
+}
+
+// userDataExportQueries 用户数据导出查询,每项对应导出ZIP中的一个JSON文件
+// 查询字段逐一列出,避免密码等敏感字段进入导出文件
+var userDataExportQueries = []struct {
+	name  string
+	query string
+}{
+	{"profile", "SELECT user_id, username, email, avatar_url, signature, full_name, gender, birth_date, created_at, last_login_at, role FROM user_infos WHERE user_id = ?"},
+	{"posts", "SELECT post_id, category_id, title, content, view_count, like_count, comment_count, favorite_count, status, created_at, updated_at FROM posts WHERE user_id = ? ORDER BY post_id"},
+	{"post_comments", "SELECT comment_id, post_id, to_user_id, parent_id, root_id, content, like_count, reply_count, level, status, created_at FROM post_comments WHERE user_id = ? ORDER BY comment_id"},
+	{"video_comments", "SELECT comment_id, video_id, to_user_id, root_id, parent_id, content, reply_num, level, status, created_at FROM comments WHERE user_id = ? ORDER BY comment_id"},
+	{"watch_progress", "SELECT video_id, episode, progress, status, created_at, updated_at FROM user_watch_progress WHERE user_id = ? ORDER BY updated_at DESC"},
+	{"collections", "SELECT video_id, status, created_at, updated_at FROM user_anime_collections WHERE user_id = ? AND status = 1 ORDER BY created_at DESC"},
+	{"orders", "SELECT order_id, user_name, phone, address, product_id, product_name, price, description, selected_size, selected_color, status, create_time FROM orders WHERE user_id = ? ORDER BY create_time DESC"},
+	{"following", "SELECT followee_id, created_at FROM user_follows WHERE follower_id = ? ORDER BY created_at DESC"},
+	{"followers", "SELECT follower_id, created_at FROM user_follows WHERE followee_id = ? ORDER BY created_at DESC"},
+	{"blocks", "SELECT target_user_id, block_type, created_at FROM user_blocks WHERE user_id = ? ORDER BY created_at DESC"},
+	{"notifications", "SELECT notification_id, from_user_id, post_id, comment_id, notification_type, content, is_read, created_at FROM user_notifications WHERE user_id = ? ORDER BY notification_id DESC"},
+}
+
+// userDataDeletionPlan 用户注销语句,按顺序在同一事务中执行
+// 先扣减该用户贡献的计数再删除关联记录;帖子与评论被他人回复或引用,只做匿名化;订单需保留用于对账,只清除收货信息
+var userDataDeletionPlan = []struct {
+	step  string
+	query string
+}{
+	{"扣减帖子点赞数", "UPDATE posts p JOIN post_likes l ON l.post_id = p.post_id SET p.like_count = GREATEST(p.like_count - 1, 0) WHERE l.user_id = ? AND l.status = 1"},
+	{"删除帖子点赞", "DELETE FROM post_likes WHERE user_id = ?"},
+	{"扣减帖子收藏数", "UPDATE posts p JOIN post_favorites f ON f.post_id = p.post_id SET p.favorite_count = GREATEST(p.favorite_count - 1, 0) WHERE f.user_id = ? AND f.status = 1"},
+	{"删除帖子收藏", "DELETE FROM post_favorites WHERE user_id = ?"},
+	{"扣减评论点赞数", "UPDATE post_comments c JOIN post_comment_likes l ON l.comment_id = c.comment_id SET c.like_count = GREATEST(c.like_count - 1, 0) WHERE l.user_id = ? AND l.status = 1"},
+	{"删除评论点赞", "DELETE FROM post_comment_likes WHERE user_id = ?"},
+	{"删除帖子图片", "DELETE i FROM post_images i JOIN posts p ON p.post_id = i.post_id WHERE p.user_id = ?"},
+	{"匿名化帖子", "UPDATE posts SET title = '该帖子已删除', content = '', status = 3 WHERE user_id = ?"},
+	{"匿名化帖子评论", "UPDATE post_comments SET content = '该评论已删除', status = 3 WHERE user_id = ?"},
+	{"匿名化视频评论", "UPDATE comments SET content = '该评论已删除', status = 0 WHERE user_id = ?"},
+	{"删除动漫收藏", "DELETE FROM user_anime_collections WHERE user_id = ?"},
+	{"删除观看进度", "DELETE FROM user_watch_progress WHERE user_id = ?"},
+	{"删除通知", "DELETE FROM user_notifications WHERE user_id = ? OR from_user_id = ?"},
+	{"删除关注关系", "DELETE FROM user_follows WHERE follower_id = ? OR followee_id = ?"},
+	{"删除屏蔽关系", "DELETE FROM user_blocks WHERE user_id = ? OR target_user_id = ?"},
+	{"匿名化订单", "UPDATE orders SET user_name = '已注销用户', phone = NULL, address = '' WHERE user_id = ?"},
+	{"删除两步验证恢复码", "DELETE FROM user_mfa_recovery_codes WHERE user_id = ?"},
+	{"删除两步验证", "DELETE FROM user_mfa WHERE user_id = ?"},
+	{"删除第三方身份绑定", "DELETE FROM user_identities WHERE user_id = ?"},
+	{"删除个人访问令牌", "DELETE FROM personal_access_tokens WHERE user_id = ?"},
+}
+
+// SaveExportJob 保存数据导出任务,并记录为该用户最近一次导出任务
+// 参数:
+// - ctx: 上下文
+// - job: 导出任务
+// - ttl: 任务保留时间
+// 返回:
+// - error: 错误信息
+func (r *AccountDataRepositoryImpl) SaveExportJob(ctx context.Context, job *entity.DataExportJob, ttl time.Duration) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	// FileName不参与JSON序列化,单独保存
+	pipe := r.rdb.TxPipeline()
+	pipe.HSet(ctx, exportJobKey(job.JobID), "job", value, "file", job.FileName)
+	pipe.Expire(ctx, exportJobKey(job.JobID), ttl)
+	pipe.Set(ctx, userExportJobKey(job.UserID), job.JobID, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetExportJob 获取数据导出任务
+// 参数:
+// - ctx: 上下文
+// - jobID: 任务ID
+// 返回:
+// - *entity.DataExportJob: 导出任务,不存在或已过期时返回nil
+// - error: 错误信息
+func (r *AccountDataRepositoryImpl) GetExportJob(ctx context.Context, jobID string) (*entity.DataExportJob, error) {
+	values, err := r.rdb.HGetAll(ctx, exportJobKey(jobID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if values["job"] == "" {
+		return nil, nil
+	}
+
+	var job entity.DataExportJob
+	if err := json.Unmarshal([]byte(values["job"]), &job); err != nil {
+		return nil, err
+	}
+	job.FileName = values["file"]
+	return &job, nil
+}
+
+// GetLatestExportJob 获取用户最近一次数据导出任务
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - *entity.DataExportJob: 导出任务,不存在或已过期时返回nil
+// - error: 错误信息
+func (r *AccountDataRepositoryImpl) GetLatestExportJob(ctx context.Context, userID int) (*entity.DataExportJob, error) {
+	jobID, err := r.rdb.Get(ctx, userExportJobKey(userID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.GetExportJob(ctx, jobID)
+}
+
+// ExportUserData 读取用户在各业务表中的全部数据
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - []*entity.UserDataSection: 按分类组织的用户数据
+// - error: 错误信息
+func (r *AccountDataRepositoryImpl) ExportUserData(ctx context.Context, userID int) ([]*entity.UserDataSection, error) {
+	// 使用只读事务,保证各分类数据来自同一快照
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sections := make([]*entity.UserDataSection, 0, len(userDataExportQueries))
+	for _, item := range userDataExportQueries {
+		rows, err := queryRowsAsMaps(ctx, tx, item.query, userID)
+		if err != nil {
+			return nil, fmt.Errorf("导出%s失败: %v", item.name, err)
+		}
+		sections = append(sections, &entity.UserDataSection{Name: item.name, Rows: rows})
+	}
+	return sections, tx.Commit()
+}
+
+// DeleteUserData 在同一事务中注销用户
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - error: 错误信息
+func (r *AccountDataRepositoryImpl) DeleteUserData(ctx context.Context, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 先匿名化用户记录并加行锁,用户已注销时直接返回,重复提交不会重复扣减计数
+	query := `
+		UPDATE user_infos
+		SET username = '已注销用户', email = CONCAT('deleted_', user_id, '@deleted.invalid'), password = '',
+			avatar_url = '', signature = '', full_name = '', gender = 'Other', role = 'user', status = 0
+		WHERE user_id = ? AND status = 1
+	`
+	result, err := tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("匿名化用户信息失败: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("用户不存在或已注销")
+	}
+
+	for _, item := range userDataDeletionPlan {
+		// 计划中的占位符均为用户ID
+		args := make([]interface{}, strings.Count(item.query, "?"))
+		for i := range args {
+			args[i] = userID
+		}
+		if _, err := tx.ExecContext(ctx, item.query, args...); err != nil {
+			return fmt.Errorf("%s失败: %v", item.step, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// queryRowsAsMaps 执行查询并将每行转换为字段名到值的映射,[]byte转换为字符串以便JSON序列化
+func queryRowsAsMaps(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
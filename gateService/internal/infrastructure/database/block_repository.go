@@ -0,0 +1,217 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 屏蔽列表缓存有效期,写入时直接删除缓存,过期只用于回收不活跃用户的缓存
+const blockCacheTTL = 12 * time.Hour
+
+// 屏蔽列表缓存中的占位成员,使空列表也能被缓存,用户ID从1开始不会与之冲突
+const blockCachePlaceholder = "0"
+
+// BlockRepositoryImpl 实现了用户屏蔽关系仓储接口
+// 屏蔽关系保存在MySQL,每个用户的拉黑/静音列表以Set缓存在Redis
+type BlockRepositoryImpl struct {
+	db  *sql.DB
+	rdb *redis.Client
+}
+
+// NewBlockRepositoryImpl 创建一个新的用户屏蔽关系仓储实现实例
+// 参数:
+// - db: 数据库连接对象
+// - rdb: Redis客户端
+// 返回:
+// - *BlockRepositoryImpl: 用户屏蔽关系仓储实现实例
+func NewBlockRepositoryImpl(db *sql.DB, rdb *redis.Client) *BlockRepositoryImpl {
+	return &BlockRepositoryImpl{
+		db:  db,
+		rdb: rdb,
+	}
+}
+
+// blockCacheKey 获取屏蔽列表缓存的键
+func blockCacheKey(userID int, blockType int8) string {
+	if blockType == entity.BlockTypeMute {
+		return fmt.Sprintf("user:muted:%d", userID)
+	}
+	return fmt.Sprintf("user:blocked:%d", userID)
+}
+
+// AddBlock 添加屏蔽关系
+// 参数:
+// - ctx: 上下文
+// - userID: 发起屏蔽的用户ID
+// - targetUserID: 被屏蔽的用户ID
+// - blockType: 屏蔽类型,拉黑或静音
+// 返回:
+// - bool: 是否新建了屏蔽关系,已屏蔽时返回false
+// - error: 错误信息
+func (r *BlockRepositoryImpl) AddBlock(ctx context.Context, userID, targetUserID int, blockType int8) (bool, error) {
+	query := "INSERT IGNORE INTO user_blocks (user_id, target_user_id, block_type) VALUES (?, ?, ?)"
+	result, err := r.db.ExecContext(ctx, query, userID, targetUserID, blockType)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected > 0 {
+		if err := r.rdb.Del(ctx, blockCacheKey(userID, blockType)).Err(); err != nil {
+			return true, fmt.Errorf("清除屏蔽列表缓存失败: %v", err)
+		}
+	}
+	return affected > 0, nil
+}
+
+// RemoveBlock 移除屏蔽关系
+// 参数:
+// - ctx: 上下文
+// - userID: 发起屏蔽的用户ID
+// - targetUserID: 被屏蔽的用户ID
+// - blockType: 屏蔽类型,拉黑或静音
+// 返回:
+// - bool: 是否移除了屏蔽关系,未屏蔽时返回false
+// - error: 错误信息
+func (r *BlockRepositoryImpl) RemoveBlock(ctx context.Context, userID, targetUserID int, blockType int8) (bool, error) {
+	query := "DELETE FROM user_blocks WHERE user_id = ? AND target_user_id = ? AND block_type = ?"
+	result, err := r.db.ExecContext(ctx, query, userID, targetUserID, blockType)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected > 0 {
+		if err := r.rdb.Del(ctx, blockCacheKey(userID, blockType)).Err(); err != nil {
+			return true, fmt.Errorf("清除屏蔽列表缓存失败: %v", err)
+		}
+	}
+	return affected > 0, nil
+}
+
+// IsBlocked 判断用户是否屏蔽了目标用户,优先读取Redis缓存
+// 参数:
+// - ctx: 上下文
+// - userID: 发起屏蔽的用户ID
+// - targetUserID: 被屏蔽的用户ID
+// - blockType: 屏蔽类型,拉黑或静音
+// 返回:
+// - bool: 是否已屏蔽
+// - error: 错误信息
+func (r *BlockRepositoryImpl) IsBlocked(ctx context.Context, userID, targetUserID int, blockType int8) (bool, error) {
+	blocked, err := r.GetBlockedUserIDs(ctx, userID, blockType)
+	if err != nil {
+		return false, err
+	}
+	return blocked[targetUserID], nil
+}
+
+// GetBlockedUserIDs 获取用户屏蔽的全部用户ID,优先读取Redis缓存
+// 缓存未命中时从MySQL加载并写回缓存
+// 参数:
+// - ctx: 上下文
+// - userID: 发起屏蔽的用户ID
+// - blockType: 屏蔽类型,拉黑或静音
+// 返回:
+// - map[int]bool: 被屏蔽的用户ID集合
+// - error: 错误信息
+func (r *BlockRepositoryImpl) GetBlockedUserIDs(ctx context.Context, userID int, blockType int8) (map[int]bool, error) {
+	key := blockCacheKey(userID, blockType)
+
+	members, err := r.rdb.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("读取屏蔽列表缓存失败: %v", err)
+	}
+	if len(members) > 0 {
+		blocked := make(map[int]bool, len(members))
+		for _, member := range members {
+			if member == blockCachePlaceholder {
+				continue
+			}
+			targetUserID, err := strconv.Atoi(member)
+			if err != nil {
+				return nil, fmt.Errorf("解析屏蔽列表缓存失败: %v", err)
+			}
+			blocked[targetUserID] = true
+		}
+		return blocked, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT target_user_id FROM user_blocks WHERE user_id = ? AND block_type = ?", userID, blockType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocked := make(map[int]bool)
+	cacheMembers := []interface{}{blockCachePlaceholder}
+	for rows.Next() {
+		var targetUserID int
+		if err := rows.Scan(&targetUserID); err != nil {
+			return nil, err
+		}
+		blocked[targetUserID] = true
+		cacheMembers = append(cacheMembers, targetUserID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, cacheMembers...)
+		pipe.Expire(ctx, key, blockCacheTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("写入屏蔽列表缓存失败: %v", err)
+	}
+	return blocked, nil
+}
+
+// ListBlockedUsers 分页获取用户的拉黑列表或静音列表,按屏蔽时间倒序
+// 参数:
+// - ctx: 上下文
+// - userID: 发起屏蔽的用户ID
+// - blockType: 屏蔽类型,拉黑或静音
+// - page: 页码
+// - pageSize: 每页数量
+// 返回:
+// - []*entity.BlockedUser: 被屏蔽的用户列表
+// - error: 错误信息
+func (r *BlockRepositoryImpl) ListBlockedUsers(ctx context.Context, userID int, blockType int8, page, pageSize int) ([]*entity.BlockedUser, error) {
+	offset := (page - 1) * pageSize
+	query := `
+		SELECT u.user_id, u.username, u.avatar_url, b.created_at
+		FROM user_blocks b
+		JOIN user_infos u ON u.user_id = b.target_user_id
+		WHERE b.user_id = ? AND b.block_type = ?
+		ORDER BY b.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, blockType, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*entity.BlockedUser, 0)
+	for rows.Next() {
+		var user entity.BlockedUser
+		if err := rows.Scan(&user.UserID, &user.Username, &user.AvatarURL, &user.BlockedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
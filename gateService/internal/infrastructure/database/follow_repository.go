@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// FollowRepositoryImpl 实现了用户关注关系仓储接口
+// 关注关系保存在MySQL,关注通知的防重复标记保存在Redis
+type FollowRepositoryImpl struct {
+	db  *sql.DB
+	rdb *redis.Client
+}
+
+// NewFollowRepositoryImpl 创建一个新的用户关注关系仓储实现实例
+// 参数:
+// - db: 数据库连接对象
+// - rdb: Redis客户端
+// 返回:
+// - *FollowRepositoryImpl: 用户关注关系仓储实现实例
+func NewFollowRepositoryImpl(db *sql.DB, rdb *redis.Client) *FollowRepositoryImpl {
+	return &FollowRepositoryImpl{
+		db:  db,
+		rdb: rdb,
+	}
+}
+
+// CreateFollow 创建关注关系
+// 参数:
+// - ctx: 上下文
+// - followerID: 关注者用户ID
+// - followeeID: 被关注者用户ID
+// 返回:
+// - bool: 是否新建了关注关系,已关注时返回false
+// - error: 错误信息
+func (r *FollowRepositoryImpl) CreateFollow(ctx context.Context, followerID, followeeID int) (bool, error) {
+	query := "INSERT IGNORE INTO user_follows (follower_id, followee_id) VALUES (?, ?)"
+	result, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteFollow 删除关注关系
+// 参数:
+// - ctx: 上下文
+// - followerID: 关注者用户ID
+// - followeeID: 被关注者用户ID
+// 返回:
+// - bool: 是否删除了关注关系,未关注时返回false
+// - error: 错误信息
+func (r *FollowRepositoryImpl) DeleteFollow(ctx context.Context, followerID, followeeID int) (bool, error) {
+	query := "DELETE FROM user_follows WHERE follower_id = ? AND followee_id = ?"
+	result, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// IsFollowing 判断是否已关注
+// 参数:
+// - ctx: 上下文
+// - followerID: 关注者用户ID
+// - followeeID: 被关注者用户ID
+// 返回:
+// - bool: 是否已关注
+// - error: 错误信息
+func (r *FollowRepositoryImpl) IsFollowing(ctx context.Context, followerID, followeeID int) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM user_follows WHERE follower_id = ? AND followee_id = ?)"
+	var exist bool
+	if err := r.db.QueryRowContext(ctx, query, followerID, followeeID).Scan(&exist); err != nil {
+		return false, err
+	}
+	return exist, nil
+}
+
+// GetFollowers 分页获取用户的粉丝列表,按关注时间倒序
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - page: 页码
+// - pageSize: 每页数量
+// 返回:
+// - []*entity.FollowUser: 粉丝列表
+// - error: 错误信息
+func (r *FollowRepositoryImpl) GetFollowers(ctx context.Context, userID int, page, pageSize int) ([]*entity.FollowUser, error) {
+	return r.listFollowUsers(ctx, "follower_id", "followee_id", userID, page, pageSize)
+}
+
+// GetFollowing 分页获取用户的关注列表,按关注时间倒序
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - page: 页码
+// - pageSize: 每页数量
+// 返回:
+// - []*entity.FollowUser: 关注列表
+// - error: 错误信息
+func (r *FollowRepositoryImpl) GetFollowing(ctx context.Context, userID int, page, pageSize int) ([]*entity.FollowUser, error) {
+	return r.listFollowUsers(ctx, "followee_id", "follower_id", userID, page, pageSize)
+}
+
+// listFollowUsers 按关注关系的一端查询另一端的用户信息,已注销的用户不返回
+// 参数:
+// - userColumn: 需要返回的用户所在的列
+// - filterColumn: 用于筛选的用户所在的列
+func (r *FollowRepositoryImpl) listFollowUsers(ctx context.Context, userColumn, filterColumn string, userID int, page, pageSize int) ([]*entity.FollowUser, error) {
+	offset := (page - 1) * pageSize
+	query := fmt.Sprintf(`
+		SELECT u.user_id, u.username, u.avatar_url, u.signature, f.created_at
+		FROM user_follows f
+		JOIN user_infos u ON u.user_id = f.%s
+		WHERE f.%s = ? AND u.status = 1
+		ORDER BY f.created_at DESC
+		LIMIT ? OFFSET ?
+	`, userColumn, filterColumn)
+
+	rows, err := r.db.QueryContext(ctx, query, userID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*entity.FollowUser, 0)
+	for rows.Next() {
+		var user entity.FollowUser
+		var signature sql.NullString
+		if err := rows.Scan(&user.UserID, &user.Username, &user.AvatarURL, &signature, &user.FollowedAt); err != nil {
+			return nil, err
+		}
+		user.Signature = signature.String
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
+// GetFollowerCount 获取用户的粉丝数量
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - int: 粉丝数量
+// - error: 错误信息
+func (r *FollowRepositoryImpl) GetFollowerCount(ctx context.Context, userID int) (int, error) {
+	query := "SELECT COUNT(*) FROM user_follows f JOIN user_infos u ON u.user_id = f.follower_id WHERE f.followee_id = ? AND u.status = 1"
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetFollowingCount 获取用户的关注数量
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - int: 关注数量
+// - error: 错误信息
+func (r *FollowRepositoryImpl) GetFollowingCount(ctx context.Context, userID int) (int, error) {
+	query := "SELECT COUNT(*) FROM user_follows f JOIN user_infos u ON u.user_id = f.followee_id WHERE f.follower_id = ? AND u.status = 1"
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkFollowNotified 标记关注通知已发送,用于防止反复关注/取消关注刷通知
+// 参数:
+// - ctx: 上下文
+// - followerID: 关注者用户ID
+// - followeeID: 被关注者用户ID
+// - ttl: 标记有效期
+// 返回:
+// - bool: 是否首次标记,有效期内已标记过时返回false
+// - error: 错误信息
+func (r *FollowRepositoryImpl) MarkFollowNotified(ctx context.Context, followerID, followeeID int, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("follow:notified:%d:%d", followerID, followeeID)
+	return r.rdb.SetNX(ctx, key, 1, ttl).Result()
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"gateService/internal/domain/entity"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IdentityRepositoryImpl 实现了第三方身份仓储接口
+// 身份绑定关系保存在MySQL,授权请求状态保存在Redis
+type IdentityRepositoryImpl struct {
+	db  *sql.DB
+	rdb *redis.Client
+}
+
+// NewIdentityRepositoryImpl 创建一个新的第三方身份仓储实现实例
+// 参数:
+// - db: 数据库连接对象
+// - rdb: Redis客户端
+// 返回:
+// - *IdentityRepositoryImpl: 第三方身份仓储实现实例
+func NewIdentityRepositoryImpl(db *sql.DB, rdb *redis.Client) *IdentityRepositoryImpl {
+	return &IdentityRepositoryImpl{
+		db:  db,
+		rdb: rdb,
+	}
+}
+
+// oauthStateKey 授权请求状态的Redis键
+func oauthStateKey(state string) string {
+	return "oauth:state:" + state
+}
+
+// SaveOAuthState 保存授权请求状态
+// 参数:
+// - ctx: 上下文
+// - state: 授权请求的state参数
+// - data: 授权请求状态
+// - ttl: 有效期
+// 返回:
+// - error: 错误信息
+func (r *IdentityRepositoryImpl) SaveOAuthState(ctx context.Context, state string, data *entity.OAuthState, ttl time.Duration) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, oauthStateKey(state), value, ttl).Err()
+}
+
+// ConsumeOAuthState 取出并删除授权请求状态,每个state只能使用一次
+// 参数:
+// - ctx: 上下文
+// - state: 授权请求的state参数
+// 返回:
+// - *entity.OAuthState: 授权请求状态,不存在或已过期时返回nil
+// - error: 错误信息
+func (r *IdentityRepositoryImpl) ConsumeOAuthState(ctx context.Context, state string) (*entity.OAuthState, error) {
+	value, err := r.rdb.GetDel(ctx, oauthStateKey(state)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data entity.OAuthState
+	if err := json.Unmarshal(value, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// pendingLinkKey 待确认身份绑定的Redis键
+func pendingLinkKey(token string) string {
+	return "oauth:link:" + token
+}
+
+// SavePendingLink 保存待确认的第三方身份绑定
+// 参数:
+// - ctx: 上下文
+// - token: 确认令牌
+// - data: 待确认的身份绑定
+// - ttl: 有效期
+// 返回:
+// - error: 错误信息
+func (r *IdentityRepositoryImpl) SavePendingLink(ctx context.Context, token string, data *entity.OAuthPendingLink, ttl time.Duration) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, pendingLinkKey(token), value, ttl).Err()
+}
+
+// ConsumePendingLink 取出并删除待确认的第三方身份绑定,每个确认令牌只能使用一次
+// 参数:
+// - ctx: 上下文
+// - token: 确认令牌
+// 返回:
+// - *entity.OAuthPendingLink: 待确认的身份绑定,不存在或已过期时返回nil
+// - error: 错误信息
+func (r *IdentityRepositoryImpl) ConsumePendingLink(ctx context.Context, token string) (*entity.OAuthPendingLink, error) {
+	value, err := r.rdb.GetDel(ctx, pendingLinkKey(token)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data entity.OAuthPendingLink
+	if err := json.Unmarshal(value, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetUserIdentity 获取第三方身份绑定
+// 参数:
+// - ctx: 上下文
+// - provider: 身份提供方名称
+// - subject: 用户在身份提供方处的唯一标识
+// 返回:
+// - *entity.UserIdentity: 身份绑定,未绑定时返回nil
+// - error: 错误信息
+func (r *IdentityRepositoryImpl) GetUserIdentity(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	query := "SELECT id, user_id, provider, subject, IFNULL(email, ''), created_at FROM user_identities WHERE provider = ? AND subject = ?"
+	row := r.db.QueryRowContext(ctx, query, provider, subject)
+	var identity entity.UserIdentity
+	err := row.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email, &identity.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// CreateUserIdentity 创建第三方身份绑定
+// 参数:
+// - ctx: 上下文
+// - identity: 身份绑定信息
+// 返回:
+// - error: 错误信息
+func (r *IdentityRepositoryImpl) CreateUserIdentity(ctx context.Context, identity *entity.UserIdentity) error {
+	query := "INSERT INTO user_identities (user_id, provider, subject, email) VALUES (?, ?, ?, ?)"
+	_, err := r.db.ExecContext(ctx, query, identity.UserID, identity.Provider, identity.Subject, identity.Email)
+	return err
+}
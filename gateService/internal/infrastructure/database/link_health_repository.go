@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// 检查执行标记,保证每个周期只有一个实例执行检查
+	linkCheckLockKey = "LinkHealth:CheckLock"
+	// 播放源缓存key的扫描位置,各实例共用,使每轮检查接着上一轮继续
+	linkCheckCursorKey = "LinkHealth:ScanCursor"
+	// 各来源站点的探测可用次数,哈希结构,字段为来源站点
+	linkAliveStatsKey = "LinkHealth:Alive"
+	// 各来源站点的探测失效次数,结构同可用次数
+	linkDeadStatsKey = "LinkHealth:Dead"
+	// 播放地址的连续探测失败次数,地址较长,使用摘要
+	linkFailStreakKeyFormat = "LinkHealth:FailStreak:%x"
+	// 连续失败次数的有效期,每次失败后重新计时,长时间未被探测的地址重新计数
+	linkFailStreakTTL = 24 * time.Hour
+
+	// 播放源缓存key的匹配模式
+	videoSourceKeyPattern = "VideoURL:Video*"
+)
+
+// LinkHealthRepositoryImpl 实现了播放地址健康检查仓储接口,仅使用Redis
+type LinkHealthRepositoryImpl struct {
+	rdb *redis.Client
+}
+
+// NewLinkHealthRepositoryImpl 创建一个新的播放地址健康检查仓储实现实例
+// 参数:
+// - rdb: Redis客户端
+// 返回:
+// - *LinkHealthRepositoryImpl: 播放地址健康检查仓储实现实例
+func NewLinkHealthRepositoryImpl(rdb *redis.Client) *LinkHealthRepositoryImpl {
+	return &LinkHealthRepositoryImpl{
+		rdb: rdb,
+	}
+}
+
+// AcquireCheckRound 尝试获取本轮检查的执行权
+func (r *LinkHealthRepositoryImpl) AcquireCheckRound(ctx context.Context, interval time.Duration) (bool, error) {
+	// 标记略短于检查周期,避免各实例定时器的微小偏差导致某个周期无实例执行
+	return r.rdb.SetNX(ctx, linkCheckLockKey, time.Now().Unix(), interval*9/10).Result()
+}
+
+// SampleVideoSourceKeys 从上次的扫描位置继续扫描播放源缓存key
+func (r *LinkHealthRepositoryImpl) SampleVideoSourceKeys(ctx context.Context, count int) ([]string, error) {
+	cursor, err := r.rdb.Get(ctx, linkCheckCursorKey).Uint64()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, count)
+	for len(keys) < count {
+		var batch []string
+		batch, cursor, err = r.rdb.Scan(ctx, cursor, videoSourceKeyPattern, int64(count)).Result()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, batch...)
+		// 游标回到0表示已扫描完一遍,下一轮从头开始
+		if cursor == 0 {
+			break
+		}
+	}
+
+	if err := r.rdb.Set(ctx, linkCheckCursorKey, cursor, 0).Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RecordProbeResults 累加各来源站点的探测结果
+func (r *LinkHealthRepositoryImpl) RecordProbeResults(ctx context.Context, alive, dead map[string]int) error {
+	if len(alive) == 0 && len(dead) == 0 {
+		return nil
+	}
+
+	_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for origin, count := range alive {
+			pipe.HIncrBy(ctx, linkAliveStatsKey, origin, int64(count))
+		}
+		for origin, count := range dead {
+			pipe.HIncrBy(ctx, linkDeadStatsKey, origin, int64(count))
+		}
+		return nil
+	})
+	return err
+}
+
+// UpdateFailureStreaks 更新播放地址的连续探测失败次数,失败加1并刷新有效期,可用时清零
+func (r *LinkHealthRepositoryImpl) UpdateFailureStreaks(ctx context.Context, failed, recovered []string) (map[string]int64, error) {
+	if len(failed) == 0 && len(recovered) == 0 {
+		return map[string]int64{}, nil
+	}
+
+	incrCmds := make(map[string]*redis.IntCmd, len(failed))
+	_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, url := range failed {
+			key := fmt.Sprintf(linkFailStreakKeyFormat, sha1.Sum([]byte(url)))
+			incrCmds[url] = pipe.Incr(ctx, key)
+			pipe.Expire(ctx, key, linkFailStreakTTL)
+		}
+		for _, url := range recovered {
+			pipe.Del(ctx, fmt.Sprintf(linkFailStreakKeyFormat, sha1.Sum([]byte(url))))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	streaks := make(map[string]int64, len(incrCmds))
+	for url, cmd := range incrCmds {
+		streaks[url] = cmd.Val()
+	}
+	return streaks, nil
+}
+
+// GetLinkHealthStats 获取各来源站点的探测统计,可用率最低的来源站点排在最前
+func (r *LinkHealthRepositoryImpl) GetLinkHealthStats(ctx context.Context) ([]*entity.LinkHealthStat, error) {
+	pipe := r.rdb.Pipeline()
+	aliveCmd := pipe.HGetAll(ctx, linkAliveStatsKey)
+	deadCmd := pipe.HGetAll(ctx, linkDeadStatsKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	statMap := make(map[string]*entity.LinkHealthStat)
+	getStat := func(origin string) *entity.LinkHealthStat {
+		stat, ok := statMap[origin]
+		if !ok {
+			stat = &entity.LinkHealthStat{Origin: origin}
+			statMap[origin] = stat
+		}
+		return stat
+	}
+	for origin, value := range aliveCmd.Val() {
+		getStat(origin).Alive, _ = strconv.ParseInt(value, 10, 64)
+	}
+	for origin, value := range deadCmd.Val() {
+		getStat(origin).Dead, _ = strconv.ParseInt(value, 10, 64)
+	}
+
+	stats := make([]*entity.LinkHealthStat, 0, len(statMap))
+	for _, stat := range statMap {
+		if total := stat.Alive + stat.Dead; total > 0 {
+			stat.SuccessRate = math.Round(float64(stat.Alive)/float64(total)*10000) / 10000
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].SuccessRate != stats[j].SuccessRate {
+			return stats[i].SuccessRate < stats[j].SuccessRate
+		}
+		return stats[i].Origin < stats[j].Origin
+	})
+	return stats, nil
+}
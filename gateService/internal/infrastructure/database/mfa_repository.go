@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MfaRepositoryImpl 实现了两步验证仓储接口
+type MfaRepositoryImpl struct {
+	db  *sql.DB
+	rdb *redis.Client
+}
+
+// NewMfaRepositoryImpl 创建一个新的两步验证仓储实现实例
+// 参数:
+// - db: 数据库连接对象
+// - rdb: Redis客户端
+// 返回:
+// - *MfaRepositoryImpl: 两步验证仓储实现实例
+func NewMfaRepositoryImpl(db *sql.DB, rdb *redis.Client) *MfaRepositoryImpl {
+	return &MfaRepositoryImpl{
+		db:  db,
+		rdb: rdb,
+	}
+}
+
+// GetUserMfa 获取用户两步验证配置
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - *entity.UserMfa: 两步验证配置,未设置时返回nil
+// - error: 错误信息
+func (r *MfaRepositoryImpl) GetUserMfa(ctx context.Context, userID int) (*entity.UserMfa, error) {
+	query := "SELECT user_id, secret, enabled, created_at, updated_at FROM user_mfa WHERE user_id = ?"
+	row := r.db.QueryRowContext(ctx, query, userID)
+	var mfa entity.UserMfa
+	err := row.Scan(&mfa.UserID, &mfa.Secret, &mfa.Enabled, &mfa.CreatedAt, &mfa.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+// SaveUserMfaSecret 保存待确认的TOTP密钥
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - secret: TOTP密钥
+// 返回:
+// - error: 错误信息
+func (r *MfaRepositoryImpl) SaveUserMfaSecret(ctx context.Context, userID int, secret string) error {
+	query := `
+		INSERT INTO user_mfa (user_id, secret, enabled) VALUES (?, ?, 0)
+		ON DUPLICATE KEY UPDATE secret = IF(enabled = 0, VALUES(secret), secret)
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, secret)
+	return err
+}
+
+// EnableUserMfa 启用两步验证,并替换用户的全部恢复码
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - recoveryCodeHashes: 恢复码哈希列表
+// 返回:
+// - error: 错误信息
+func (r *MfaRepositoryImpl) EnableUserMfa(ctx context.Context, userID int, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "UPDATE user_mfa SET enabled = 1 WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM user_mfa_recovery_codes WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	if len(recoveryCodeHashes) > 0 {
+		placeholders := make([]string, len(recoveryCodeHashes))
+		args := make([]interface{}, 0, len(recoveryCodeHashes)*2)
+		for i, codeHash := range recoveryCodeHashes {
+			placeholders[i] = "(?, ?)"
+			args = append(args, userID, codeHash)
+		}
+		query := "INSERT INTO user_mfa_recovery_codes (user_id, code_hash) VALUES " + strings.Join(placeholders, ",")
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DisableUserMfa 关闭两步验证,删除密钥及全部恢复码
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - error: 错误信息
+func (r *MfaRepositoryImpl) DisableUserMfa(ctx context.Context, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM user_mfa_recovery_codes WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM user_mfa WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode 使用恢复码,每个恢复码只能使用一次
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - codeHash: 恢复码哈希
+// 返回:
+// - bool: 恢复码是否有效且未使用
+// - error: 错误信息
+func (r *MfaRepositoryImpl) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	query := "UPDATE user_mfa_recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = ? AND code_hash = ? AND used_at IS NULL"
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// MarkTotpStepUsed 标记用户某个TOTP时间步的验证码已被使用
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - step: TOTP时间步
+// - ttl: 标记的过期时间
+// 返回:
+// - bool: 标记成功返回true,该时间步已被使用过返回false
+// - error: 错误信息
+func (r *MfaRepositoryImpl) MarkTotpStepUsed(ctx context.Context, userID int, step int64, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("mfa:used:%d:%d", userID, step)
+	return r.rdb.SetNX(ctx, key, 1, ttl).Result()
+}
@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"gateService/internal/domain/entity"
+	"strings"
+	"time"
+)
+
+// PersonalAccessTokenRepositoryImpl 实现了个人访问令牌仓储接口,仅使用MySQL
+type PersonalAccessTokenRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewPersonalAccessTokenRepositoryImpl 创建一个新的个人访问令牌仓储实现实例
+// 参数:
+// - db: 数据库连接对象
+// 返回:
+// - *PersonalAccessTokenRepositoryImpl: 个人访问令牌仓储实现实例
+func NewPersonalAccessTokenRepositoryImpl(db *sql.DB) *PersonalAccessTokenRepositoryImpl {
+	return &PersonalAccessTokenRepositoryImpl{
+		db: db,
+	}
+}
+
+// 个人访问令牌查询字段,与scanPersonalAccessToken的扫描顺序一致
+const personalAccessTokenColumns = "token_id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, created_at"
+
+// scanPersonalAccessToken 扫描一行个人访问令牌数据
+func scanPersonalAccessToken(scanner interface{ Scan(...interface{}) error }) (*entity.PersonalAccessToken, error) {
+	var (
+		token      entity.PersonalAccessToken
+		scopes     string
+		expiresAt  sql.NullTime
+		lastUsedAt sql.NullTime
+	)
+	err := scanner.Scan(&token.TokenID, &token.UserID, &token.Name, &token.TokenHash, &token.TokenPrefix, &scopes, &expiresAt, &lastUsedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Scopes = []string{}
+	if scopes != "" {
+		token.Scopes = strings.Split(scopes, ",")
+	}
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	return &token, nil
+}
+
+// CreateToken 创建个人访问令牌
+// 参数:
+// - ctx: 上下文
+// - token: 令牌信息,仅包含令牌哈希,创建成功后回填令牌ID
+// 返回:
+// - error: 错误信息
+func (r *PersonalAccessTokenRepositoryImpl) CreateToken(ctx context.Context, token *entity.PersonalAccessToken) error {
+	var expiresAt sql.NullTime
+	if token.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *token.ExpiresAt, Valid: true}
+	}
+
+	query := "INSERT INTO personal_access_tokens (user_id, name, token_hash, token_prefix, scopes, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	result, err := r.db.ExecContext(ctx, query, token.UserID, token.Name, token.TokenHash, token.TokenPrefix, strings.Join(token.Scopes, ","), expiresAt, token.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	token.TokenID, err = result.LastInsertId()
+	return err
+}
+
+// CountTokensByUserID 统计用户的个人访问令牌数量
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - int: 令牌数量
+// - error: 错误信息
+func (r *PersonalAccessTokenRepositoryImpl) CountTokensByUserID(ctx context.Context, userID int) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM personal_access_tokens WHERE user_id = ?"
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListTokensByUserID 获取用户的全部个人访问令牌,按创建时间倒序
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - []*entity.PersonalAccessToken: 令牌列表
+// - error: 错误信息
+func (r *PersonalAccessTokenRepositoryImpl) ListTokensByUserID(ctx context.Context, userID int) ([]*entity.PersonalAccessToken, error) {
+	query := "SELECT " + personalAccessTokenColumns + " FROM personal_access_tokens WHERE user_id = ? ORDER BY token_id DESC"
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]*entity.PersonalAccessToken, 0)
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// GetTokenByHash 通过令牌哈希获取个人访问令牌
+// 参数:
+// - ctx: 上下文
+// - tokenHash: 令牌SHA-256哈希
+// 返回:
+// - *entity.PersonalAccessToken: 令牌信息,不存在时返回nil
+// - error: 错误信息
+func (r *PersonalAccessTokenRepositoryImpl) GetTokenByHash(ctx context.Context, tokenHash string) (*entity.PersonalAccessToken, error) {
+	query := "SELECT " + personalAccessTokenColumns + " FROM personal_access_tokens WHERE token_hash = ?"
+	token, err := scanPersonalAccessToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// UpdateTokenLastUsed 更新令牌最近使用时间
+// 参数:
+// - ctx: 上下文
+// - tokenID: 令牌ID
+// - usedAt: 使用时间
+// 返回:
+// - error: 错误信息
+func (r *PersonalAccessTokenRepositoryImpl) UpdateTokenLastUsed(ctx context.Context, tokenID int64, usedAt time.Time) error {
+	query := "UPDATE personal_access_tokens SET last_used_at = ? WHERE token_id = ?"
+	_, err := r.db.ExecContext(ctx, query, usedAt, tokenID)
+	return err
+}
+
+// DeleteToken 删除用户的指定个人访问令牌
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID,只能删除该用户自己的令牌
+// - tokenID: 令牌ID
+// 返回:
+// - bool: 是否删除成功,令牌不存在或不属于该用户时返回false
+// - error: 错误信息
+func (r *PersonalAccessTokenRepositoryImpl) DeleteToken(ctx context.Context, userID int, tokenID int64) (bool, error) {
+	query := "DELETE FROM personal_access_tokens WHERE token_id = ? AND user_id = ?"
+	result, err := r.db.ExecContext(ctx, query, tokenID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteTokensByUserID 删除用户的全部个人访问令牌
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - error: 错误信息
+func (r *PersonalAccessTokenRepositoryImpl) DeleteTokensByUserID(ctx context.Context, userID int) error {
+	query := "DELETE FROM personal_access_tokens WHERE user_id = ?"
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"gateService/internal/domain/entity"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -152,18 +153,21 @@ func (p *PostCommentRepositoryImpl) DeletePostComment(ctx context.Context, postC
 // - postID: 帖子ID
 // - page: 页码,从1开始
 // - pageSize: 每页评论数量
+// - excludeUserIDs: 需要排除的评论作者ID,为空时不排除
 // 返回:
 // - []*entity.PostComment: 评论列表
 // - error: 获取过程中的错误信息
-func (p *PostCommentRepositoryImpl) GetPostCommentsByPostID(ctx context.Context, postID int64, page int, pageSize int) ([]*entity.PostComment, error) {
+func (p *PostCommentRepositoryImpl) GetPostCommentsByPostID(ctx context.Context, postID int64, page int, pageSize int, excludeUserIDs []int) ([]*entity.PostComment, error) {
 	offset := (page - 1) * pageSize
+	exclude, excludeArgs := excludeUsersClause(excludeUserIDs)
 	query := `SELECT comment_id, user_id, content, like_count, reply_count, created_at
 		FROM post_comments 
-		WHERE post_id = ? AND status = 1 AND level = 1
+		WHERE post_id = ? AND status = 1 AND level = 1` + exclude + `
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?`
 
-	rows, err := p.db.QueryContext(ctx, query, postID, pageSize, offset)
+	args := append([]interface{}{postID}, excludeArgs...)
+	rows, err := p.db.QueryContext(ctx, query, append(args, pageSize, offset)...)
 	if err != nil {
 		return nil, err
 	}
@@ -194,18 +198,21 @@ func (p *PostCommentRepositoryImpl) GetPostCommentsByPostID(ctx context.Context,
 // - rootID: 根评论ID
 // - page: 页码,从1开始
 // - pageSize: 每页评论数量
+// - excludeUserIDs: 需要排除的评论作者ID,为空时不排除
 // 返回:
 // - []*entity.PostComment: 子评论列表
 // - error: 获取过程中的错误信息
-func (p *PostCommentRepositoryImpl) GetPostCommentsByRootID(ctx context.Context, rootID int64, page int, pageSize int) ([]*entity.PostComment, error) {
+func (p *PostCommentRepositoryImpl) GetPostCommentsByRootID(ctx context.Context, rootID int64, page int, pageSize int, excludeUserIDs []int) ([]*entity.PostComment, error) {
 	offset := (page - 1) * pageSize
+	exclude, excludeArgs := excludeUsersClause(excludeUserIDs)
 	query := `SELECT comment_id, user_id, to_user_id, content, like_count, reply_count, created_at
 		FROM post_comments 
-		WHERE root_id = ? AND status = 1
+		WHERE root_id = ? AND status = 1` + exclude + `
 		ORDER BY created_at ASC
 		LIMIT ? OFFSET ?`
 
-	rows, err := p.db.QueryContext(ctx, query, rootID, pageSize, offset)
+	args := append([]interface{}{rootID}, excludeArgs...)
+	rows, err := p.db.QueryContext(ctx, query, append(args, pageSize, offset)...)
 	if err != nil {
 		return nil, err
 	}
@@ -230,6 +237,27 @@ func (p *PostCommentRepositoryImpl) GetPostCommentsByRootID(ctx context.Context,
 	return comments, nil
 }
 
+// excludeUsersClause 构建排除指定评论作者的查询条件
+// 在SQL中排除而不是查询后过滤,保证分页数量和总页数正确
+// 参数:
+// - userIDs: 需要排除的评论作者ID
+// 返回:
+// - string: 追加到WHERE后的查询条件,无需排除时为空
+// - []interface{}: 查询条件对应的参数
+func excludeUsersClause(userIDs []int) (string, []interface{}) {
+	if len(userIDs) == 0 {
+		return "", nil
+	}
+
+	placeholders := make([]string, len(userIDs))
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return " AND user_id NOT IN (" + strings.Join(placeholders, ",") + ")", args
+}
+
 // GetPostCommentLikesByUserID 获取用户对评论的点赞状态
 // 参数:
 // - ctx: 上下文
@@ -266,14 +294,16 @@ func (p *PostCommentRepositoryImpl) GetPostCommentLikesByUserID(ctx context.Cont
 // - ctx: 上下文
 // - postID: 帖子ID
 // - pageSize: 每页评论数量
+// - excludeUserIDs: 需要排除的评论作者ID,为空时不排除
 // 返回:
 // - int: 总页数
 // - error: 获取过程中的错误信息
-func (p *PostCommentRepositoryImpl) GetCommentTotalPage(ctx context.Context, postID int64, pageSize int) (int, error) {
-	query := `SELECT COUNT(*) FROM post_comments WHERE post_id = ? AND status = 1 AND level = 1`
+func (p *PostCommentRepositoryImpl) GetCommentTotalPage(ctx context.Context, postID int64, pageSize int, excludeUserIDs []int) (int, error) {
+	exclude, excludeArgs := excludeUsersClause(excludeUserIDs)
+	query := `SELECT COUNT(*) FROM post_comments WHERE post_id = ? AND status = 1 AND level = 1` + exclude
 
 	var total int
-	err := p.db.QueryRowContext(ctx, query, postID).Scan(&total)
+	err := p.db.QueryRowContext(ctx, query, append([]interface{}{postID}, excludeArgs...)...).Scan(&total)
 	if err != nil {
 		return 0, err
 	}
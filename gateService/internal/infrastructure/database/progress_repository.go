@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"gateService/internal/domain/entity"
+	"time"
 )
 
 type ProgressRepositoryImpl struct {
@@ -109,3 +110,42 @@ func (p *ProgressRepositoryImpl) GetUserWatchProgress(ctx context.Context, userI
 
 	return progress, nil
 }
+
+// ListRecentlyWatchedEpisodes 按数据库时间计算统计窗口,避免应用与数据库时区不一致
+func (p *ProgressRepositoryImpl) ListRecentlyWatchedEpisodes(ctx context.Context, window time.Duration, limit int) ([]*entity.WatchedEpisode, error) {
+	query := `
+		SELECT video_id, episode
+		FROM user_watch_progress
+		WHERE updated_at >= NOW() - INTERVAL ? SECOND
+		GROUP BY video_id, episode
+		ORDER BY MAX(updated_at) DESC
+		LIMIT ?`
+	rows, err := p.db.QueryContext(ctx, query, int(window.Seconds()), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	episodes := make([]*entity.WatchedEpisode, 0, limit)
+	for rows.Next() {
+		var episode entity.WatchedEpisode
+		if err := rows.Scan(&episode.VideoID, &episode.Episode); err != nil {
+			return nil, err
+		}
+		episodes = append(episodes, &episode)
+	}
+	return episodes, rows.Err()
+}
+
+func (p *ProgressRepositoryImpl) GetRecentlyWatchedEpisode(ctx context.Context, userID, videoID int, window time.Duration) (string, error) {
+	query := `
+		SELECT episode
+		FROM user_watch_progress
+		WHERE user_id = ? AND video_id = ? AND updated_at >= NOW() - INTERVAL ? SECOND`
+	var episode string
+	err := p.db.QueryRowContext(ctx, query, userID, videoID, int(window.Seconds())).Scan(&episode)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return episode, err
+}
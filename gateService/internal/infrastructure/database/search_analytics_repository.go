@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// 热门搜索词,有序集合,成员为搜索词,分数为衰减后的搜索次数
+	searchTrendingKey = "SearchAnalytics:Trending"
+	// 无结果搜索词,结构同热门搜索词
+	searchZeroResultKey = "SearchAnalytics:ZeroResult"
+	// 衰减执行标记,保证每个周期只有一个实例执行衰减
+	searchDecayLockKey = "SearchAnalytics:DecayLock"
+	// 用户搜索去重标记,参数为用户ID和搜索词
+	searchSeenKeyFormat = "SearchAnalytics:Seen:%d:%s"
+	// 搜索词的搜索用户,HyperLogLog,参数为搜索词
+	searchUsersKeyFormat = "SearchAnalytics:Users:%s"
+
+	// 同一用户在该时间内重复搜索同一词只统计一次
+	searchDedupeWindow = time.Hour
+	// 搜索用户统计的有效期,期间无人搜索该词时过期
+	searchUsersTTL = 24 * time.Hour
+	// 获取热门搜索词时按返回数量的倍数读取候选词,用于过滤搜索用户不足的搜索词
+	searchTrendingCandidates = 5
+
+	// 衰减后热度低于该值的搜索词被清理
+	searchMinScore = 0.1
+	// 每个有序集合保留的最大搜索词数量
+	searchMaxQueries = 10000
+)
+
+// SearchAnalyticsRepositoryImpl 实现了搜索统计仓储接口
+// 搜索热度保存在Redis有序集合中,每次搜索加1,定期整体乘以衰减系数
+type SearchAnalyticsRepositoryImpl struct {
+	rdb *redis.Client
+}
+
+// NewSearchAnalyticsRepositoryImpl 创建一个新的搜索统计仓储实现实例
+// 参数:
+// - rdb: Redis客户端
+// 返回:
+// - *SearchAnalyticsRepositoryImpl: 搜索统计仓储实现实例
+func NewSearchAnalyticsRepositoryImpl(rdb *redis.Client) *SearchAnalyticsRepositoryImpl {
+	return &SearchAnalyticsRepositoryImpl{
+		rdb: rdb,
+	}
+}
+
+// RecordSearch 记录一次搜索
+// 同一用户在去重窗口内重复搜索同一词只统计一次,有结果的搜索同时记录搜索用户
+func (r *SearchAnalyticsRepositoryImpl) RecordSearch(ctx context.Context, userID int, query string, zeroResult bool) error {
+	counted, err := r.rdb.SetNX(ctx, fmt.Sprintf(searchSeenKeyFormat, userID, query), 1, searchDedupeWindow).Result()
+	if err != nil || !counted {
+		return err
+	}
+
+	if zeroResult {
+		return r.rdb.ZIncrBy(ctx, searchZeroResultKey, 1, query).Err()
+	}
+	usersKey := fmt.Sprintf(searchUsersKeyFormat, query)
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZIncrBy(ctx, searchTrendingKey, 1, query)
+		pipe.PFAdd(ctx, usersKey, userID)
+		pipe.Expire(ctx, usersKey, searchUsersTTL)
+		return nil
+	})
+	return err
+}
+
+// GetTrendingQueries 获取热门搜索词,只返回搜索用户数不少于minUsers的搜索词
+func (r *SearchAnalyticsRepositoryImpl) GetTrendingQueries(ctx context.Context, limit, minUsers int) ([]*entity.SearchQueryStat, error) {
+	members, err := r.rdb.ZRevRangeWithScores(ctx, searchTrendingKey, 0, int64(limit*searchTrendingCandidates-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return []*entity.SearchQueryStat{}, nil
+	}
+
+	pipe := r.rdb.Pipeline()
+	userCmds := make([]*redis.IntCmd, len(members))
+	for i, member := range members {
+		query, _ := member.Member.(string)
+		userCmds[i] = pipe.PFCount(ctx, fmt.Sprintf(searchUsersKeyFormat, query))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	trending := make([]redis.Z, 0, limit)
+	for i, member := range members {
+		if userCmds[i].Val() < int64(minUsers) {
+			continue
+		}
+		trending = append(trending, member)
+		if len(trending) == limit {
+			break
+		}
+	}
+	return toSearchQueryStats(trending), nil
+}
+
+// GetZeroResultQueries 分页获取无结果的搜索词
+func (r *SearchAnalyticsRepositoryImpl) GetZeroResultQueries(ctx context.Context, offset, limit int) ([]*entity.SearchQueryStat, int64, error) {
+	pipe := r.rdb.Pipeline()
+	totalCmd := pipe.ZCard(ctx, searchZeroResultKey)
+	membersCmd := pipe.ZRevRangeWithScores(ctx, searchZeroResultKey, int64(offset), int64(offset+limit-1))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, err
+	}
+	return toSearchQueryStats(membersCmd.Val()), totalCmd.Val(), nil
+}
+
+// DecayScores 按经过的时间衰减全部搜索热度
+// 衰减系数为 0.5^(interval/halfLife),衰减后清理热度过低的搜索词,并只保留热度最高的部分搜索词
+func (r *SearchAnalyticsRepositoryImpl) DecayScores(ctx context.Context, interval, halfLife time.Duration) (bool, error) {
+	// 标记略短于衰减周期,避免各实例定时器的微小偏差导致某个周期无实例执行
+	acquired, err := r.rdb.SetNX(ctx, searchDecayLockKey, time.Now().Unix(), interval*9/10).Result()
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	factor := math.Pow(0.5, interval.Seconds()/halfLife.Seconds())
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range []string{searchTrendingKey, searchZeroResultKey} {
+			pipe.ZUnionStore(ctx, key, &redis.ZStore{Keys: []string{key}, Weights: []float64{factor}})
+			pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatFloat(searchMinScore, 'f', -1, 64))
+			pipe.ZRemRangeByRank(ctx, key, 0, -searchMaxQueries-1)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func toSearchQueryStats(members []redis.Z) []*entity.SearchQueryStat {
+	stats := make([]*entity.SearchQueryStat, 0, len(members))
+	for _, member := range members {
+		query, _ := member.Member.(string)
+		stats = append(stats, &entity.SearchQueryStat{
+			Query: query,
+			Score: math.Round(member.Score*100) / 100,
+		})
+	}
+	return stats
+}
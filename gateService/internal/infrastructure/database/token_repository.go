@@ -0,0 +1,372 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// rotateRefreshScript 原子地校验并轮换刷新令牌
+// 返回: 1-轮换成功, 0-令牌重用(已删除家族), -1-家族不存在
+var rotateRefreshScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'current')
+if not current then
+	return -1
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+redis.call('HSET', KEYS[1], 'current', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// touchSessionScript 检查会话是否存在,距上次更新超过间隔时更新最近活跃时间
+// 返回: 1-会话有效, 0-会话不存在
+var touchSessionScript = redis.NewScript(`
+local values = redis.call('HMGET', KEYS[1], 'user_id', 'last_seen')
+if not values[1] then
+	return 0
+end
+local now = tonumber(ARGV[1])
+if not values[2] or now - tonumber(values[2]) >= tonumber(ARGV[2]) then
+	redis.call('HSET', KEYS[1], 'last_seen', now)
+end
+return 1
+`)
+
+// 会话时间的展示格式
+const sessionTimeLayout = "2006-01-02 15:04:05"
+
+// TokenRepositoryImpl 实现了令牌仓储接口,仅使用Redis
+type TokenRepositoryImpl struct {
+	rdb *redis.Client
+}
+
+// NewTokenRepositoryImpl 创建一个新的令牌仓储实现实例
+// 参数:
+// - rdb: Redis连接对象
+// 返回:
+// - *TokenRepositoryImpl: 令牌仓储实现实例
+func NewTokenRepositoryImpl(rdb *redis.Client) *TokenRepositoryImpl {
+	return &TokenRepositoryImpl{
+		rdb: rdb,
+	}
+}
+
+// refreshFamilyKey 刷新令牌家族键
+func refreshFamilyKey(familyID string) string {
+	return fmt.Sprintf("refresh_family:%s", familyID)
+}
+
+// userRefreshFamiliesKey 用户刷新令牌家族集合键
+func userRefreshFamiliesKey(userID int) string {
+	return fmt.Sprintf("refresh_family:user:%d", userID)
+}
+
+// deniedTokenKey 访问令牌黑名单键
+func deniedTokenKey(tokenID string) string {
+	return fmt.Sprintf("token:denied:%s", tokenID)
+}
+
+// userRevokedBeforeKey 用户令牌吊销时间键
+func userRevokedBeforeKey(userID int) string {
+	return fmt.Sprintf("token:revoked_before:%d", userID)
+}
+
+// CreateRefreshFamily 创建刷新令牌家族,同时记录登录会话信息
+// 参数:
+// - ctx: 上下文
+// - session: 会话信息,包含会话ID(即令牌家族ID)、用户ID和设备信息
+// - tokenID: 当前有效的刷新令牌ID
+// - ttl: 过期时间
+// 返回:
+// - error: 错误信息
+func (r *TokenRepositoryImpl) CreateRefreshFamily(ctx context.Context, session *entity.Session, tokenID string, ttl time.Duration) error {
+	now := time.Now().Unix()
+	key := refreshFamilyKey(session.SessionID)
+
+	pipe := r.rdb.TxPipeline()
+	pipe.HSet(ctx, key,
+		"user_id", session.UserID,
+		"current", tokenID,
+		"device", session.Device,
+		"user_agent", session.UserAgent,
+		"ip", session.IP,
+		"created_at", now,
+		"last_seen", now,
+	)
+	pipe.Expire(ctx, key, ttl)
+	pipe.SAdd(ctx, userRefreshFamiliesKey(session.UserID), session.SessionID)
+	pipe.Expire(ctx, userRefreshFamiliesKey(session.UserID), ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ListSessions 获取用户的全部登录会话,按最近活跃时间倒序
+// 已过期的家族会从用户集合中顺带清理
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - []*entity.Session: 会话列表
+// - error: 错误信息
+func (r *TokenRepositoryImpl) ListSessions(ctx context.Context, userID int) ([]*entity.Session, error) {
+	familyIDs, err := r.rdb.SMembers(ctx, userRefreshFamiliesKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(familyIDs) == 0 {
+		return []*entity.Session{}, nil
+	}
+
+	pipe := r.rdb.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(familyIDs))
+	for i, familyID := range familyIDs {
+		cmds[i] = pipe.HGetAll(ctx, refreshFamilyKey(familyID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	type sessionWithTime struct {
+		session  *entity.Session
+		lastSeen int64
+	}
+	items := make([]sessionWithTime, 0, len(familyIDs))
+	expired := make([]interface{}, 0)
+	for i, cmd := range cmds {
+		values := cmd.Val()
+		if len(values) == 0 {
+			expired = append(expired, familyIDs[i])
+			continue
+		}
+		createdAt, _ := strconv.ParseInt(values["created_at"], 10, 64)
+		lastSeen, _ := strconv.ParseInt(values["last_seen"], 10, 64)
+		items = append(items, sessionWithTime{
+			session: &entity.Session{
+				SessionID:  familyIDs[i],
+				UserID:     userID,
+				Device:     values["device"],
+				UserAgent:  values["user_agent"],
+				IP:         values["ip"],
+				CreatedAt:  formatSessionTime(createdAt),
+				LastSeenAt: formatSessionTime(lastSeen),
+			},
+			lastSeen: lastSeen,
+		})
+	}
+
+	if len(expired) > 0 {
+		if err := r.rdb.SRem(ctx, userRefreshFamiliesKey(userID), expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].lastSeen > items[j].lastSeen
+	})
+	sessions := make([]*entity.Session, len(items))
+	for i, item := range items {
+		sessions[i] = item.session
+	}
+	return sessions, nil
+}
+
+// formatSessionTime 格式化会话时间,未记录时返回空字符串
+func formatSessionTime(unix int64) string {
+	if unix == 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).Format(sessionTimeLayout)
+}
+
+// TouchSession 检查会话是否有效,并按最小间隔更新最近活跃时间
+// 参数:
+// - ctx: 上下文
+// - familyID: 会话ID(令牌家族ID)
+// - now: 当前时间
+// - interval: 最近活跃时间的最小更新间隔,避免每次请求都写入
+// 返回:
+// - bool: 会话是否仍然有效
+// - error: 错误信息
+func (r *TokenRepositoryImpl) TouchSession(ctx context.Context, familyID string, now time.Time, interval time.Duration) (bool, error) {
+	result, err := touchSessionScript.Run(ctx, r.rdb, []string{refreshFamilyKey(familyID)}, now.Unix(), int64(interval.Seconds())).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// RotateRefreshToken 轮换刷新令牌
+// 参数:
+// - ctx: 上下文
+// - familyID: 令牌家族ID
+// - tokenID: 请求携带的刷新令牌ID
+// - newTokenID: 新的刷新令牌ID
+// - ttl: 过期时间
+// 返回:
+// - repository.RefreshRotateResult: 轮换结果
+// - error: 错误信息
+func (r *TokenRepositoryImpl) RotateRefreshToken(ctx context.Context, familyID, tokenID, newTokenID string, ttl time.Duration) (repository.RefreshRotateResult, error) {
+	result, err := rotateRefreshScript.Run(ctx, r.rdb, []string{refreshFamilyKey(familyID)}, tokenID, newTokenID, ttl.Milliseconds()).Int()
+	if err != nil {
+		return repository.RefreshFamilyNotFound, err
+	}
+
+	switch result {
+	case 1:
+		return repository.RefreshRotated, nil
+	case 0:
+		return repository.RefreshTokenReused, nil
+	default:
+		return repository.RefreshFamilyNotFound, nil
+	}
+}
+
+// RevokeRefreshFamily 吊销刷新令牌家族
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - familyID: 令牌家族ID
+// 返回:
+// - error: 错误信息
+func (r *TokenRepositoryImpl) RevokeRefreshFamily(ctx context.Context, userID int, familyID string) error {
+	pipe := r.rdb.TxPipeline()
+	pipe.Del(ctx, refreshFamilyKey(familyID))
+	pipe.SRem(ctx, userRefreshFamiliesKey(userID), familyID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RevokeUserRefreshFamilies 吊销用户的全部刷新令牌家族
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - error: 错误信息
+func (r *TokenRepositoryImpl) RevokeUserRefreshFamilies(ctx context.Context, userID int) error {
+	familyIDs, err := r.rdb.SMembers(ctx, userRefreshFamiliesKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(familyIDs)+1)
+	for _, familyID := range familyIDs {
+		keys = append(keys, refreshFamilyKey(familyID))
+	}
+	keys = append(keys, userRefreshFamiliesKey(userID))
+
+	return r.rdb.Del(ctx, keys...).Err()
+}
+
+// RevokeOtherRefreshFamilies 吊销用户除指定家族外的全部刷新令牌家族
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - keepFamilyID: 需要保留的令牌家族ID
+// 返回:
+// - int: 吊销的家族数量
+// - error: 错误信息
+func (r *TokenRepositoryImpl) RevokeOtherRefreshFamilies(ctx context.Context, userID int, keepFamilyID string) (int, error) {
+	familyIDs, err := r.rdb.SMembers(ctx, userRefreshFamiliesKey(userID)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	keys := make([]string, 0, len(familyIDs))
+	members := make([]interface{}, 0, len(familyIDs))
+	for _, familyID := range familyIDs {
+		if familyID == keepFamilyID {
+			continue
+		}
+		keys = append(keys, refreshFamilyKey(familyID))
+		members = append(members, familyID)
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	pipe := r.rdb.TxPipeline()
+	pipe.Del(ctx, keys...)
+	pipe.SRem(ctx, userRefreshFamiliesKey(userID), members...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
+
+// DenyToken 将访问令牌加入黑名单
+// 参数:
+// - ctx: 上下文
+// - tokenID: 令牌ID(jti)
+// - ttl: 黑名单保留时间,应不短于令牌剩余有效期
+// 返回:
+// - error: 错误信息
+func (r *TokenRepositoryImpl) DenyToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	if tokenID == "" || ttl <= 0 {
+		return nil
+	}
+	return r.rdb.Set(ctx, deniedTokenKey(tokenID), 1, ttl).Err()
+}
+
+// RevokeUserTokens 吊销用户在指定时间之前签发的全部令牌,同时吊销其全部刷新令牌家族
+// 吊销时间点和令牌签发时间均精确到毫秒,吊销后立即重新登录签发的令牌不会被误判为已吊销
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - before: 吊销时间点
+// - ttl: 吊销标记保留时间,应不短于令牌最长有效期
+// 返回:
+// - error: 错误信息
+func (r *TokenRepositoryImpl) RevokeUserTokens(ctx context.Context, userID int, before time.Time, ttl time.Duration) error {
+	err := r.rdb.Set(ctx, userRevokedBeforeKey(userID), before.UnixMilli(), ttl).Err()
+	if err != nil {
+		return err
+	}
+	return r.RevokeUserRefreshFamilies(ctx, userID)
+}
+
+// IsTokenRevoked 检查令牌是否已被吊销
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - tokenID: 令牌ID(jti),为空时仅检查用户级吊销标记
+// - issuedAt: 令牌签发时间
+// 返回:
+// - bool: 是否已被吊销
+// - error: 错误信息
+func (r *TokenRepositoryImpl) IsTokenRevoked(ctx context.Context, userID int, tokenID string, issuedAt time.Time) (bool, error) {
+	pipe := r.rdb.Pipeline()
+	revokedBefore := pipe.Get(ctx, userRevokedBeforeKey(userID))
+	var denied *redis.IntCmd
+	if tokenID != "" {
+		denied = pipe.Exists(ctx, deniedTokenKey(tokenID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return false, err
+	}
+
+	if denied != nil && denied.Val() > 0 {
+		return true, nil
+	}
+
+	if value, err := revokedBefore.Result(); err == nil {
+		before, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		if issuedAt.UnixMilli() < before {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
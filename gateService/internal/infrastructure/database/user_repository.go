@@ -103,24 +103,82 @@ func (r *UserRepositoryImpl) IsExistUser(ctx context.Context, email string) (boo
 	return true, nil
 }
 
-// VerifyUser 验证用户信息
+// GetUserCredentialByEmail 通过邮箱查询正常状态用户的登录凭证
 // 参数:
 // - ctx: 上下文
-// - user: 待验证的用户信息
+// - email: 用户邮箱
 // 返回:
-// - bool: 验证是否通过
+// - *entity.UserInfo: 用户信息(含密码哈希),用户不存在时返回nil
 // - error: 错误信息
-func (r *UserRepositoryImpl) VerifyUser(ctx context.Context, user *entity.UserInfo) (bool, error) {
-	query := "SELECT user_id, username, avatar_url, full_name, gender, birth_date FROM user_infos WHERE email = ? AND password = ? AND status = 1"
-	row := r.db.QueryRowContext(ctx, query, user.Email, user.Password)
-	err := row.Scan(&user.UserID, &user.Username, &user.AvatarURL, &user.FullName, &user.Gender, &user.BirthDate)
+func (r *UserRepositoryImpl) GetUserCredentialByEmail(ctx context.Context, email string) (*entity.UserInfo, error) {
+	query := "SELECT user_id, username, email, password, avatar_url, full_name, gender, birth_date, role FROM user_infos WHERE email = ? AND status = 1"
+	row := r.db.QueryRowContext(ctx, query, email)
+	var user entity.UserInfo
+	err := row.Scan(&user.UserID, &user.Username, &user.Email, &user.Password, &user.AvatarURL, &user.FullName, &user.Gender, &user.BirthDate, &user.Role)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return false, nil
+			return nil, nil
 		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUserPassword 更新用户密码哈希
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - passwordHash: 密码哈希
+// 返回:
+// - error: 错误信息
+func (r *UserRepositoryImpl) UpdateUserPassword(ctx context.Context, userID int, passwordHash string) error {
+	query := "UPDATE user_infos SET password = ? WHERE user_id = ?"
+	_, err := r.db.ExecContext(ctx, query, passwordHash, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ConvertTestAccount 将体验账号转为正式账号,更新用户名、邮箱和密码
+// 参数:
+// - ctx: 上下文
+// - user: 用户信息,包含用户ID、新用户名、新邮箱和密码哈希
+// 返回:
+// - bool: 是否转换成功,体验账号已被删除或已转换时返回false
+// - error: 错误信息
+func (r *UserRepositoryImpl) ConvertTestAccount(ctx context.Context, user *entity.UserInfo) (bool, error) {
+	query := "UPDATE user_infos SET username = ?, email = ?, password = ? WHERE user_id = ? AND status = 1 AND email LIKE ?"
+	result, err := r.db.ExecContext(ctx, query, user.Username, user.Email, user.Password, user.UserID, "%"+entity.TestAccountEmailSuffix)
+	if err != nil {
 		return false, err
 	}
-	return true, nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// DeleteTestAccount 删除体验账号
+// 参数:
+// - ctx: 上下文
+// - tx: 事务
+// - userID: 用户ID
+// 返回:
+// - bool: 是否删除成功,用户已转为正式账号或已删除时返回false
+// - error: 错误信息
+func (r *UserRepositoryImpl) DeleteTestAccount(ctx context.Context, tx *sql.Tx, userID int) (bool, error) {
+	query := "UPDATE user_infos SET status = 0 WHERE user_id = ? AND status = 1 AND email LIKE ?"
+	result, err := tx.ExecContext(ctx, query, userID, "%"+entity.TestAccountEmailSuffix)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
 }
 
 // GetUserByEmail 通过邮箱获取用户信息
@@ -131,10 +189,10 @@ func (r *UserRepositoryImpl) VerifyUser(ctx context.Context, user *entity.UserIn
 // - *entity.UserInfo: 用户信息
 // - error: 错误信息
 func (r *UserRepositoryImpl) GetUserByEmail(ctx context.Context, email string) (*entity.UserInfo, error) {
-	query := "SELECT * FROM user_infos WHERE email = ?"
+	query := "SELECT user_id, username, email, password, avatar_url, signature, full_name, gender, birth_date, created_at, last_login_at, status, role FROM user_infos WHERE email = ?"
 	row := r.db.QueryRowContext(ctx, query, email)
 	var user entity.UserInfo
-	err := row.Scan(&user.UserID, &user.Username, &user.Email, &user.Password, &user.FullName, &user.Gender, &user.BirthDate, &user.CreatedAt, &user.LastLoginAt, &user.AvatarURL)
+	err := row.Scan(&user.UserID, &user.Username, &user.Email, &user.Password, &user.AvatarURL, &user.Signature, &user.FullName, &user.Gender, &user.BirthDate, &user.CreatedAt, &user.LastLoginAt, &user.Status, &user.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -149,10 +207,10 @@ func (r *UserRepositoryImpl) GetUserByEmail(ctx context.Context, email string) (
 // - *entity.UserInfo: 用户信息
 // - error: 错误信息
 func (r *UserRepositoryImpl) GetUserByID(ctx context.Context, userID int) (*entity.UserInfo, error) {
-	query := "SELECT * FROM user_infos WHERE user_id = ?"
+	query := "SELECT user_id, username, email, password, avatar_url, signature, full_name, gender, birth_date, created_at, last_login_at, status, role FROM user_infos WHERE user_id = ?"
 	row := r.db.QueryRowContext(ctx, query, userID)
 	var user entity.UserInfo
-	err := row.Scan(&user.UserID, &user.Username, &user.Email, &user.Password, &user.AvatarURL, &user.Signature, &user.FullName, &user.Gender, &user.BirthDate, &user.CreatedAt, &user.LastLoginAt, &user.Status)
+	err := row.Scan(&user.UserID, &user.Username, &user.Email, &user.Password, &user.AvatarURL, &user.Signature, &user.FullName, &user.Gender, &user.BirthDate, &user.CreatedAt, &user.LastLoginAt, &user.Status, &user.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +248,82 @@ func (r *UserRepositoryImpl) DeleteUser(ctx context.Context, tx *sql.Tx, userID
 	return nil
 }
 
+// GetUserRole 获取用户当前角色
+// 优先读取Redis缓存,未命中时查询数据库并回填缓存,用户不存在时不缓存
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - string: 用户角色,用户不存在或已删除时返回空字符串
+// - error: 错误信息
+func (r *UserRepositoryImpl) GetUserRole(ctx context.Context, userID int) (string, error) {
+	key := fmt.Sprintf("user:role:%d", userID)
+	role, err := r.rdb.Get(ctx, key).Result()
+	if err == nil {
+		return role, nil
+	}
+	if err != redis.Nil {
+		return "", err
+	}
+
+	query := "SELECT role FROM user_infos WHERE user_id = ? AND status = 1"
+	err = r.db.QueryRowContext(ctx, query, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.rdb.Set(ctx, key, role, 10*time.Minute).Err(); err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+// UpdateUserRole 更新用户角色,并清除角色缓存使其立即生效
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// - role: 新角色
+// 返回:
+// - error: 错误信息
+func (r *UserRepositoryImpl) UpdateUserRole(ctx context.Context, userID int, role string) error {
+	query := "UPDATE user_infos SET role = ? WHERE user_id = ? AND status = 1"
+	result, err := r.db.ExecContext(ctx, query, role, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		// 角色未变化时同样影响0行,需区分用户是否存在
+		var exist int
+		err := r.db.QueryRowContext(ctx, "SELECT 1 FROM user_infos WHERE user_id = ? AND status = 1", userID).Scan(&exist)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("用户不存在")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.DeleteUserRoleCache(ctx, userID)
+}
+
+// DeleteUserRoleCache 删除用户角色缓存
+// 参数:
+// - ctx: 上下文
+// - userID: 用户ID
+// 返回:
+// - error: 错误信息
+func (r *UserRepositoryImpl) DeleteUserRoleCache(ctx context.Context, userID int) error {
+	return r.rdb.Del(ctx, fmt.Sprintf("user:role:%d", userID)).Err()
+}
+
 // GetUsersByIDs 通过用户ID列表批量查询用户信息
 // 参数:
 // - ctx: 上下文
@@ -426,3 +560,26 @@ func (r *UserRepositoryImpl) SetInRedis(ctx context.Context, key string, value i
 	}
 	return nil
 }
+
+// IncrInRedis 对Redis中的计数键加一,首次创建时设置过期时间
+// 参数:
+// - ctx: 上下文
+// - key: 键
+// - ttl: 过期时间
+// 返回:
+// - int: 加一后的计数
+// - error: 错误信息
+func (r *UserRepositoryImpl) IncrInRedis(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	// 计数与设置过期时间在同一脚本中原子执行,形成固定统计窗口,不会留下永不过期的计数
+	return incrWithWindowScript.Run(ctx, r.rdb, []string{key}, ttl.Milliseconds()).Int()
+}
+
+// DeleteInRedis 删除Redis中的键
+// 参数:
+// - ctx: 上下文
+// - key: 键
+// 返回:
+// - error: 错误信息
+func (r *UserRepositoryImpl) DeleteInRedis(ctx context.Context, key string) error {
+	return r.rdb.Del(ctx, key).Err()
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// verifyCodeScript 原子地校验并消费验证码
+// 返回: 1-校验通过, 0-验证码错误, -1-验证码不存在或已失效
+var verifyCodeScript = redis.NewScript(`
+local code = redis.call('HGET', KEYS[1], 'code')
+if not code then
+	return -1
+end
+if code == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return 1
+end
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+if attempts >= tonumber(ARGV[2]) then
+	redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// VerificationRepositoryImpl 实现了验证凭证仓储接口,仅使用Redis
+type VerificationRepositoryImpl struct {
+	rdb *redis.Client
+}
+
+// NewVerificationRepositoryImpl 创建一个新的验证凭证仓储实现实例
+// 参数:
+// - rdb: Redis连接对象
+// 返回:
+// - *VerificationRepositoryImpl: 验证凭证仓储实现实例
+func NewVerificationRepositoryImpl(rdb *redis.Client) *VerificationRepositoryImpl {
+	return &VerificationRepositoryImpl{
+		rdb: rdb,
+	}
+}
+
+// verificationCodeKey 验证码键
+func verificationCodeKey(scene, email string) string {
+	return fmt.Sprintf("verification:code:%s:%s", scene, email)
+}
+
+// verificationSendCountKey 发送次数计数键
+func verificationSendCountKey(source string) string {
+	return fmt.Sprintf("verification:send_count:%s", source)
+}
+
+// resetTokenKey 密码重置令牌键
+func resetTokenKey(tokenHash string) string {
+	return fmt.Sprintf("reset_password:token:%s", tokenHash)
+}
+
+// userResetTokenKey 用户当前密码重置令牌键
+func userResetTokenKey(userID int) string {
+	return fmt.Sprintf("reset_password:user:%d", userID)
+}
+
+// SaveVerificationCode 保存验证码,覆盖同一场景下该邮箱此前的验证码
+// 参数:
+// - ctx: 上下文
+// - scene: 验证码使用场景,如register
+// - email: 邮箱
+// - code: 验证码
+// - ttl: 有效期
+// 返回:
+// - error: 错误信息
+func (r *VerificationRepositoryImpl) SaveVerificationCode(ctx context.Context, scene, email, code string, ttl time.Duration) error {
+	key := verificationCodeKey(scene, email)
+	pipe := r.rdb.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "code", code, "attempts", 0)
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// VerifyVerificationCode 校验并消费验证码
+// 参数:
+// - ctx: 上下文
+// - scene: 验证码使用场景
+// - email: 邮箱
+// - code: 待校验的验证码
+// - maxAttempts: 最大尝试次数
+// 返回:
+// - bool: 是否校验通过
+// - error: 错误信息
+func (r *VerificationRepositoryImpl) VerifyVerificationCode(ctx context.Context, scene, email, code string, maxAttempts int) (bool, error) {
+	result, err := verifyCodeScript.Run(ctx, r.rdb, []string{verificationCodeKey(scene, email)}, code, maxAttempts).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// IncrSendCount 累加某个来源在时间窗口内的发送次数
+// 参数:
+// - ctx: 上下文
+// - source: 发送来源,如客户端IP
+// - window: 统计窗口
+// 返回:
+// - int64: 当前窗口内的发送次数(含本次)
+// - error: 错误信息
+func (r *VerificationRepositoryImpl) IncrSendCount(ctx context.Context, source string, window time.Duration) (int64, error) {
+	// 计数与设置过期时间原子执行,不会留下永不过期的计数导致该来源无法再发送
+	return incrWithWindowScript.Run(ctx, r.rdb, []string{verificationSendCountKey(source)}, window.Milliseconds()).Int64()
+}
+
+// SaveResetToken 保存密码重置令牌,同时使该用户此前未使用的重置令牌失效
+// 参数:
+// - ctx: 上下文
+// - tokenHash: 重置令牌的哈希值
+// - userID: 用户ID
+// - ttl: 有效期
+// 返回:
+// - error: 错误信息
+func (r *VerificationRepositoryImpl) SaveResetToken(ctx context.Context, tokenHash string, userID int, ttl time.Duration) error {
+	oldHash, err := r.rdb.Get(ctx, userResetTokenKey(userID)).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	pipe := r.rdb.TxPipeline()
+	if oldHash != "" {
+		pipe.Del(ctx, resetTokenKey(oldHash))
+	}
+	pipe.Set(ctx, resetTokenKey(tokenHash), userID, ttl)
+	pipe.Set(ctx, userResetTokenKey(userID), tokenHash, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// ConsumeResetToken 消费密码重置令牌,令牌只能使用一次
+// 参数:
+// - ctx: 上下文
+// - tokenHash: 重置令牌的哈希值
+// 返回:
+// - int: 令牌对应的用户ID,令牌不存在或已失效时返回0
+// - error: 错误信息
+func (r *VerificationRepositoryImpl) ConsumeResetToken(ctx context.Context, tokenHash string) (int, error) {
+	value, err := r.rdb.GetDel(ctx, resetTokenKey(tokenHash)).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	userID, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.rdb.Del(ctx, userResetTokenKey(userID)).Err(); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"gateService/internal/domain/entity"
@@ -13,6 +14,27 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// 由视频目录派生的缓存,目录发生变更时需要清除
+const (
+	videoFiltersCacheKey   = "VideoCatalog:Filters"
+	topAnimeGenresCacheKey = "VideoCatalog:TopGenres"
+	catalogCacheTTL        = time.Hour
+)
+
+// 播放源缓存时间
+const videoSourceCacheTTL = 4 * time.Hour
+
+// incrWithWindowScript 原子地计数加一,计数没有过期时间时设置过期时间
+// 与计数在同一脚本中执行,不会因设置过期时间失败而留下永不过期的计数
+// 返回: 加一后的计数
+var incrWithWindowScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) == -1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
 type VideoRepositoryImpl struct {
 	db  *sql.DB
 	rdb *redis.Client
@@ -25,10 +47,30 @@ func NewVideoRepositoryImpl(db *sql.DB, rdb *redis.Client) *VideoRepositoryImpl
 	}
 }
 
-func (r *VideoRepositoryImpl) CreateVideo(ctx context.Context, video *entity.Video) error {
+func (r *VideoRepositoryImpl) CreateVideo(ctx context.Context, video *entity.Video, genres []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO anime_videos (video_name, release_date, area, description, cover_image_url, uploader_id) VALUES (?, ?, ?, ?, ?, ?)`
-	_, err := r.db.ExecContext(ctx, query, video.Name, video.ReleaseDate, video.Area, video.Description, video.CoverImageUrl, video.UploaderID)
-	return err
+	result, err := tx.ExecContext(ctx, query, video.Name, video.ReleaseDate, video.Area, video.Description, video.CoverImageUrl, video.UploaderID)
+	if err != nil {
+		return err
+	}
+	videoID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if err := insertAnimeGenres(ctx, tx, int(videoID), genres); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	video.ID = int(videoID)
+	return nil
 }
 
 func (r *VideoRepositoryImpl) UpdateVideo(ctx context.Context, video *entity.Video) error {
@@ -38,8 +80,276 @@ func (r *VideoRepositoryImpl) UpdateVideo(ctx context.Context, video *entity.Vid
 }
 
 func (r *VideoRepositoryImpl) DeleteVideo(ctx context.Context, videoID int) error {
-	query := `DELETE FROM anime_videos WHERE video_id = ?`
-	_, err := r.db.ExecContext(ctx, query, videoID)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 评论和收藏是用户数据,不随视频级联删除
+	var referenced bool
+	query := `SELECT EXISTS(SELECT 1 FROM comments WHERE video_id = ?) OR EXISTS(SELECT 1 FROM user_anime_collections WHERE video_id = ?)`
+	if err := tx.QueryRowContext(ctx, query, videoID, videoID).Scan(&referenced); err != nil {
+		return err
+	}
+	if referenced {
+		return errors.New("视频已被评论或收藏,无法删除")
+	}
+
+	// 删除剧集时级联删除观看进度,类型随视频级联删除
+	if _, err := tx.ExecContext(ctx, `DELETE FROM video_urls WHERE video_id = ?`, videoID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM anime_videos WHERE video_id = ?`, videoID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *VideoRepositoryImpl) GetVideoIDByIdentity(ctx context.Context, name, releaseDate, area string) (int, error) {
+	query := `SELECT video_id FROM anime_videos WHERE video_name = ? AND release_date = ? AND area = ?`
+	var videoID int
+	err := r.db.QueryRowContext(ctx, query, name, releaseDate, area).Scan(&videoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return videoID, err
+}
+
+func (r *VideoRepositoryImpl) ReplaceAnimeGenres(ctx context.Context, videoID int, genres []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM anime_genres WHERE anime_id = ?`, videoID); err != nil {
+		return err
+	}
+	if err := insertAnimeGenres(ctx, tx, videoID, genres); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertAnimeGenres 在事务中批量写入视频类型
+func insertAnimeGenres(ctx context.Context, tx *sql.Tx, videoID int, genres []string) error {
+	if len(genres) == 0 {
+		return nil
+	}
+	placeholders := make([]string, len(genres))
+	args := make([]interface{}, 0, len(genres)*2)
+	for i, genre := range genres {
+		placeholders[i] = "(?, ?)"
+		args = append(args, genre, videoID)
+	}
+	query := fmt.Sprintf(`INSERT INTO anime_genres (genre, anime_id) VALUES %s`, strings.Join(placeholders, ","))
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *VideoRepositoryImpl) GetAnimeAliases(ctx context.Context, videoID int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT alias FROM anime_aliases WHERE anime_id = ? ORDER BY id ASC`, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := make([]string, 0)
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, rows.Err()
+}
+
+func (r *VideoRepositoryImpl) ReplaceAnimeAliases(ctx context.Context, videoID int, aliases []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM anime_aliases WHERE anime_id = ?`, videoID); err != nil {
+		return err
+	}
+
+	if len(aliases) > 0 {
+		placeholders := make([]string, len(aliases))
+		args := make([]interface{}, 0, len(aliases)*2)
+		for i, alias := range aliases {
+			placeholders[i] = "(?, ?)"
+			args = append(args, videoID, alias)
+		}
+		query := fmt.Sprintf(`INSERT INTO anime_aliases (anime_id, alias) VALUES %s`, strings.Join(placeholders, ","))
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *VideoRepositoryImpl) GetVideoEpisodes(ctx context.Context, videoID int) ([]*entity.VideoEpisode, error) {
+	query := `SELECT video_id, episode, video_url, status FROM video_urls WHERE video_id = ? ORDER BY id ASC`
+	rows, err := r.db.QueryContext(ctx, query, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	episodes := make([]*entity.VideoEpisode, 0)
+	for rows.Next() {
+		var episode entity.VideoEpisode
+		var status sql.NullInt16
+		if err := rows.Scan(&episode.VideoID, &episode.Episode, &episode.VideoURL, &status); err != nil {
+			return nil, err
+		}
+		episode.Status = int8(status.Int16)
+		episodes = append(episodes, &episode)
+	}
+	return episodes, rows.Err()
+}
+
+func (r *VideoRepositoryImpl) ReplaceVideoEpisodes(ctx context.Context, videoID int, episodes []*entity.VideoEpisode) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 观看进度通过(video_id, episode)外键级联到剧集,保留的剧集不能先删后插
+	if len(episodes) == 0 {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM video_urls WHERE video_id = ?`, videoID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	placeholders := make([]string, len(episodes))
+	deleteArgs := make([]interface{}, 0, len(episodes)+1)
+	insertArgs := make([]interface{}, 0, len(episodes)*3)
+	deleteArgs = append(deleteArgs, videoID)
+	for i, episode := range episodes {
+		placeholders[i] = "?"
+		deleteArgs = append(deleteArgs, episode.Episode)
+		insertArgs = append(insertArgs, videoID, episode.Episode, episode.VideoURL)
+	}
+
+	query := fmt.Sprintf(`DELETE FROM video_urls WHERE video_id = ? AND episode NOT IN (%s)`, strings.Join(placeholders, ","))
+	if _, err := tx.ExecContext(ctx, query, deleteArgs...); err != nil {
+		return err
+	}
+
+	values := make([]string, len(episodes))
+	for i := range episodes {
+		values[i] = "(?, ?, ?, 1)"
+	}
+	query = fmt.Sprintf(`INSERT INTO video_urls (video_id, episode, video_url, status) VALUES %s
+		ON DUPLICATE KEY UPDATE video_url = VALUES(video_url), status = 1`, strings.Join(values, ","))
+	if _, err := tx.ExecContext(ctx, query, insertArgs...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *VideoRepositoryImpl) GetStoredVideoSources(ctx context.Context, videoID int, episode string) (*entity.StoredVideoSources, error) {
+	// 在数据库中计算爬取时长,避免应用与数据库时区不一致
+	query := `SELECT video_url, sources, scraped_at, TIMESTAMPDIFF(SECOND, scraped_at, NOW())
+			  FROM video_urls
+			  WHERE video_id = ? AND episode = ?`
+	stored := &entity.StoredVideoSources{VideoID: videoID, Episode: episode}
+	var sources, scrapedAt sql.NullString
+	var age sql.NullInt64
+	err := r.db.QueryRowContext(ctx, query, videoID, episode).Scan(&stored.VideoURL, &sources, &scrapedAt, &age)
+	if err != nil {
+		return nil, err
+	}
+
+	stored.Sources = make([]*entity.VideoSource, 0)
+	if sources.Valid && sources.String != "" {
+		if err := json.Unmarshal([]byte(sources.String), &stored.Sources); err != nil {
+			return nil, fmt.Errorf("解析播放源失败: %v", err)
+		}
+	}
+	stored.ScrapedAt = scrapedAt.String
+	stored.Age = time.Duration(age.Int64) * time.Second
+	return stored, nil
+}
+
+func (r *VideoRepositoryImpl) SaveVideoSources(ctx context.Context, videoID int, episode string, sources []*entity.VideoSource) error {
+	sourcesJson, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE video_urls SET sources = ?, scraped_at = NOW() WHERE video_id = ? AND episode = ?`
+	_, err = r.db.ExecContext(ctx, query, string(sourcesJson), videoID, episode)
+	return err
+}
+
+func (r *VideoRepositoryImpl) RemoveStoredVideoSources(ctx context.Context, videoID int, episode string, urls []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 加锁读取,避免覆盖同时写入的新爬取结果
+	query := `SELECT sources FROM video_urls WHERE video_id = ? AND episode = ? FOR UPDATE`
+	var sourcesJson sql.NullString
+	err = tx.QueryRowContext(ctx, query, videoID, episode).Scan(&sourcesJson)
+	if err == sql.ErrNoRows || (err == nil && (!sourcesJson.Valid || sourcesJson.String == "")) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var sources []*entity.VideoSource
+	if err := json.Unmarshal([]byte(sourcesJson.String), &sources); err != nil {
+		return fmt.Errorf("解析播放源失败: %v", err)
+	}
+	removeURLs := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		removeURLs[url] = true
+	}
+	remaining := make([]*entity.VideoSource, 0, len(sources))
+	for _, source := range sources {
+		if !removeURLs[source.URL] {
+			remaining = append(remaining, source)
+		}
+	}
+	if len(remaining) == len(sources) {
+		return nil
+	}
+
+	if len(remaining) == 0 {
+		_, err = tx.ExecContext(ctx, `UPDATE video_urls SET sources = NULL, scraped_at = NULL WHERE video_id = ? AND episode = ?`, videoID, episode)
+	} else {
+		var remainingJson []byte
+		remainingJson, err = json.Marshal(remaining)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, `UPDATE video_urls SET sources = ? WHERE video_id = ? AND episode = ?`, string(remainingJson), videoID, episode)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *VideoRepositoryImpl) ClearStoredVideoSources(ctx context.Context, videoID int, episode string) error {
+	query := `UPDATE video_urls SET sources = NULL, scraped_at = NULL WHERE video_id = ? AND episode = ?`
+	_, err := r.db.ExecContext(ctx, query, videoID, episode)
+	return err
+}
+
+func (r *VideoRepositoryImpl) UpdateEpisodeVideoURL(ctx context.Context, videoID int, episode string, videoURL string) error {
+	query := `UPDATE video_urls SET video_url = ? WHERE video_id = ? AND episode = ?`
+	_, err := r.db.ExecContext(ctx, query, videoURL, videoID, episode)
 	return err
 }
 
@@ -268,6 +578,25 @@ func (r *VideoRepositoryImpl) GetVideosByFilters(ctx context.Context, video *ent
 }
 
 func (r *VideoRepositoryImpl) GetVideoFilters(ctx context.Context) (*entity.VideoFilters, error) {
+	if cached, err := r.rdb.Get(ctx, videoFiltersCacheKey).Bytes(); err == nil {
+		filters := &entity.VideoFilters{}
+		if json.Unmarshal(cached, filters) == nil {
+			return filters, nil
+		}
+	}
+
+	filters, err := r.queryVideoFilters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(filters); err == nil {
+		r.rdb.Set(ctx, videoFiltersCacheKey, data, catalogCacheTTL)
+	}
+	return filters, nil
+}
+
+func (r *VideoRepositoryImpl) queryVideoFilters(ctx context.Context) (*entity.VideoFilters, error) {
 	// 1. 并发执行三个独立查询
 	var (
 		wg          sync.WaitGroup
@@ -395,6 +724,25 @@ func (r *VideoRepositoryImpl) GetAnimesByGenre(ctx context.Context, genre string
 }
 
 func (r *VideoRepositoryImpl) GetTopAnimeGenres(ctx context.Context) ([]string, error) {
+	if cached, err := r.rdb.Get(ctx, topAnimeGenresCacheKey).Bytes(); err == nil {
+		var genres []string
+		if json.Unmarshal(cached, &genres) == nil {
+			return genres, nil
+		}
+	}
+
+	genres, err := r.queryTopAnimeGenres(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(genres); err == nil {
+		r.rdb.Set(ctx, topAnimeGenresCacheKey, data, catalogCacheTTL)
+	}
+	return genres, nil
+}
+
+func (r *VideoRepositoryImpl) queryTopAnimeGenres(ctx context.Context) ([]string, error) {
 	query := `
 		SELECT genre
 		FROM anime_genres
@@ -420,24 +768,474 @@ func (r *VideoRepositoryImpl) GetTopAnimeGenres(ctx context.Context) ([]string,
 	return genres, nil
 }
 
-func (r *VideoRepositoryImpl) CacheVideoURL(ctx context.Context, key string, value string) error {
-	err := r.rdb.Set(ctx, key, value, 4*time.Hour).Err()
+// CacheVideoSources 以有序集合缓存播放源,成员为播放源的JSON,分数为评分
+// 先删除旧缓存再写入,避免残留上一次爬取的失效播放源
+func (r *VideoRepositoryImpl) CacheVideoSources(ctx context.Context, key string, sources []*entity.VideoSource) error {
+	members := make([]redis.Z, 0, len(sources))
+	for _, source := range sources {
+		member, err := json.Marshal(source)
+		if err != nil {
+			return err
+		}
+		members = append(members, redis.Z{Score: source.Score, Member: string(member)})
+	}
+
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(members) > 0 {
+			pipe.ZAdd(ctx, key, members...)
+			pipe.Expire(ctx, key, videoSourceCacheTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *VideoRepositoryImpl) GetVideoSources(ctx context.Context, key string) ([]*entity.VideoSource, error) {
+	members, err := r.rdb.ZRevRangeWithScores(ctx, key, 0, -1).Result()
+	if err != nil {
+		// 旧版本以字符串缓存单个地址,按未缓存处理,重新爬取后覆盖
+		if strings.HasPrefix(err.Error(), "WRONGTYPE") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	sources := make([]*entity.VideoSource, 0, len(members))
+	for _, member := range members {
+		var source entity.VideoSource
+		if err := json.Unmarshal([]byte(member.Member.(string)), &source); err != nil {
+			return nil, err
+		}
+		source.Score = member.Score
+		sources = append(sources, &source)
+	}
+	return sources, nil
+}
+
+// DemoteVideoSource 按播放地址查找成员并降低评分
+// 使用XX选项只更新已存在的成员,查找后缓存被重新爬取覆盖时不会写入旧成员
+func (r *VideoRepositoryImpl) DemoteVideoSource(ctx context.Context, key string, url string, penalty float64) (bool, error) {
+	members, err := r.rdb.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "WRONGTYPE") {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, member := range members {
+		var source entity.VideoSource
+		if err := json.Unmarshal([]byte(member), &source); err != nil || source.URL != url {
+			continue
+		}
+		err := r.rdb.ZAddArgsIncr(ctx, key, redis.ZAddArgs{
+			XX:      true,
+			Members: []redis.Z{{Score: -penalty, Member: member}},
+		}).Err()
+		if err == redis.Nil {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	return false, nil
+}
+
+// RemoveVideoSources 按播放地址查找成员并移除,返回剩余的播放源数量
+func (r *VideoRepositoryImpl) RemoveVideoSources(ctx context.Context, key string, urls []string) (int64, error) {
+	members, err := r.rdb.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	removeURLs := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		removeURLs[url] = true
+	}
+	removeMembers := make([]interface{}, 0, len(urls))
+	for _, member := range members {
+		var source entity.VideoSource
+		if err := json.Unmarshal([]byte(member), &source); err == nil && removeURLs[source.URL] {
+			removeMembers = append(removeMembers, member)
+		}
+	}
+	if len(removeMembers) == 0 {
+		return int64(len(members)), nil
+	}
+
+	pipe := r.rdb.TxPipeline()
+	pipe.ZRem(ctx, key, removeMembers...)
+	remainingCmd := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return remainingCmd.Val(), nil
+}
+
+func (r *VideoRepositoryImpl) DeleteVideoSources(ctx context.Context, key string) error {
+	return r.rdb.Del(ctx, key).Err()
+}
+
+func (r *VideoRepositoryImpl) MarkBrokenLinkReport(ctx context.Context, key string, window time.Duration) (bool, error) {
+	return r.rdb.SetNX(ctx, key, time.Now().Unix(), window).Result()
+}
+
+func (r *VideoRepositoryImpl) IncrBrokenLinkCount(ctx context.Context, key string, window time.Duration) (int, error) {
+	return r.incrWithWindow(ctx, key, window)
+}
+
+func (r *VideoRepositoryImpl) MarkVideoSourceReport(ctx context.Context, key string, window time.Duration) (bool, error) {
+	return r.rdb.SetNX(ctx, key, time.Now().Unix(), window).Result()
+}
+
+func (r *VideoRepositoryImpl) IncrVideoReportCount(ctx context.Context, key string, window time.Duration) (int, error) {
+	return r.incrWithWindow(ctx, key, window)
+}
+
+func (r *VideoRepositoryImpl) MarkVideoPrefetch(ctx context.Context, key string, window time.Duration) (bool, error) {
+	return r.rdb.SetNX(ctx, key, time.Now().Unix(), window).Result()
+}
+
+func (r *VideoRepositoryImpl) IncrVideoPrefetchCount(ctx context.Context, key string, window time.Duration) (int, error) {
+	return r.incrWithWindow(ctx, key, window)
+}
+
+// incrWithWindow 计数加一,首次计数时设置过期时间,形成固定统计窗口
+func (r *VideoRepositoryImpl) incrWithWindow(ctx context.Context, key string, window time.Duration) (int, error) {
+	return incrWithWindowScript.Run(ctx, r.rdb, []string{key}, window.Milliseconds()).Int()
+}
+
+func (r *VideoRepositoryImpl) GetCatalogRecordsByIDs(ctx context.Context, videoIDs []int) (map[int]*entity.CatalogRecord, error) {
+	result := make(map[int]*entity.CatalogRecord, len(videoIDs))
+	if len(videoIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(videoIDs))
+	args := make([]interface{}, len(videoIDs))
+	for i, videoID := range videoIDs {
+		placeholders[i] = "?"
+		args[i] = videoID
+	}
+
+	records, err := r.queryCatalogRecords(ctx, fmt.Sprintf("video_id IN (%s)", strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		result[record.VideoID] = record
+	}
+	return result, nil
+}
+
+func (r *VideoRepositoryImpl) GetCatalogRecordsByIdentities(ctx context.Context, records []*entity.CatalogRecord) (map[string]*entity.CatalogRecord, error) {
+	result := make(map[string]*entity.CatalogRecord, len(records))
+	if len(records) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(records))
+	args := make([]interface{}, 0, len(records)*3)
+	for i, record := range records {
+		placeholders[i] = "(?, ?, ?)"
+		args = append(args, record.Name, record.ReleaseDate, record.Area)
+	}
+
+	existing, err := r.queryCatalogRecords(ctx, fmt.Sprintf("(video_name, release_date, area) IN (%s)", strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range existing {
+		result[record.IdentityKey()] = record
+	}
+	return result, nil
+}
+
+func (r *VideoRepositoryImpl) SaveCatalogRecords(ctx context.Context, records []*entity.CatalogRecord, uploaderID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	for _, record := range records {
+		if record.VideoID == 0 {
+			query := `INSERT INTO anime_videos (video_name, release_date, area, description, cover_image_url, uploader_id) VALUES (?, ?, ?, ?, ?, ?)`
+			result, err := tx.ExecContext(ctx, query, record.Name, record.ReleaseDate, record.Area, record.Description, record.CoverImageUrl, uploaderID)
+			if err != nil {
+				return fmt.Errorf("创建视频%s失败: %w", record.Name, err)
+			}
+			videoID, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+			record.VideoID = int(videoID)
+		} else {
+			query := `UPDATE anime_videos SET video_name = ?, release_date = ?, area = ?, description = ?, cover_image_url = ? WHERE video_id = ?`
+			if _, err := tx.ExecContext(ctx, query, record.Name, record.ReleaseDate, record.Area, record.Description, record.CoverImageUrl, record.VideoID); err != nil {
+				return fmt.Errorf("更新视频%d失败: %w", record.VideoID, err)
+			}
+			// 未提供类型列表时保留已有类型
+			if record.Genres == nil {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM anime_genres WHERE anime_id = ?`, record.VideoID); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		if err := insertAnimeGenres(ctx, tx, record.VideoID, record.Genres); err != nil {
+			return fmt.Errorf("保存视频%d类型失败: %w", record.VideoID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *VideoRepositoryImpl) ListCatalogRecords(ctx context.Context, afterID, limit int) ([]*entity.CatalogRecord, error) {
+	return r.queryCatalogRecords(ctx, "video_id > ? ORDER BY video_id ASC LIMIT ?", afterID, limit)
+}
+
+// queryCatalogRecords 按条件查询目录记录并补全类型列表
+func (r *VideoRepositoryImpl) queryCatalogRecords(ctx context.Context, condition string, args ...interface{}) ([]*entity.CatalogRecord, error) {
+	query := `SELECT video_id, video_name, release_date, area, description, cover_image_url FROM anime_videos WHERE ` + condition
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make([]*entity.CatalogRecord, 0)
+	recordMap := make(map[int]*entity.CatalogRecord)
+	for rows.Next() {
+		var record entity.CatalogRecord
+		var description sql.NullString
+		if err := rows.Scan(&record.VideoID, &record.Name, &record.ReleaseDate, &record.Area, &description, &record.CoverImageUrl); err != nil {
+			return nil, err
+		}
+		record.Description = description.String
+		record.Genres = make([]string, 0)
+		records = append(records, &record)
+		recordMap[record.VideoID] = &record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	placeholders := make([]string, len(records))
+	genreArgs := make([]interface{}, len(records))
+	for i, record := range records {
+		placeholders[i] = "?"
+		genreArgs[i] = record.VideoID
+	}
+	genreQuery := fmt.Sprintf(`SELECT anime_id, genre FROM anime_genres WHERE anime_id IN (%s) ORDER BY id ASC`, strings.Join(placeholders, ","))
+	genreRows, err := r.db.QueryContext(ctx, genreQuery, genreArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer genreRows.Close()
+
+	for genreRows.Next() {
+		var videoID int
+		var genre string
+		if err := genreRows.Scan(&videoID, &genre); err != nil {
+			return nil, err
+		}
+		if record, ok := recordMap[videoID]; ok {
+			record.Genres = append(record.Genres, genre)
+		}
+	}
+	return records, genreRows.Err()
+}
+
+func (r *VideoRepositoryImpl) InvalidateCatalogCache(ctx context.Context, videoID int) error {
+	if err := r.rdb.Del(ctx, videoFiltersCacheKey, topAnimeGenresCacheKey).Err(); err != nil {
+		return err
+	}
+	if videoID == 0 {
+		return nil
+	}
+
+	// 视频URL缓存键格式为 VideoURL:Video{视频ID}:Episode{集数}
+	pattern := fmt.Sprintf("VideoURL:Video%d:Episode*", videoID)
+	iter := r.rdb.Scan(ctx, 0, pattern, 100).Iterator()
+	keys := make([]string, 0)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.rdb.Del(ctx, keys...).Err()
 }
 
-func (r *VideoRepositoryImpl) GetVideoURL(ctx context.Context, key string) (string, error) {
-	result, err := r.rdb.Get(ctx, key).Result()
+func (r *VideoRepositoryImpl) ListSearchDocuments(ctx context.Context, afterID, limit int) ([]*entity.VideoSearchDocument, error) {
+	records, err := r.ListCatalogRecords(ctx, afterID, limit)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return r.buildSearchDocuments(ctx, records)
+}
 
+func (r *VideoRepositoryImpl) GetSearchDocumentsByIDs(ctx context.Context, videoIDs []int) (map[int]*entity.VideoSearchDocument, error) {
+	recordMap, err := r.GetCatalogRecordsByIDs(ctx, videoIDs)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*entity.CatalogRecord, 0, len(recordMap))
+	for _, record := range recordMap {
+		records = append(records, record)
+	}
+
+	docs, err := r.buildSearchDocuments(ctx, records)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int]*entity.VideoSearchDocument, len(docs))
+	for _, doc := range docs {
+		result[doc.VideoID] = doc
+	}
 	return result, nil
 }
 
+// buildSearchDocuments 由目录记录生成检索文档并补全别名列表
+func (r *VideoRepositoryImpl) buildSearchDocuments(ctx context.Context, records []*entity.CatalogRecord) ([]*entity.VideoSearchDocument, error) {
+	docs := make([]*entity.VideoSearchDocument, 0, len(records))
+	if len(records) == 0 {
+		return docs, nil
+	}
+
+	docMap := make(map[int]*entity.VideoSearchDocument, len(records))
+	placeholders := make([]string, len(records))
+	args := make([]interface{}, len(records))
+	for i, record := range records {
+		doc := &entity.VideoSearchDocument{
+			VideoID:       record.VideoID,
+			Name:          record.Name,
+			Aliases:       make([]string, 0),
+			Genres:        record.Genres,
+			Description:   record.Description,
+			CoverImageUrl: record.CoverImageUrl,
+		}
+		docs = append(docs, doc)
+		docMap[doc.VideoID] = doc
+		placeholders[i] = "?"
+		args[i] = record.VideoID
+	}
+
+	query := fmt.Sprintf(`SELECT anime_id, alias FROM anime_aliases WHERE anime_id IN (%s) ORDER BY id ASC`, strings.Join(placeholders, ","))
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var videoID int
+		var alias string
+		if err := rows.Scan(&videoID, &alias); err != nil {
+			return nil, err
+		}
+		if doc, ok := docMap[videoID]; ok {
+			doc.Aliases = append(doc.Aliases, alias)
+		}
+	}
+	return docs, rows.Err()
+}
+
+func (r *VideoRepositoryImpl) GetVideosWithEpisodesByIDs(ctx context.Context, videoIDs []int) ([]*entity.Video, error) {
+	videos := make([]*entity.Video, 0, len(videoIDs))
+	if len(videoIDs) == 0 {
+		return videos, nil
+	}
+
+	placeholders := make([]string, len(videoIDs))
+	args := make([]interface{}, len(videoIDs))
+	for i, videoID := range videoIDs {
+		placeholders[i] = "?"
+		args[i] = videoID
+	}
+
+	query := fmt.Sprintf(`
+		SELECT 
+			v.video_id,
+			v.video_name,
+			v.cover_image_url,
+			v.release_date,
+			v.area,
+			v.description,
+			GROUP_CONCAT(DISTINCT g.genre) AS genres,
+			GROUP_CONCAT(DISTINCT u.episode ORDER BY u.id ASC) AS episodes
+		FROM anime_videos v
+		LEFT JOIN anime_genres g ON v.video_id = g.anime_id 
+		LEFT JOIN video_urls u ON v.video_id = u.video_id
+		WHERE v.video_id IN (%s)
+		GROUP BY 
+			v.video_id,
+			v.video_name,
+			v.cover_image_url,
+			v.release_date,
+			v.area,
+			v.description
+	`, strings.Join(placeholders, ","))
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	videoMap := make(map[int]*entity.Video, len(videoIDs))
+	for rows.Next() {
+		var description, genres, episodes sql.NullString
+		var video entity.Video
+		err := rows.Scan(&video.ID, &video.Name, &video.CoverImageUrl, &video.ReleaseDate, &video.Area, &description, &genres, &episodes)
+		if err != nil {
+			return nil, err
+		}
+		video.Description = description.String
+		video.Genres = genres.String
+		video.Episodes = make([]string, 0)
+		if episodes.Valid && episodes.String != "" {
+			video.Episodes = strings.Split(episodes.String, ",")
+		}
+		videoMap[video.ID] = &video
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, videoID := range videoIDs {
+		if video, ok := videoMap[videoID]; ok {
+			videos = append(videos, video)
+		}
+	}
+	return videos, nil
+}
+
+func (r *VideoRepositoryImpl) ListVideoSuggestions(ctx context.Context, afterID, limit int) ([]*entity.VideoSuggestion, error) {
+	query := `SELECT video_id, video_name, cover_image_url, IFNULL(views, 0), IFNULL(likes, 0) FROM anime_videos WHERE video_id > ? ORDER BY video_id ASC LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := make([]*entity.VideoSuggestion, 0)
+	for rows.Next() {
+		var video entity.VideoSuggestion
+		if err := rows.Scan(&video.VideoID, &video.Name, &video.CoverImageUrl, &video.Views, &video.Likes); err != nil {
+			return nil, err
+		}
+		videos = append(videos, &video)
+	}
+	return videos, rows.Err()
+}
+
 func (r *VideoRepositoryImpl) AddAnimeCollection(ctx context.Context, collection *entity.UserAnimeCollection) error {
 	query := `INSERT INTO user_anime_collections (user_id, video_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE status = 1`
 	_, err := r.db.ExecContext(ctx, query, collection.UserID, collection.VideoID)
@@ -21,11 +21,12 @@ func NewCookieManager(config *config.CookieConfig) *CookieManager {
 
 func DefaultCookieConfig() *config.CookieConfig {
 	return &config.CookieConfig{
-		Domain:   "localhost",
-		Path:     "/",
-		MaxAge:   3600,
-		Secure:   false,
-		HTTPOnly: true,
+		Domain:        "localhost",
+		Path:          "/",
+		MaxAge:        3600,
+		RefreshMaxAge: 7 * 24 * 3600,
+		Secure:        false,
+		HTTPOnly:      true,
 	}
 }
 
@@ -60,3 +61,58 @@ func (m *CookieManager) GetTokenCookie(c *gin.Context) (string, error) {
 	}
 	return token, nil
 }
+
+func (m *CookieManager) SetRefreshTokenCookie(c *gin.Context, token string) {
+	c.SetCookie(
+		"refresh_token",
+		token,
+		m.config.RefreshMaxAge,
+		m.config.Path,
+		m.config.Domain,
+		m.config.Secure,
+		true, // 刷新令牌始终禁止JavaScript访问
+	)
+}
+
+func (m *CookieManager) ClearRefreshTokenCookie(c *gin.Context) {
+	c.SetCookie(
+		"refresh_token",
+		"",
+		-1,
+		m.config.Path,
+		m.config.Domain,
+		m.config.Secure,
+		true,
+	)
+}
+
+func (m *CookieManager) GetRefreshTokenCookie(c *gin.Context) (string, error) {
+	token, err := c.Cookie("refresh_token")
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// 登录设备Cookie有效期,与服务端保存的设备记录有效期一致
+const deviceCookieMaxAge = 30 * 24 * 3600
+
+func (m *CookieManager) SetDeviceCookie(c *gin.Context, token string) {
+	c.SetCookie(
+		"login_device",
+		token,
+		deviceCookieMaxAge,
+		m.config.Path,
+		m.config.Domain,
+		m.config.Secure,
+		true,
+	)
+}
+
+func (m *CookieManager) GetDeviceCookie(c *gin.Context) (string, error) {
+	token, err := c.Cookie("login_device")
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -16,6 +17,15 @@ var (
 	ErrTokenExpired     = errors.New("token已过期")
 	ErrInvalidSignature = errors.New("无效的签名")
 	ErrMalformedToken   = errors.New("格式错误的token")
+	ErrNotRefreshToken  = errors.New("不是有效的刷新令牌")
+	ErrNotMfaToken      = errors.New("不是有效的两步验证令牌")
+	ErrNotAccessToken   = errors.New("不是有效的访问令牌")
+)
+
+// 非访问令牌的令牌类型,访问令牌的类型为配置中的TokenType
+const (
+	refreshTokenType    = "Refresh"    // 刷新令牌
+	mfaPendingTokenType = "MfaPending" // 两步验证待确认令牌
 )
 
 // JWTManager JWT管理器
@@ -25,11 +35,25 @@ type JWTManager struct {
 
 // CustomClaims 自定义Claims结构体
 type CustomClaims struct {
-	UserInfo  *entity.UserInfo `json:"user_info"`
-	TokenType string           `json:"token_type"`
+	UserInfo   *entity.UserInfo `json:"user_info"`
+	TokenType  string           `json:"token_type"`
+	FamilyID   string           `json:"family_id,omitempty"` // 所属会话(刷新令牌家族)ID,访问令牌和刷新令牌均携带
+	IssuedAtMs int64            `json:"iat_ms,omitempty"`    // 签发时间的毫秒时间戳,标准iat只精确到秒
 	jwt.RegisteredClaims
 }
 
+// IssuedAtTime 返回精确到毫秒的签发时间,用于与用户级吊销时间点比较
+// 用户级吊销后立即签发的令牌与吊销时间点通常在同一秒内,只按秒比较会被误判为已吊销
+func (c *CustomClaims) IssuedAtTime() time.Time {
+	if c.IssuedAtMs > 0 {
+		return time.UnixMilli(c.IssuedAtMs)
+	}
+	if c.IssuedAt != nil {
+		return c.IssuedAt.Time
+	}
+	return time.Time{}
+}
+
 // NewJWTManager 创建JWT管理器实例
 func NewJWTManager(config *config.JWTConfig) *JWTManager {
 	if config == nil {
@@ -49,12 +73,16 @@ func DefaultJWTConfig() *config.JWTConfig {
 }
 
 // GenerateToken 生成JWT令牌
-func (m *JWTManager) GenerateToken(userInfo *entity.UserInfo) (string, error) {
+// familyID 为令牌所属会话ID,用于会话被移除后立即拒绝该会话的访问令牌
+func (m *JWTManager) GenerateToken(userInfo *entity.UserInfo, familyID string) (string, error) {
 	now := time.Now()
 	claims := CustomClaims{
-		UserInfo:  userInfo,           // 用户信息
-		TokenType: m.config.TokenType, // 令牌类型
+		UserInfo:   userInfo,           // 用户信息
+		TokenType:  m.config.TokenType, // 令牌类型
+		FamilyID:   familyID,           // 所属会话ID
+		IssuedAtMs: now.UnixMilli(),    // 签发时间(毫秒)
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),                                          // 令牌唯一ID(jti),用于服务端吊销
 			Issuer:    m.config.Issuer,                                              // 令牌签发者
 			Subject:   fmt.Sprintf("user_%d", userInfo.UserID),                      // 令牌主题,包含用户ID
 			IssuedAt:  jwt.NewNumericDate(now),                                      // 令牌签发时间
@@ -68,12 +96,16 @@ func (m *JWTManager) GenerateToken(userInfo *entity.UserInfo) (string, error) {
 }
 
 // GenerateRefreshToken 生成刷新令牌
-func (m *JWTManager) GenerateRefreshToken(userInfo *entity.UserInfo) (string, error) {
+// familyID 标识同一次登录派生出的刷新令牌家族,tokenID 为本令牌的唯一ID,用于轮换时的重用检测
+func (m *JWTManager) GenerateRefreshToken(userInfo *entity.UserInfo, familyID, tokenID string) (string, error) {
 	now := time.Now()
 	claims := CustomClaims{
-		UserInfo:  userInfo,  // 用户信息
-		TokenType: "Refresh", // 令牌类型为刷新令牌
+		UserInfo:   userInfo,         // 用户信息
+		TokenType:  refreshTokenType, // 令牌类型为刷新令牌
+		FamilyID:   familyID,         // 令牌家族ID
+		IssuedAtMs: now.UnixMilli(),  // 签发时间(毫秒)
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,                                                       // 令牌唯一ID
 			Issuer:    m.config.Issuer,                                               // 令牌签发者
 			Subject:   fmt.Sprintf("refresh_user_%d", userInfo.UserID),               // 令牌主题,包含用户ID
 			IssuedAt:  jwt.NewNumericDate(now),                                       // 令牌签发时间
@@ -86,6 +118,52 @@ func (m *JWTManager) GenerateRefreshToken(userInfo *entity.UserInfo) (string, er
 	return token.SignedString([]byte(m.config.SecretKey))
 }
 
+// GetRefreshTokenExpireTime 获取刷新令牌有效期
+func (m *JWTManager) GetRefreshTokenExpireTime() time.Duration {
+	return m.config.RefreshToken.ExpireTime
+}
+
+// GenerateMfaPendingToken 生成两步验证待确认令牌
+// 密码校验通过但尚未完成两步验证时签发,仅可用于换取正式会话,不能访问其他接口
+func (m *JWTManager) GenerateMfaPendingToken(userInfo *entity.UserInfo) (string, error) {
+	expireTime := m.config.MfaPending.ExpireTime
+	if expireTime == 0 {
+		expireTime = 5 * time.Minute
+	}
+
+	now := time.Now()
+	claims := CustomClaims{
+		UserInfo:   userInfo,            // 用户信息
+		TokenType:  mfaPendingTokenType, // 令牌类型为两步验证待确认令牌
+		IssuedAtMs: now.UnixMilli(),     // 签发时间(毫秒)
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),                         // 令牌唯一ID
+			Issuer:    m.config.Issuer,                             // 令牌签发者
+			Subject:   fmt.Sprintf("mfa_user_%d", userInfo.UserID), // 令牌主题,包含用户ID
+			IssuedAt:  jwt.NewNumericDate(now),                     // 令牌签发时间
+			ExpiresAt: jwt.NewNumericDate(now.Add(expireTime)),     // 令牌过期时间
+			NotBefore: jwt.NewNumericDate(now),                     // 令牌生效时间
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(m.config.SecretKey))
+}
+
+// ParseMfaPendingToken 解析两步验证待确认令牌,并校验令牌类型
+func (m *JWTManager) ParseMfaPendingToken(tokenString string) (*CustomClaims, error) {
+	claims, err := m.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != mfaPendingTokenType {
+		return nil, ErrNotMfaToken
+	}
+
+	return claims, nil
+}
+
 // ParseToken 解析并验证token
 func (m *JWTManager) ParseToken(tokenString string) (*CustomClaims, error) {
 	// 移除Bearer前缀
@@ -115,29 +193,54 @@ func (m *JWTManager) ParseToken(tokenString string) (*CustomClaims, error) {
 	return nil, ErrInvalidToken
 }
 
-// RefreshToken 刷新token
-func (m *JWTManager) RefreshToken(refreshToken string) (string, error) {
+// ParseAccessToken 解析访问令牌
+// 只接受类型为配置中TokenType的令牌,刷新令牌、两步验证待确认令牌等其他类型一律拒绝
+func (m *JWTManager) ParseAccessToken(tokenString string) (*CustomClaims, error) {
+	claims, err := m.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != m.config.TokenType {
+		return nil, ErrNotAccessToken
+	}
+
+	return claims, nil
+}
+
+// ParseRefreshToken 解析刷新令牌,并校验令牌类型
+func (m *JWTManager) ParseRefreshToken(refreshToken string) (*CustomClaims, error) {
 	claims, err := m.ParseToken(refreshToken)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if claims.TokenType != "Refresh" {
-		return "", errors.New("不是有效的刷新令牌")
+	if claims.TokenType != refreshTokenType || claims.FamilyID == "" || claims.ID == "" {
+		return nil, ErrNotRefreshToken
+	}
+
+	return claims, nil
+}
+
+// RefreshToken 刷新token
+func (m *JWTManager) RefreshToken(refreshToken string) (string, error) {
+	claims, err := m.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return "", err
 	}
 
-	return m.GenerateToken(claims.UserInfo)
+	return m.GenerateToken(claims.UserInfo, claims.FamilyID)
 }
 
-// ValidateToken 验证token的有效性
+// ValidateToken 验证访问令牌的有效性
 func (m *JWTManager) ValidateToken(tokenString string) bool {
-	_, err := m.ParseToken(tokenString)
+	_, err := m.ParseAccessToken(tokenString)
 	return err == nil
 }
 
-// GetUserFromToken 从token中获取用户信息
+// GetUserFromToken 从访问令牌中获取用户信息
 func (m *JWTManager) GetUserFromToken(tokenString string) (*entity.UserInfo, error) {
-	claims, err := m.ParseToken(tokenString)
+	claims, err := m.ParseAccessToken(tokenString)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// PersonalAccessTokenPrefix 个人访问令牌前缀,用于与JWT区分并便于密钥扫描工具识别
+const PersonalAccessTokenPrefix = "zat_"
+
+// PersonalAccessTokenType 个人访问令牌认证时写入CustomClaims的令牌类型
+const PersonalAccessTokenType = "PersonalAccessToken"
+
+// personalAccessTokenDisplayLen 列表中用于识别令牌的前缀长度(含zat_)
+const personalAccessTokenDisplayLen = 12
+
+// GeneratePersonalAccessToken 生成个人访问令牌
+// 返回:
+// - token: 令牌明文,仅在创建时返回给用户一次
+// - displayPrefix: 令牌前缀,用于在列表中识别令牌
+// - tokenHash: 令牌哈希,用于存储和查找
+// - err: 错误信息
+func GeneratePersonalAccessToken() (token, displayPrefix, tokenHash string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+	token = PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, token[:personalAccessTokenDisplayLen], HashPersonalAccessToken(token), nil
+}
+
+// HashPersonalAccessToken 计算个人访问令牌的SHA-256哈希
+// 令牌为256位随机值,无需加盐和慢哈希
+func HashPersonalAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsPersonalAccessToken 判断令牌是否为个人访问令牌格式
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"gateService/internal/domain/entity"
+	"gateService/pkg/fulltext"
+	"sync/atomic"
+	"time"
+)
+
+// 每个前缀保存的最大提示数
+const MaxSuggestions = 20
+
+// SuggestIndex 视频名称输入提示索引
+// 职责：
+//   - 支持名称原文、拼音全拼和首字母前缀匹配,结果按热度排序
+//   - 定期由MySQL全量重建,重建后整体替换,查询无需加锁
+type SuggestIndex struct {
+	snapshot atomic.Pointer[suggestSnapshot]
+}
+
+// suggestSnapshot 一次构建的只读结果
+type suggestSnapshot struct {
+	suggester *fulltext.Suggester
+	videos    map[int]*entity.VideoSuggestion
+	builtAt   time.Time
+}
+
+// NewSuggestIndex 创建空的输入提示索引,需要调用Rebuild完成构建后才可使用
+func NewSuggestIndex() *SuggestIndex {
+	return &SuggestIndex{}
+}
+
+// Rebuild 使用全部视频重建输入提示,热度为播放量与点赞数之和
+func (s *SuggestIndex) Rebuild(videos []*entity.VideoSuggestion) {
+	items := make([]fulltext.SuggestItem, 0, len(videos))
+	videoMap := make(map[int]*entity.VideoSuggestion, len(videos))
+	for _, video := range videos {
+		items = append(items, fulltext.SuggestItem{
+			ID:    video.VideoID,
+			Text:  video.Name,
+			Score: float64(video.Views + video.Likes),
+		})
+		videoMap[video.VideoID] = video
+	}
+
+	s.snapshot.Store(&suggestSnapshot{
+		suggester: fulltext.NewSuggester(items, MaxSuggestions),
+		videos:    videoMap,
+		builtAt:   time.Now(),
+	})
+}
+
+// BuiltAt 返回最近一次构建的时间,尚未构建时返回零值
+func (s *SuggestIndex) BuiltAt() time.Time {
+	if snapshot := s.snapshot.Load(); snapshot != nil {
+		return snapshot.builtAt
+	}
+	return time.Time{}
+}
+
+// Suggest 返回名称以给定文本开头的视频,按热度从高到低排列
+// 参数:
+// - prefix: 用户输入的文本,可以是名称原文、拼音全拼或首字母
+// - n: 返回的最大数量,不超过MaxSuggestions
+// 返回:
+// - []*entity.VideoSuggestion: 匹配的视频,尚未构建时返回空列表
+func (s *SuggestIndex) Suggest(prefix string, n int) []*entity.VideoSuggestion {
+	snapshot := s.snapshot.Load()
+	if snapshot == nil {
+		return []*entity.VideoSuggestion{}
+	}
+
+	ids := snapshot.suggester.Suggest(prefix, n)
+	videos := make([]*entity.VideoSuggestion, 0, len(ids))
+	for _, id := range ids {
+		videos = append(videos, snapshot.videos[id])
+	}
+	return videos
+}
@@ -0,0 +1,116 @@
+package search
+
+import (
+	"gateService/internal/domain/entity"
+	"gateService/pkg/fulltext"
+	"sync"
+	"sync/atomic"
+)
+
+// 检索字段及权重,名称和别名命中的视频排在类型、简介命中之前
+var videoIndexFields = []fulltext.Field{
+	{Name: "name", Weight: 3},
+	{Name: "aliases", Weight: 2.5},
+	{Name: "genres", Weight: 1.5},
+	{Name: "description", Weight: 1},
+}
+
+// VideoIndex 视频全文检索索引
+// 职责：
+//   - 启动时由MySQL全量构建,之后根据目录变更消息增量更新
+//   - 保存检索文档,搜索结果无需再次查询数据库即可返回基本信息
+type VideoIndex struct {
+	index *fulltext.Index
+
+	mu    sync.RWMutex
+	docs  map[int]*entity.VideoSearchDocument
+	ready atomic.Bool // 是否已完成首次全量构建
+}
+
+// NewVideoIndex 创建空的视频检索索引,需要调用Rebuild完成构建后才可使用
+func NewVideoIndex() *VideoIndex {
+	return &VideoIndex{
+		index: fulltext.NewIndex(&fulltext.Options{Fields: videoIndexFields}),
+		docs:  make(map[int]*entity.VideoSearchDocument),
+	}
+}
+
+// Rebuild 使用全部检索文档重建索引,构建期间检索仍使用旧索引
+func (v *VideoIndex) Rebuild(docs []*entity.VideoSearchDocument) {
+	indexDocs := make([]*fulltext.Document, 0, len(docs))
+	docMap := make(map[int]*entity.VideoSearchDocument, len(docs))
+	for _, doc := range docs {
+		indexDocs = append(indexDocs, toIndexDocument(doc))
+		docMap[doc.VideoID] = doc
+	}
+	index := fulltext.NewIndex(&fulltext.Options{Fields: videoIndexFields})
+	index.Reset(indexDocs)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.index = index
+	v.docs = docMap
+	v.ready.Store(true)
+}
+
+// Put 添加或替换一部视频
+func (v *VideoIndex) Put(doc *entity.VideoSearchDocument) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.index.Put(toIndexDocument(doc))
+	v.docs[doc.VideoID] = doc
+}
+
+// Delete 从索引中移除一部视频
+func (v *VideoIndex) Delete(videoID int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.index.Delete(videoID)
+	delete(v.docs, videoID)
+}
+
+// Ready 索引是否已完成首次全量构建
+func (v *VideoIndex) Ready() bool {
+	return v.ready.Load()
+}
+
+// Len 返回索引中的视频数量
+func (v *VideoIndex) Len() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return len(v.docs)
+}
+
+// Search 按相关度检索视频
+// 参数:
+// - query: 查询文本
+// - offset: 跳过的结果数
+// - limit: 返回的最大结果数
+// 返回:
+// - []*entity.VideoSearchDocument: 当前页的视频,按相关度从高到低排序
+// - int: 命中的视频总数
+func (v *VideoIndex) Search(query string, offset, limit int) ([]*entity.VideoSearchDocument, int) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	hits, total := v.index.Search(query, offset, limit)
+	docs := make([]*entity.VideoSearchDocument, 0, len(hits))
+	for _, hit := range hits {
+		if doc, ok := v.docs[hit.ID]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, total
+}
+
+func toIndexDocument(doc *entity.VideoSearchDocument) *fulltext.Document {
+	return &fulltext.Document{
+		ID: doc.VideoID,
+		Fields: map[string][]string{
+			"name":        {doc.Name},
+			"aliases":     doc.Aliases,
+			"genres":      doc.Genres,
+			"description": {doc.Description},
+		},
+	}
+}
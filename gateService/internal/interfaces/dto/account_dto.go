@@ -0,0 +1,52 @@
+package dto
+
+import "gateService/internal/domain/entity"
+
+// RequestDataExportRequest 发起用户数据导出请求参数
+type RequestDataExportRequest struct {
+	UserID int `form:"user_id"` // 用户ID
+}
+
+// RequestDataExportResponse 发起用户数据导出响应
+type RequestDataExportResponse struct {
+	Code    int                   `json:"code"`    // 响应状态码,200表示成功
+	Message string                `json:"message"` // 响应消息
+	Job     *entity.DataExportJob `json:"job"`     // 导出任务
+}
+
+// GetDataExportRequest 查询用户数据导出任务请求参数
+type GetDataExportRequest struct {
+	UserID int    `form:"user_id"`                   // 用户ID
+	JobID  string `form:"job_id" binding:"required"` // 导出任务ID,必填
+}
+
+// GetDataExportResponse 查询用户数据导出任务响应
+type GetDataExportResponse struct {
+	Code int                   `json:"code"` // 响应状态码,200表示成功
+	Job  *entity.DataExportJob `json:"job"`  // 导出任务
+}
+
+// DownloadDataExportRequest 下载用户数据导出文件请求参数
+type DownloadDataExportRequest struct {
+	UserID int    `form:"user_id"`                   // 用户ID
+	JobID  string `form:"job_id" binding:"required"` // 导出任务ID,必填
+}
+
+// DownloadDataExportResponse 下载用户数据导出文件响应,由处理器以附件形式返回文件
+type DownloadDataExportResponse struct {
+	FilePath string // 导出文件在服务端的路径
+	FileName string // 下载时使用的文件名
+}
+
+// DeleteAccountRequest 注销账号请求参数
+type DeleteAccountRequest struct {
+	UserID   int    `form:"user_id"`                     // 用户ID
+	Password string `form:"password" binding:"required"` // 当前密码,必填
+	Code     string `form:"code"`                        // 动态验证码或恢复码,开启两步验证时必填
+}
+
+// DeleteAccountResponse 注销账号响应
+type DeleteAccountResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "gateService/internal/domain/entity"
+
+// BlockRequest 拉黑/静音或取消拉黑/静音用户请求参数
+type BlockRequest struct {
+	UserID       int `form:"user_id"`                                 // 当前用户ID
+	TargetUserID int `form:"target_user_id" binding:"required,min=1"` // 目标用户ID,必填
+}
+
+// BlockResponse 拉黑/静音或取消拉黑/静音用户响应
+type BlockResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+}
+
+// BlockListRequest 获取拉黑列表或静音列表请求参数
+type BlockListRequest struct {
+	UserID   int `form:"user_id"`                                    // 当前用户ID
+	Page     int `form:"page" binding:"required,min=1"`              // 页码,必须大于等于1
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=50"` // 每页数量,限制范围1-50
+}
+
+// BlockListResponse 获取拉黑列表或静音列表响应
+type BlockListResponse struct {
+	Code  int                   `json:"code"`  // 响应状态码,200表示成功
+	Users []*entity.BlockedUser `json:"users"` // 用户列表,按屏蔽时间倒序
+}
@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"gateService/internal/domain/entity"
+	"mime/multipart"
+)
+
+// CatalogVideoListRequest 管理后台按名称搜索视频的请求参数
+type CatalogVideoListRequest struct {
+	Keyword string `form:"keyword"`                       // 视频名称关键字,为空时列出全部视频
+	Page    int    `form:"page" binding:"required,min=1"` // 页码,每页10条
+}
+
+// CatalogVideoListResponse 管理后台视频列表响应
+type CatalogVideoListResponse struct {
+	Code   int             `json:"code"`   // 响应状态码,200表示成功
+	Videos []*entity.Video `json:"videos"` // 视频列表,包含类型和剧集
+}
+
+// CatalogVideoRequest 管理后台指定视频的请求参数
+type CatalogVideoRequest struct {
+	VideoID int `form:"video_id" json:"video_id" binding:"required,min=1"` // 视频ID,必填
+}
+
+// CatalogVideoDetailResponse 管理后台视频详情响应
+type CatalogVideoDetailResponse struct {
+	Code     int                    `json:"code"`     // 响应状态码,200表示成功
+	Video    *entity.Video          `json:"video"`    // 视频基本信息
+	Genres   []string               `json:"genres"`   // 视频类型列表
+	Aliases  []string               `json:"aliases"`  // 视频别名列表
+	Episodes []*entity.VideoEpisode `json:"episodes"` // 剧集列表,按添加顺序排列
+}
+
+// CatalogVideoSaveRequest 管理后台创建或更新视频的请求参数
+type CatalogVideoSaveRequest struct {
+	UserID        int      `json:"user_id"`                                                // 当前管理员ID,创建时作为上传者
+	VideoID       int      `json:"video_id"`                                               // 视频ID,更新时必填
+	Name          string   `json:"video_name" binding:"required,max=255"`                  // 视频名称,必填
+	ReleaseDate   string   `json:"release_date" binding:"required,max=255"`                // 上映时间,必填,未知时填写"未知"
+	Area          string   `json:"area" binding:"required,max=255"`                        // 地区,必填
+	Description   string   `json:"description" binding:"max=5000"`                         // 简介
+	CoverImageUrl string   `json:"cover_image_url" binding:"required,max=255"`             // 封面图片URL,必填
+	Genres        []string `json:"genres" binding:"omitempty,max=20,dive,required,max=50"` // 类型列表,创建时至少一个,更新时不为空则替换
+}
+
+// CatalogVideoSaveResponse 管理后台创建或更新视频的响应
+type CatalogVideoSaveResponse struct {
+	Code    int    `json:"code"`     // 响应状态码,200表示成功
+	Message string `json:"message"`  // 响应消息
+	VideoID int    `json:"video_id"` // 视频ID
+}
+
+// CatalogGenresRequest 管理后台替换视频类型的请求参数
+type CatalogGenresRequest struct {
+	VideoID int      `json:"video_id" binding:"required,min=1"`                           // 视频ID,必填
+	Genres  []string `json:"genres" binding:"required,min=1,max=20,dive,required,max=50"` // 新的类型列表,至少一个
+}
+
+// CatalogAliasesRequest 管理后台替换视频别名的请求参数
+type CatalogAliasesRequest struct {
+	VideoID int      `json:"video_id" binding:"required,min=1"`              // 视频ID,必填
+	Aliases []string `json:"aliases" binding:"max=20,dive,required,max=255"` // 新的别名列表,为空时清除全部别名
+}
+
+// CatalogEpisode 管理后台提交的一集
+type CatalogEpisode struct {
+	Episode  string `json:"episode" binding:"required,max=50"`         // 集数,必填
+	VideoURL string `json:"video_url" binding:"omitempty,max=500,url"` // 播放地址,为空时播放时再爬取
+}
+
+// CatalogEpisodesRequest 管理后台替换视频剧集列表的请求参数
+type CatalogEpisodesRequest struct {
+	VideoID  int               `json:"video_id" binding:"required,min=1"`               // 视频ID,必填
+	Episodes []*CatalogEpisode `json:"episodes" binding:"required,min=1,max=2000,dive"` // 新的剧集列表,按播放顺序排列,至少一集
+}
+
+// CatalogEpisodeURLRequest 管理后台查看一集播放地址的请求参数
+type CatalogEpisodeURLRequest struct {
+	VideoID int    `form:"video_id" binding:"required,min=1"` // 视频ID,必填
+	Episode string `form:"episode" binding:"required,max=50"` // 集数,必填
+}
+
+// CatalogEpisodeURLResponse 管理后台查看一集播放地址的响应
+type CatalogEpisodeURLResponse struct {
+	Code    int                        `json:"code"`    // 响应状态码,200表示成功
+	Stored  *entity.StoredVideoSources `json:"stored"`  // 数据库中保存的管理员指定地址和最近一次爬取结果
+	Expired bool                       `json:"expired"` // 数据库中的爬取结果是否已过期
+	Cached  []*entity.VideoSource      `json:"cached"`  // Redis中缓存的播放源,即当前返回给用户的播放源
+}
+
+// CatalogEpisodeURLUpdateRequest 管理后台指定一集播放地址的请求参数
+type CatalogEpisodeURLUpdateRequest struct {
+	VideoID  int    `json:"video_id" binding:"required,min=1"`         // 视频ID,必填
+	Episode  string `json:"episode" binding:"required,max=50"`         // 集数,必填
+	VideoURL string `json:"video_url" binding:"omitempty,max=500,url"` // 播放地址,为空时取消指定,播放时使用爬取结果
+}
+
+// CatalogResponse 管理后台目录修改操作的通用响应
+type CatalogResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+}
+
+// CatalogImportRequest 批量导入视频目录的请求参数
+type CatalogImportRequest struct {
+	UserID   int                   `form:"user_id"`                                   // 当前管理员ID,作为新建视频的上传者
+	Format   string                `form:"format" binding:"omitempty,oneof=csv json"` // 文件格式,不传时根据文件扩展名判断
+	DryRun   bool                  `form:"dry_run"`                                   // 是否试运行,试运行只返回差异报告不写入数据库
+	File     *multipart.FileHeader `form:"file"`                                      // 上传的导入文件,使用multipart/form-data格式上传
+	FilePath string                `form:"-"`                                         // 本地导入文件路径,仅命令行导入时使用
+}
+
+// CatalogImportResponse 批量导入视频目录的响应
+type CatalogImportResponse struct {
+	Code    int                         `json:"code"`    // 响应状态码,200表示成功
+	Message string                      `json:"message"` // 响应消息
+	Report  *entity.CatalogImportReport `json:"report"`  // 差异报告
+}
+
+// CatalogExportRequest 导出视频目录的请求参数
+type CatalogExportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv json"` // 文件格式,默认json
+}
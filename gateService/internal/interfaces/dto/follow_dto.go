@@ -0,0 +1,30 @@
+package dto
+
+import "gateService/internal/domain/entity"
+
+// FollowRequest 关注或取消关注用户请求参数
+type FollowRequest struct {
+	UserID     int `form:"user_id"`                              // 当前用户ID
+	FolloweeID int `form:"followee_id" binding:"required,min=1"` // 被关注的用户ID,必填
+}
+
+// FollowResponse 关注或取消关注用户响应
+type FollowResponse struct {
+	Code      int    `json:"code"`      // 响应状态码,200表示成功
+	Message   string `json:"message"`   // 响应消息
+	Following bool   `json:"following"` // 操作后是否处于关注状态
+}
+
+// FollowListRequest 获取粉丝列表或关注列表请求参数
+type FollowListRequest struct {
+	UserID       int `form:"user_id"`                                    // 当前用户ID
+	TargetUserID int `form:"target_user_id"`                             // 查询的用户ID,不传时查询当前用户
+	Page         int `form:"page" binding:"required,min=1"`              // 页码,必须大于等于1
+	PageSize     int `form:"page_size" binding:"omitempty,min=1,max=50"` // 每页数量,限制范围1-50
+}
+
+// FollowListResponse 获取粉丝列表或关注列表响应
+type FollowListResponse struct {
+	Code  int                  `json:"code"`  // 响应状态码,200表示成功
+	Users []*entity.FollowUser `json:"users"` // 用户列表,按关注时间倒序
+}
@@ -0,0 +1,50 @@
+package dto
+
+// GetMfaStatusRequest 获取两步验证状态请求参数
+type GetMfaStatusRequest struct {
+	UserID int `form:"user_id"` // 用户ID
+}
+
+// GetMfaStatusResponse 获取两步验证状态响应
+type GetMfaStatusResponse struct {
+	Code    int  `json:"code"`    // 响应状态码,200表示成功
+	Enabled bool `json:"enabled"` // 是否已开启两步验证
+}
+
+// SetupMfaRequest 开始绑定两步验证请求参数
+type SetupMfaRequest struct {
+	UserID int `form:"user_id"` // 用户ID
+}
+
+// SetupMfaResponse 开始绑定两步验证响应
+type SetupMfaResponse struct {
+	Code            int    `json:"code"`             // 响应状态码,200表示成功
+	Secret          string `json:"secret"`           // TOTP密钥,供无法扫码时手动输入
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth://链接,用于生成二维码
+}
+
+// ConfirmMfaRequest 确认绑定两步验证请求参数
+type ConfirmMfaRequest struct {
+	UserID int    `form:"user_id"`                               // 用户ID
+	Code   string `form:"code" binding:"required,len=6,numeric"` // 验证器应用中的6位动态验证码,必填
+}
+
+// ConfirmMfaResponse 确认绑定两步验证响应
+type ConfirmMfaResponse struct {
+	Code          int      `json:"code"`           // 响应状态码,200表示成功
+	Message       string   `json:"message"`        // 响应消息
+	RecoveryCodes []string `json:"recovery_codes"` // 一次性恢复码,仅在此时返回一次
+}
+
+// DisableMfaRequest 关闭两步验证请求参数
+type DisableMfaRequest struct {
+	UserID   int    `form:"user_id"`                     // 用户ID
+	Password string `form:"password" binding:"required"` // 当前密码,必填
+	Code     string `form:"code" binding:"required"`     // 6位动态验证码或恢复码,必填
+}
+
+// DisableMfaResponse 关闭两步验证响应
+type DisableMfaResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+}
@@ -0,0 +1,48 @@
+package dto
+
+// OAuthProvidersResponse 获取已启用的第三方登录方式响应
+type OAuthProvidersResponse struct {
+	Code      int      `json:"code"`      // 响应状态码,200表示成功
+	Providers []string `json:"providers"` // 身份提供方名称列表
+}
+
+// OAuthLoginRequest 发起第三方登录请求参数
+type OAuthLoginRequest struct {
+	Provider string `form:"-"`        // 身份提供方名称,取自路径参数
+	Redirect string `form:"redirect"` // 登录完成后跳转的前端路径,仅允许站内相对路径
+}
+
+// OAuthLoginResponse 发起第三方登录响应
+type OAuthLoginResponse struct {
+	Code    int    `json:"code"`     // 响应状态码,200表示成功
+	AuthURL string `json:"auth_url"` // 身份提供方授权地址
+}
+
+// OAuthCallbackRequest 第三方登录回调请求参数
+type OAuthCallbackRequest struct {
+	Provider         string `form:"-"`                 // 身份提供方名称,取自路径参数
+	Code             string `form:"code"`              // 授权码
+	State            string `form:"state"`             // 发起登录时生成的state
+	Error            string `form:"error"`             // 身份提供方返回的错误码
+	ErrorDescription string `form:"error_description"` // 身份提供方返回的错误描述
+}
+
+// OAuthLinkConfirmRequest 确认绑定第三方身份请求参数
+type OAuthLinkConfirmRequest struct {
+	LinkToken string `json:"link_token" binding:"required"` // 回调跳转地址中的确认令牌
+	Password  string `json:"password" binding:"required"`   // 本地账号密码
+}
+
+// OAuthLinkConfirmResponse 确认绑定第三方身份响应
+type OAuthLinkConfirmResponse struct {
+	Code        int    `json:"code"`                // 响应状态码,200表示成功
+	Message     string `json:"message"`             // 响应消息
+	MfaToken    string `json:"mfa_token,omitempty"` // 账号开启两步验证时返回的待确认令牌
+	RedirectURL string `json:"redirect_url"`        // 登录完成后跳转的前端地址
+}
+
+// OAuthCallbackResponse 第三方登录回调响应
+type OAuthCallbackResponse struct {
+	Code        int    `json:"code"`         // 响应状态码,200表示成功
+	RedirectURL string `json:"redirect_url"` // 登录完成后跳转的前端地址
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "gateService/internal/domain/entity"
+
+// CreatePersonalAccessTokenRequest 创建个人访问令牌请求参数
+type CreatePersonalAccessTokenRequest struct {
+	UserID        int      `form:"user_id"`                                           // 用户ID
+	Name          string   `form:"name" binding:"required,max=64"`                    // 令牌名称,必填
+	Scopes        []string `form:"scopes" binding:"required,min=1"`                   // 授权范围,必填,可重复传参
+	ExpiresInDays int      `form:"expires_in_days" binding:"omitempty,min=0,max=365"` // 有效天数,0或不传表示永不过期
+}
+
+// CreatePersonalAccessTokenResponse 创建个人访问令牌响应
+type CreatePersonalAccessTokenResponse struct {
+	Code                int                         `json:"code"`                  // 响应状态码,200表示成功
+	Message             string                      `json:"message"`               // 响应消息
+	Token               string                      `json:"token"`                 // 令牌明文,仅返回这一次
+	PersonalAccessToken *entity.PersonalAccessToken `json:"personal_access_token"` // 令牌信息
+}
+
+// ListPersonalAccessTokensRequest 获取个人访问令牌列表请求参数
+type ListPersonalAccessTokensRequest struct {
+	UserID int `form:"user_id"` // 用户ID
+}
+
+// ListPersonalAccessTokensResponse 获取个人访问令牌列表响应
+type ListPersonalAccessTokensResponse struct {
+	Code            int                           `json:"code"`             // 响应状态码,200表示成功
+	Tokens          []*entity.PersonalAccessToken `json:"tokens"`           // 令牌列表,按创建时间倒序
+	AvailableScopes []string                      `json:"available_scopes"` // 可申请的全部授权范围
+}
+
+// RevokePersonalAccessTokenRequest 吊销个人访问令牌请求参数
+type RevokePersonalAccessTokenRequest struct {
+	UserID  int   `form:"user_id"`                     // 用户ID
+	TokenID int64 `form:"token_id" binding:"required"` // 需要吊销的令牌ID,必填
+}
+
+// RevokePersonalAccessTokenResponse 吊销个人访问令牌响应
+type RevokePersonalAccessTokenResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+}
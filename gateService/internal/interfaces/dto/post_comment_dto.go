@@ -31,11 +31,12 @@ type SubmitPostReplyResponse struct {
 
 // GetCommentsRequest 获取评论列表的请求参数
 type GetPostCommentsRequest struct {
-	UserID   int   `form:"user_id"`   // 用户ID
-	ID       int64 `form:"id"`        // 视频/文章ID
-	Page     int   `form:"page"`      // 页码,从1开始
-	PageSize int   `form:"page_size"` // 每页评论数量
-	RootID   int64 `form:"root_id"`   // 根评论ID,用于获取子评论列表
+	UserID    int   `form:"user_id"`    // 用户ID
+	ID        int64 `form:"id"`         // 视频/文章ID
+	Page      int   `form:"page"`       // 页码,从1开始
+	PageSize  int   `form:"page_size"`  // 每页评论数量
+	RootID    int64 `form:"root_id"`    // 根评论ID,用于获取子评论列表
+	HideMuted bool  `form:"hide_muted"` // 是否隐藏当前用户拉黑或静音的用户发表的评论
 }
 
 // GetPostCommentsResponse 获取评论列表的响应
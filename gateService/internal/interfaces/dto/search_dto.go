@@ -1,8 +1,11 @@
 package dto
 
+import "gateService/internal/domain/entity"
+
 // SearchRequest 搜索请求参数
 type SearchRequest struct {
-	Query string `form:"query" binding:"required"` // 搜索关键词，必填
+	UserID int    `form:"user_id"`                  // 用户ID
+	Query  string `form:"query" binding:"required"` // 搜索关键词，必填
 }
 
 // SearchAnime 搜索结果中的动漫基本信息
@@ -43,3 +46,38 @@ type SearchDetailResponse struct {
 	Code   int                  `json:"code"`   // 响应状态码
 	Animes []*SearchDetailAnime `json:"animes"` // 搜索结果动漫详情列表
 }
+
+// SearchSuggestRequest 输入提示请求参数
+type SearchSuggestRequest struct {
+	Query string `form:"query" binding:"required,max=50"`        // 已输入的文本，支持名称、拼音全拼或首字母，必填
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=20"` // 返回数量，默认10
+}
+
+// SearchSuggestResponse 输入提示响应
+type SearchSuggestResponse struct {
+	Code   int            `json:"code"`   // 响应状态码
+	Animes []*SearchAnime `json:"animes"` // 匹配的动漫列表，按热度排序
+}
+
+// SearchTrendingRequest 热门搜索请求参数
+type SearchTrendingRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"` // 返回数量，默认10
+}
+
+// SearchTrendingResponse 热门搜索响应
+type SearchTrendingResponse struct {
+	Code    int                       `json:"code"`    // 响应状态码
+	Queries []*entity.SearchQueryStat `json:"queries"` // 热门搜索词，按近期搜索热度排序
+}
+
+// SearchZeroResultRequest 无结果搜索词报表请求参数
+type SearchZeroResultRequest struct {
+	Page int `form:"page" binding:"required,min=1"` // 页码，每页50条
+}
+
+// SearchZeroResultResponse 无结果搜索词报表响应
+type SearchZeroResultResponse struct {
+	Code    int                       `json:"code"`    // 响应状态码
+	Total   int64                     `json:"total"`   // 无结果搜索词总数
+	Queries []*entity.SearchQueryStat `json:"queries"` // 无结果搜索词，按近期搜索热度排序，用于发现目录中缺失的视频
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "gateService/internal/domain/entity"
+
+// ListSessionsRequest 获取登录会话列表请求参数
+type ListSessionsRequest struct {
+	UserID           int    `form:"user_id"` // 用户ID
+	CurrentSessionID string `form:"-"`       // 当前请求所在的会话ID
+}
+
+// ListSessionsResponse 获取登录会话列表响应
+type ListSessionsResponse struct {
+	Code     int               `json:"code"`     // 响应状态码,200表示成功
+	Sessions []*entity.Session `json:"sessions"` // 会话列表,按最近活跃时间倒序
+}
+
+// RevokeSessionRequest 移除指定登录会话请求参数
+type RevokeSessionRequest struct {
+	UserID           int    `form:"user_id"`                       // 用户ID
+	CurrentSessionID string `form:"-"`                             // 当前请求所在的会话ID
+	SessionID        string `form:"session_id" binding:"required"` // 需要移除的会话ID,必填
+}
+
+// RevokeSessionResponse 移除指定登录会话响应
+type RevokeSessionResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+}
+
+// RevokeOtherSessionsRequest 移除其他全部登录会话请求参数
+type RevokeOtherSessionsRequest struct {
+	UserID           int    `form:"user_id"` // 用户ID
+	CurrentSessionID string `form:"-"`       // 当前请求所在的会话ID,该会话保留
+}
+
+// RevokeOtherSessionsResponse 移除其他全部登录会话响应
+type RevokeOtherSessionsResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+	Revoked int    `json:"revoked"` // 移除的会话数量
+}
@@ -13,15 +13,32 @@ type LoginRequest struct {
 }
 
 // LoginResponse 用户登录响应
+// 开启两步验证的用户密码校验通过后MfaRequired为true,需携带MfaToken调用两步验证登录接口
 type LoginResponse struct {
-	Code     int              `json:"code"`      // 响应状态码,200表示成功
-	UserInfo *entity.UserInfo `json:"user_info"` // 用户信息
+	Code        int              `json:"code"`                // 响应状态码,200表示成功
+	UserInfo    *entity.UserInfo `json:"user_info"`           // 用户信息
+	MfaRequired bool             `json:"mfa_required"`        // 是否需要进行两步验证
+	MfaToken    string           `json:"mfa_token,omitempty"` // 两步验证待确认令牌
+}
+
+// LoginMfaRequest 两步验证登录请求参数
+type LoginMfaRequest struct {
+	MfaToken string `form:"mfa_token" binding:"required"` // 两步验证待确认令牌,必填
+	Code     string `form:"code" binding:"required"`      // 6位动态验证码或恢复码,必填
+	UserIP   string `form:"user_ip"`                      // 用户IP地址
+}
+
+// RefreshTokenResponse 刷新令牌响应
+type RefreshTokenResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
 }
 
 // RegisterRequest 用户注册请求参数
 type RegisterRequest struct {
 	Email    string `form:"email" binding:"required,email"`           // 用户邮箱,必填且需符合邮箱格式
 	Password string `form:"password" binding:"required,min=8,max=32"` // 用户密码,必填且长度在8-32位之间
+	Code     string `form:"code" binding:"required,len=6,numeric"`    // 邮箱验证码,必填且为6位数字
 }
 
 // RegisterResponse 用户注册响应
@@ -30,6 +47,55 @@ type RegisterResponse struct {
 	Message string `json:"message"` // 响应消息
 }
 
+// SendVerificationCodeRequest 发送注册验证码请求参数
+type SendVerificationCodeRequest struct {
+	Email  string `form:"email" binding:"required,email"` // 用户邮箱,必填且需符合邮箱格式
+	UserIP string `form:"user_ip"`                        // 用户IP地址
+}
+
+// SendVerificationCodeResponse 发送注册验证码响应
+type SendVerificationCodeResponse struct {
+	Code     int    `json:"code"`     // 响应状态码,200表示成功
+	Message  string `json:"message"`  // 响应消息
+	Interval int    `json:"interval"` // 再次发送前需等待的秒数
+}
+
+// ForgotPasswordRequest 申请密码重置请求参数
+type ForgotPasswordRequest struct {
+	Email  string `form:"email" binding:"required,email"` // 用户邮箱,必填且需符合邮箱格式
+	UserIP string `form:"user_ip"`                        // 用户IP地址
+}
+
+// ForgotPasswordResponse 申请密码重置响应
+type ForgotPasswordResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+}
+
+// ResetPasswordRequest 重置密码请求参数
+type ResetPasswordRequest struct {
+	Token    string `form:"token" binding:"required"`                 // 邮件中的重置令牌,必填
+	Password string `form:"password" binding:"required,min=8,max=32"` // 新密码,必填且长度在8-32位之间
+}
+
+// ResetPasswordResponse 重置密码响应
+type ResetPasswordResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+}
+
+// UpdateUserRoleRequest 修改用户角色请求参数
+type UpdateUserRoleRequest struct {
+	UserID int    `form:"user_id" binding:"required"`                         // 用户ID,必填
+	Role   string `form:"role" binding:"required,oneof=user moderator admin"` // 新角色,必填
+}
+
+// UpdateUserRoleResponse 修改用户角色响应
+type UpdateUserRoleResponse struct {
+	Code    int    `json:"code"`    // 响应状态码,200表示成功
+	Message string `json:"message"` // 响应消息
+}
+
 // VerifyUserRequest 用户验证请求参数
 type VerifyUserRequest struct {
 	UserID int `form:"user_id" binding:"required"` // 用户ID,必填
@@ -102,6 +168,7 @@ type UserStatsResponse struct {
 	Code int `json:"code"` // 响应状态码,200表示成功
 	Data struct {
 		FollowingCount    int `json:"following_count"`     // 关注数量
+		FollowerCount     int `json:"follower_count"`      // 粉丝数量
 		PostCount         int `json:"post_count"`          // 发布的帖子数量
 		FavoritePostCount int `json:"favorite_post_count"` // 收藏的帖子数量
 		CommentCount      int `json:"comment_count"`       // 评论数量
@@ -155,6 +222,21 @@ type TestAccount struct {
 	Password string `json:"password"` // 体验账号密码
 }
 
+// ConvertTestAccountRequest 体验账号转为正式账号请求参数
+type ConvertTestAccountRequest struct {
+	UserID   int    `form:"user_id"`                               // 用户ID
+	Email    string `form:"email" binding:"required"`              // 正式账号邮箱,必填
+	Code     string `form:"code" binding:"required,len=6,numeric"` // 邮箱验证码,必填
+	Password string `form:"password" binding:"required"`           // 正式账号密码,必填
+}
+
+// ConvertTestAccountResponse 体验账号转为正式账号响应
+type ConvertTestAccountResponse struct {
+	Code     int              `json:"code"`      // 响应状态码,200表示成功
+	Message  string           `json:"message"`   // 响应消息
+	UserInfo *entity.UserInfo `json:"user_info"` // 转换后的用户信息
+}
+
 // TestAccountResponse 体验账号响应
 type TestAccountResponse struct {
 	Code    int          `json:"code"`    // 响应状态码,200表示成功
@@ -62,6 +62,31 @@ type GetVideoURLResponse struct {
 	PosterPath string              `json:"PosterPath"` // 海报路径
 }
 
+// ReportVideoSourceRequest 上报播放源播放失败的请求参数
+type ReportVideoSourceRequest struct {
+	UserID  int    // 用户ID
+	VideoID int    `json:"videoId" binding:"required"` // 视频ID
+	Episode string `json:"episode" binding:"required"` // 集数
+	URL     string `json:"url" binding:"required"`     // 播放失败的地址
+}
+
+// ReportBrokenLinkRequest 上报播放地址失效的请求参数
+type ReportBrokenLinkRequest struct {
+	UserID  int    // 用户ID
+	VideoID int    `json:"videoId" binding:"required"` // 视频ID
+	Episode string `json:"episode" binding:"required"` // 集数
+}
+
+// GetLinkHealthStatsRequest 获取播放地址探测统计的请求参数
+type GetLinkHealthStatsRequest struct {
+}
+
+// GetLinkHealthStatsResponse 获取播放地址探测统计的响应
+type GetLinkHealthStatsResponse struct {
+	Code  int                      `json:"code"`  // 响应状态码
+	Stats []*entity.LinkHealthStat `json:"stats"` // 各来源站点的探测统计
+}
+
 // GetHomeAnimesRequest 获取首页动漫的请求参数
 type GetHomeAnimesRequest struct {
 	UserID int // 用户ID
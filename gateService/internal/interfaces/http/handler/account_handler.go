@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"gateService/internal/domain/service"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	accountService service.AccountService
+}
+
+func NewAccountHandler(accountService service.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+func (h *AccountHandler) RequestDataExport(c *gin.Context) {
+	request := dto.RequestDataExportRequest{
+		UserID: c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID,
+	}
+
+	response, err := h.accountService.RequestDataExport(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AccountHandler) GetDataExport(c *gin.Context) {
+	var request dto.GetDataExportRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.accountService.GetDataExport(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AccountHandler) DownloadDataExport(c *gin.Context) {
+	var request dto.DownloadDataExportRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.accountService.DownloadDataExport(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.FileAttachment(response.FilePath, response.FileName)
+}
+
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	var request dto.DeleteAccountRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.accountService.DeleteAccount(c, &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
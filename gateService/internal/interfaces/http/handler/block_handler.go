@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"gateService/internal/domain/service"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlockHandler struct {
+	blockService service.BlockService
+}
+
+func NewBlockHandler(blockService service.BlockService) *BlockHandler {
+	return &BlockHandler{blockService: blockService}
+}
+
+func (h *BlockHandler) Block(c *gin.Context) {
+	var request dto.BlockRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.blockService.Block(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BlockHandler) Unblock(c *gin.Context) {
+	var request dto.BlockRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.blockService.Unblock(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BlockHandler) Mute(c *gin.Context) {
+	var request dto.BlockRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.blockService.Mute(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BlockHandler) Unmute(c *gin.Context) {
+	var request dto.BlockRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.blockService.Unmute(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BlockHandler) GetBlockedUsers(c *gin.Context) {
+	var request dto.BlockListRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.blockService.GetBlockedUsers(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BlockHandler) GetMutedUsers(c *gin.Context) {
+	var request dto.BlockListRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.blockService.GetMutedUsers(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
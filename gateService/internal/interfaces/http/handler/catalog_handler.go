@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"gateService/internal/domain/service"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogHandler struct {
+	catalogService service.CatalogService
+}
+
+func NewCatalogHandler(catalogService service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{catalogService: catalogService}
+}
+
+func (h *CatalogHandler) ListVideos(c *gin.Context) {
+	var request dto.CatalogVideoListRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.catalogService.ListVideos(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) GetVideoDetail(c *gin.Context) {
+	var request dto.CatalogVideoRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.catalogService.GetVideoDetail(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) CreateVideo(c *gin.Context) {
+	var request dto.CatalogVideoSaveRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.catalogService.CreateVideo(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) UpdateVideo(c *gin.Context) {
+	var request dto.CatalogVideoSaveRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.catalogService.UpdateVideo(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) DeleteVideo(c *gin.Context) {
+	var request dto.CatalogVideoRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.catalogService.DeleteVideo(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) UpdateGenres(c *gin.Context) {
+	var request dto.CatalogGenresRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.catalogService.UpdateGenres(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) UpdateAliases(c *gin.Context) {
+	var request dto.CatalogAliasesRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.catalogService.UpdateAliases(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) UpdateEpisodes(c *gin.Context) {
+	var request dto.CatalogEpisodesRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.catalogService.UpdateEpisodes(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) GetEpisodeURL(c *gin.Context) {
+	var request dto.CatalogEpisodeURLRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.catalogService.GetEpisodeURL(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) UpdateEpisodeURL(c *gin.Context) {
+	var request dto.CatalogEpisodeURLUpdateRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.catalogService.UpdateEpisodeURL(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) ImportCatalog(c *gin.Context) {
+	var request dto.CatalogImportRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.catalogService.ImportCatalog(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CatalogHandler) ExportCatalog(c *gin.Context) {
+	var request dto.CatalogExportRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	if request.Format == "" {
+		request.Format = "json"
+	}
+	contentType := "application/json; charset=utf-8"
+	if request.Format == "csv" {
+		contentType = "text/csv; charset=utf-8"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename=catalog."+request.Format)
+
+	// 响应已开始写出,导出失败时只能记录错误
+	if err := h.catalogService.ExportCatalog(c.Request.Context(), &request, c.Writer); err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"gateService/internal/domain/service"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	followService service.FollowService
+}
+
+func NewFollowHandler(followService service.FollowService) *FollowHandler {
+	return &FollowHandler{followService: followService}
+}
+
+func (h *FollowHandler) Follow(c *gin.Context) {
+	var request dto.FollowRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.followService.Follow(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	var request dto.FollowRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.followService.Unfollow(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *FollowHandler) GetFollowers(c *gin.Context) {
+	var request dto.FollowListRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.followService.GetFollowers(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *FollowHandler) GetFollowing(c *gin.Context) {
+	var request dto.FollowListRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.followService.GetFollowing(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
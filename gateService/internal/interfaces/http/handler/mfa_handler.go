@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"gateService/internal/domain/service"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MfaHandler struct {
+	mfaService service.MfaService
+}
+
+func NewMfaHandler(mfaService service.MfaService) *MfaHandler {
+	return &MfaHandler{mfaService: mfaService}
+}
+
+func (h *MfaHandler) GetMfaStatus(c *gin.Context) {
+	var request dto.GetMfaStatusRequest
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.mfaService.GetMfaStatus(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *MfaHandler) SetupMfa(c *gin.Context) {
+	var request dto.SetupMfaRequest
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.mfaService.SetupMfa(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *MfaHandler) ConfirmMfa(c *gin.Context) {
+	var request dto.ConfirmMfaRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.mfaService.ConfirmMfa(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *MfaHandler) DisableMfa(c *gin.Context) {
+	var request dto.DisableMfaRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.mfaService.DisableMfa(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
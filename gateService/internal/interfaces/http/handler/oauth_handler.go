@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"gateService/internal/domain/service"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthHandler struct {
+	oauthService service.OAuthService
+}
+
+func NewOAuthHandler(oauthService service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{oauthService: oauthService}
+}
+
+func (h *OAuthHandler) GetProviders(c *gin.Context) {
+	response, err := h.oauthService.GetProviders(c.Request.Context())
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Login 发起第三方登录,重定向到身份提供方授权页面
+func (h *OAuthHandler) Login(c *gin.Context) {
+	var request dto.OAuthLoginRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.Provider = c.Param("provider")
+
+	response, err := h.oauthService.GetAuthorizationURL(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.Redirect(http.StatusFound, response.AuthURL)
+}
+
+// Callback 处理身份提供方回调,登录成功后重定向回前端
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	var request dto.OAuthCallbackRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.Provider = c.Param("provider")
+
+	response, err := h.oauthService.HandleCallback(c, &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrUnauthorized.Code, err.Error(), err))
+		return
+	}
+
+	c.Redirect(http.StatusFound, response.RedirectURL)
+}
+
+// ConfirmLink 输入本地账号密码,确认将第三方身份绑定到已有账号
+func (h *OAuthHandler) ConfirmLink(c *gin.Context) {
+	var request dto.OAuthLinkConfirmRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.oauthService.ConfirmLink(c, &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrUnauthorized.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"gateService/internal/domain/service"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalAccessTokenHandler struct {
+	personalAccessTokenService service.PersonalAccessTokenService
+}
+
+func NewPersonalAccessTokenHandler(personalAccessTokenService service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{personalAccessTokenService: personalAccessTokenService}
+}
+
+func (h *PersonalAccessTokenHandler) CreateToken(c *gin.Context) {
+	var request dto.CreatePersonalAccessTokenRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.personalAccessTokenService.CreateToken(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PersonalAccessTokenHandler) ListTokens(c *gin.Context) {
+	request := dto.ListPersonalAccessTokensRequest{
+		UserID: c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID,
+	}
+
+	response, err := h.personalAccessTokenService.ListTokens(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PersonalAccessTokenHandler) RevokeToken(c *gin.Context) {
+	var request dto.RevokePersonalAccessTokenRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.personalAccessTokenService.RevokeToken(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
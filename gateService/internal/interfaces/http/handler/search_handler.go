@@ -25,6 +25,8 @@ func (h *SearchHandler) SearchAnime(c *gin.Context) {
 		return
 	}
 
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
 	animes, err := h.searchService.SearchVideos(c.Request.Context(), &request)
 	if err != nil {
 		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
@@ -51,3 +53,51 @@ func (h *SearchHandler) SearchAnimeDetail(c *gin.Context) {
 
 	c.JSON(http.StatusOK, animes)
 }
+
+func (h *SearchHandler) SuggestAnime(c *gin.Context) {
+	var request dto.SearchSuggestRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.searchService.SuggestVideos(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *SearchHandler) GetTrendingSearches(c *gin.Context) {
+	var request dto.SearchTrendingRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.searchService.GetTrendingSearches(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *SearchHandler) GetZeroResultSearches(c *gin.Context) {
+	var request dto.SearchZeroResultRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.searchService.GetZeroResultSearches(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"gateService/internal/domain/service"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/internal/interfaces/dto"
+	"gateService/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	sessionService service.SessionService
+}
+
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	claims := c.MustGet("UserInfo").(*auth.CustomClaims)
+
+	request := dto.ListSessionsRequest{
+		UserID:           claims.UserInfo.UserID,
+		CurrentSessionID: claims.FamilyID,
+	}
+
+	response, err := h.sessionService.ListSessions(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	var request dto.RevokeSessionRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	claims := c.MustGet("UserInfo").(*auth.CustomClaims)
+	request.UserID = claims.UserInfo.UserID
+	request.CurrentSessionID = claims.FamilyID
+
+	response, err := h.sessionService.RevokeSession(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	claims := c.MustGet("UserInfo").(*auth.CustomClaims)
+
+	request := dto.RevokeOtherSessionsRequest{
+		UserID:           claims.UserInfo.UserID,
+		CurrentSessionID: claims.FamilyID,
+	}
+
+	response, err := h.sessionService.RevokeOtherSessions(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -6,6 +6,7 @@ import (
 	"gateService/internal/interfaces/dto"
 	"gateService/pkg/errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -34,6 +35,58 @@ func (h *UserHandler) Register(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *UserHandler) SendVerificationCode(c *gin.Context) {
+	var request dto.SendVerificationCodeRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserIP = c.ClientIP()
+
+	response, err := h.userService.SendVerificationCode(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var request dto.ForgotPasswordRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserIP = c.ClientIP()
+
+	response, err := h.userService.ForgotPassword(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var request dto.ResetPasswordRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.userService.ResetPassword(c, &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (h *UserHandler) Login(c *gin.Context) {
 	var request dto.LoginRequest
 	if err := c.ShouldBind(&request); err != nil {
@@ -41,8 +94,16 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
+	request.UserIP = c.ClientIP()
+
 	response, err := h.userService.Login(c, &request)
 	if err != nil {
+		// 登录被限流时通过Retry-After告知客户端需等待的秒数
+		if throttled, ok := err.(*service.LoginThrottledError); ok {
+			c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+			c.Error(errors.NewAppError(errors.ErrTooManyRequest.Code, err.Error(), err))
+			return
+		}
 		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
 		return
 	}
@@ -50,6 +111,30 @@ func (h *UserHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *UserHandler) LoginMfa(c *gin.Context) {
+	var request dto.LoginMfaRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserIP = c.ClientIP()
+
+	response, err := h.userService.LoginMfa(c, &request)
+	if err != nil {
+		// 登录被限流时通过Retry-After告知客户端需等待的秒数
+		if throttled, ok := err.(*service.LoginThrottledError); ok {
+			c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+			c.Error(errors.NewAppError(errors.ErrTooManyRequest.Code, err.Error(), err))
+			return
+		}
+		c.Error(errors.NewAppError(errors.ErrUnauthorized.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (h *UserHandler) VerifyUser(c *gin.Context) {
 	var request dto.VerifyUserRequest
 	if err := c.ShouldBind(&request); err != nil {
@@ -75,6 +160,25 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, nil)
 }
 
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	err := h.userService.LogoutAll(c)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+	c.JSON(http.StatusOK, nil)
+}
+
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	response, err := h.userService.RefreshToken(c)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrUnauthorized.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (h *UserHandler) GetUserInfo(c *gin.Context) {
 	var request dto.UserInfoRequest
 	if err := c.ShouldBind(&request); err != nil {
@@ -184,6 +288,22 @@ func (h *UserHandler) GetUserNotifications(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *UserHandler) UpdateUserRole(c *gin.Context) {
+	var request dto.UpdateUserRoleRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := h.userService.UpdateUserRole(c, &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (h *UserHandler) GetTestAccount(c *gin.Context) {
 	var request dto.TestAccountRequest
 	if err := c.ShouldBind(&request); err != nil {
@@ -201,3 +321,21 @@ func (h *UserHandler) GetTestAccount(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+func (h *UserHandler) ConvertTestAccount(c *gin.Context) {
+	var request dto.ConvertTestAccountRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	request.UserID = c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo.UserID
+
+	response, err := h.userService.ConvertTestAccount(c, &request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
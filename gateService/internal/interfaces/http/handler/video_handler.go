@@ -90,6 +90,72 @@ func (v *VideoHandler) GetVideoURL(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (v *VideoHandler) ReportVideoSource(c *gin.Context) {
+	request := &dto.ReportVideoSourceRequest{}
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	userInfo := c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo
+	request.UserID = userInfo.UserID
+
+	response, err := v.videoService.ReportVideoSource(c.Request.Context(), request)
+	if err != nil {
+		if err == service.ErrVideoReportLimited {
+			c.Error(errors.NewAppError(errors.ErrTooManyRequest.Code, err.Error(), err))
+			return
+		}
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (v *VideoHandler) ReportBrokenLink(c *gin.Context) {
+	request := &dto.ReportBrokenLinkRequest{}
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	userInfo := c.MustGet("UserInfo").(*auth.CustomClaims).UserInfo
+	request.UserID = userInfo.UserID
+
+	response, err := v.videoService.ReportBrokenLink(c.Request.Context(), request)
+	if err != nil {
+		if err == service.ErrVideoEpisodeNotFound {
+			c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+			return
+		}
+		if err == service.ErrVideoReportLimited {
+			c.Error(errors.NewAppError(errors.ErrTooManyRequest.Code, err.Error(), err))
+			return
+		}
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (v *VideoHandler) GetLinkHealthStats(c *gin.Context) {
+	request := &dto.GetLinkHealthStatsRequest{}
+	if err := c.ShouldBind(&request); err != nil {
+		c.Error(errors.NewAppError(errors.ErrParamInvalid.Code, err.Error(), err))
+		return
+	}
+
+	response, err := v.videoService.GetLinkHealthStats(c.Request.Context(), request)
+	if err != nil {
+		c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (v *VideoHandler) GetHomeAnimes(c *gin.Context) {
 	request := &dto.GetHomeAnimesRequest{}
 	if err := c.ShouldBind(&request); err != nil {
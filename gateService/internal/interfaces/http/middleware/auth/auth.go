@@ -5,19 +5,41 @@ import (
 	"gateService/internal/infrastructure/middleware/auth"
 	"gateService/pkg/errors"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// 会话最近活跃时间的最小更新间隔,间隔内的请求只读不写
+const sessionTouchInterval = time.Minute
+
 // JWTAuthMiddleware JWT认证中间件
+// 请求携带Authorization请求头时按个人访问令牌认证,否则从Cookie中读取JWT访问令牌
 // 参数:
 // - jwtManager: JWT令牌管理器,用于解析和验证令牌
 // - cookieManager: Cookie管理器,用于获取Cookie中的令牌
 // - userRepository: 用户仓储接口,用于检查用户状态
+// - tokenRepository: 令牌仓储接口,用于检查令牌是否已被吊销
+// - personalAccessTokenRepository: 个人访问令牌仓储接口,用于校验Bearer请求头中的个人访问令牌
 // 返回:
 // - gin.HandlerFunc: Gin中间件处理函数
-func JWTAuthMiddleware(jwtManager *auth.JWTManager, cookieManager *auth.CookieManager, userRepository repository.UserRepository) gin.HandlerFunc {
+func JWTAuthMiddleware(jwtManager *auth.JWTManager, cookieManager *auth.CookieManager, userRepository repository.UserRepository, tokenRepository repository.TokenRepository, personalAccessTokenRepository repository.PersonalAccessTokenRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 携带Authorization请求头时按个人访问令牌认证
+		if token, ok := bearerToken(c); ok {
+			claims, pat, appErr := authenticatePersonalAccessToken(c, token, userRepository, personalAccessTokenRepository)
+			if appErr != nil {
+				c.Error(appErr)
+				c.Abort()
+				return
+			}
+
+			c.Set("UserInfo", claims)
+			c.Set(personalAccessTokenKey, pat)
+			c.Next()
+			return
+		}
+
 		// 从Cookie中获取令牌
 		token, err := cookieManager.GetTokenCookie(c)
 		if err != nil {
@@ -28,7 +50,7 @@ func JWTAuthMiddleware(jwtManager *auth.JWTManager, cookieManager *auth.CookieMa
 		}
 
 		// 解析令牌获取声明信息
-		claims, err := jwtManager.ParseToken(token)
+		claims, err := jwtManager.ParseAccessToken(token)
 		if err != nil {
 			// 令牌解析失败,返回令牌无效错误
 			c.Error(errors.NewAppError(errors.ErrTokenInvalid.Code, err.Error(), err))
@@ -36,6 +58,34 @@ func JWTAuthMiddleware(jwtManager *auth.JWTManager, cookieManager *auth.CookieMa
 			return
 		}
 
+		// 检查令牌是否已被吊销(登出黑名单或用户级吊销)
+		revoked, err := tokenRepository.IsTokenRevoked(c, claims.UserInfo.UserID, claims.ID, claims.IssuedAtTime())
+		if err != nil {
+			c.Error(errors.NewAppError(errors.ErrUnauthorized.Code, err.Error(), err))
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.Error(errors.NewAppError(errors.ErrTokenInvalid.Code, "令牌已被吊销", nil))
+			c.Abort()
+			return
+		}
+
+		// 检查令牌所属会话是否已被移除,并按间隔刷新会话最近活跃时间
+		if claims.FamilyID != "" {
+			active, err := tokenRepository.TouchSession(c, claims.FamilyID, time.Now(), sessionTouchInterval)
+			if err != nil {
+				c.Error(errors.NewAppError(errors.ErrUnauthorized.Code, err.Error(), err))
+				c.Abort()
+				return
+			}
+			if !active {
+				c.Error(errors.NewAppError(errors.ErrTokenInvalid.Code, "登录会话已失效", nil))
+				c.Abort()
+				return
+			}
+		}
+
 		// 检查体验用户账号是否已失效
 		exist, _, err := userRepository.CheckInRedis(c, "test_account:deleted:"+strconv.Itoa(claims.UserInfo.UserID))
 		if err != nil {
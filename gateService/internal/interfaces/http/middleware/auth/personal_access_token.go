@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/pkg/errors"
+	"gateService/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// 个人访问令牌在gin上下文中的键,仅通过个人访问令牌认证的请求存在
+const personalAccessTokenKey = "PersonalAccessToken"
+
+// 个人访问令牌最近使用时间的最小更新间隔
+const personalAccessTokenTouchInterval = time.Minute
+
+// bearerToken 从Authorization请求头中获取Bearer令牌
+// 返回:
+// - string: 令牌
+// - bool: 请求是否携带Authorization请求头
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return "", false
+	}
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", true
+	}
+	return strings.TrimSpace(token), true
+}
+
+// authenticatePersonalAccessToken 校验个人访问令牌并构造与JWT一致的用户声明
+// 用户信息从用户仓储实时读取,令牌本身不携带用户信息快照
+// 参数:
+// - c: gin上下文
+// - token: 令牌明文
+// - userRepository: 用户仓储接口
+// - personalAccessTokenRepository: 个人访问令牌仓储接口
+// 返回:
+// - *auth.CustomClaims: 用户声明
+// - *entity.PersonalAccessToken: 令牌信息
+// - *errors.AppError: 认证失败时的错误
+func authenticatePersonalAccessToken(c *gin.Context, token string, userRepository repository.UserRepository, personalAccessTokenRepository repository.PersonalAccessTokenRepository) (*auth.CustomClaims, *entity.PersonalAccessToken, *errors.AppError) {
+	if !auth.IsPersonalAccessToken(token) {
+		return nil, nil, errors.NewAppError(errors.ErrTokenInvalid.Code, "无效的个人访问令牌", nil)
+	}
+
+	pat, err := personalAccessTokenRepository.GetTokenByHash(c, auth.HashPersonalAccessToken(token))
+	if err != nil {
+		return nil, nil, errors.NewAppError(errors.ErrUnauthorized.Code, err.Error(), err)
+	}
+	if pat == nil {
+		return nil, nil, errors.NewAppError(errors.ErrTokenInvalid.Code, "无效的个人访问令牌", nil)
+	}
+
+	now := time.Now()
+	if pat.IsExpired(now) {
+		return nil, nil, errors.NewAppError(errors.ErrTokenInvalid.Code, "个人访问令牌已过期", nil)
+	}
+
+	userInfo, err := userRepository.GetUserByID(c, pat.UserID)
+	if err != nil {
+		return nil, nil, errors.NewAppError(errors.ErrUnauthorized.Code, err.Error(), err)
+	}
+	if userInfo.Status != 1 {
+		return nil, nil, errors.NewAppError(errors.ErrUnauthorized.Code, "用户账号已失效", nil)
+	}
+	userInfo.Password = ""
+
+	// 按间隔更新最近使用时间,更新失败不影响本次请求
+	if pat.LastUsedAt == nil || now.Sub(*pat.LastUsedAt) >= personalAccessTokenTouchInterval {
+		if err := personalAccessTokenRepository.UpdateTokenLastUsed(c, pat.TokenID, now); err != nil {
+			logger.Log.Warn("更新个人访问令牌使用时间失败", zap.Int64("tokenID", pat.TokenID), zap.Error(err))
+		}
+	}
+
+	claims := &auth.CustomClaims{
+		UserInfo:   userInfo,
+		TokenType:  auth.PersonalAccessTokenType,
+		IssuedAtMs: pat.CreatedAt.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt: jwt.NewNumericDate(pat.CreatedAt),
+		},
+	}
+	if pat.ExpiresAt != nil {
+		claims.ExpiresAt = jwt.NewNumericDate(*pat.ExpiresAt)
+	}
+	return claims, pat, nil
+}
+
+// RequireScope 个人访问令牌授权范围校验中间件,需在JWTAuthMiddleware之后使用
+// 通过Cookie登录的请求不受授权范围限制
+// 参数:
+// - scope: 访问该路由分组所需的授权范围
+// 返回:
+// - gin.HandlerFunc: Gin中间件处理函数
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if value, ok := c.Get(personalAccessTokenKey); ok {
+			if !value.(*entity.PersonalAccessToken).HasScope(scope) {
+				c.Error(errors.NewAppError(errors.ErrForbidden.Code, "个人访问令牌缺少授权范围: "+scope, nil))
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// DenyPersonalAccessToken 拒绝个人访问令牌访问的中间件,需在JWTAuthMiddleware之后使用
+// 用于会话、两步验证、令牌管理等账号安全相关的路由,这些操作只能通过浏览器登录完成
+// 返回:
+// - gin.HandlerFunc: Gin中间件处理函数
+func DenyPersonalAccessToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(personalAccessTokenKey); ok {
+			c.Error(errors.NewAppError(errors.ErrForbidden.Code, "该接口不支持个人访问令牌", nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
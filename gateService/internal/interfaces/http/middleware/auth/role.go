@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"gateService/internal/domain/repository"
+	"gateService/internal/infrastructure/middleware/auth"
+	"gateService/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole 角色校验中间件,需在JWTAuthMiddleware之后使用
+// 角色以用户仓储中的最新值为准而非令牌中的快照,角色变更无需等待令牌过期即可生效
+// 参数:
+// - userRepository: 用户仓储接口,用于查询用户当前角色
+// - roles: 允许访问的角色列表
+// 返回:
+// - gin.HandlerFunc: Gin中间件处理函数
+func RequireRole(userRepository repository.UserRepository, roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		allowed[role] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		claims, ok := c.MustGet("UserInfo").(*auth.CustomClaims)
+		if !ok {
+			c.Error(errors.NewAppError(errors.ErrUnauthorized.Code, "未获取到用户信息", nil))
+			c.Abort()
+			return
+		}
+
+		// 查询用户当前角色
+		role, err := userRepository.GetUserRole(c, claims.UserInfo.UserID)
+		if err != nil {
+			c.Error(errors.NewAppError(errors.ErrInternalError.Code, err.Error(), err))
+			c.Abort()
+			return
+		}
+
+		if _, ok := allowed[role]; !ok {
+			c.Error(errors.NewAppError(errors.ErrForbidden.Code, "权限不足", nil))
+			c.Abort()
+			return
+		}
+
+		// 同步最新角色,供后续处理器使用
+		claims.UserInfo.Role = role
+		c.Next()
+	}
+}
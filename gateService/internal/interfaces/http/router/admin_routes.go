@@ -0,0 +1,44 @@
+package router
+
+import (
+	"gateService/internal/domain/entity"
+	"gateService/internal/interfaces/http/middleware/auth"
+)
+
+// setupAdminRoutes 初始化管理后台路由
+// 所有路由需要JWT认证且用户角色为管理员,不支持个人访问令牌
+func (c *Controller) setupAdminRoutes() {
+	adminGroup := c.engine.Group("/api/admin")
+	adminGroup.Use(auth.JWTAuthMiddleware(c.jwtManager, c.cookieManager, c.userRepository, c.tokenRepository, c.personalAccessTokenRepository))
+	adminGroup.Use(auth.DenyPersonalAccessToken())
+	adminGroup.Use(auth.RequireRole(c.userRepository, entity.RoleAdmin))
+	{
+		// ================== 用户管理模块 ==================
+		adminGroup.POST("/user/role", c.userHandler.UpdateUserRole) // 修改用户角色（参数：用户ID、角色）
+
+		// ================== 视频目录管理模块 ==================
+		// 所有修改操作完成后清除筛选条件、热门类型及该视频的播放地址缓存
+		adminGroup.GET("/video/list", c.catalogHandler.ListVideos)          // 按名称搜索视频（参数：名称关键字、页码）
+		adminGroup.GET("/video/detail", c.catalogHandler.GetVideoDetail)    // 获取视频详情（参数：视频ID,包含类型和剧集列表）
+		adminGroup.POST("/video/create", c.catalogHandler.CreateVideo)      // 创建视频（参数：名称、上映时间、地区、简介、封面、类型列表）
+		adminGroup.POST("/video/update", c.catalogHandler.UpdateVideo)      // 更新视频（参数：视频ID、基本信息,类型列表不为空时替换）
+		adminGroup.POST("/video/delete", c.catalogHandler.DeleteVideo)      // 删除视频（参数：视频ID,已被评论或收藏的视频不允许删除）
+		adminGroup.POST("/video/genres", c.catalogHandler.UpdateGenres)     // 替换视频类型（参数：视频ID、类型列表）
+		adminGroup.POST("/video/aliases", c.catalogHandler.UpdateAliases)   // 替换视频别名（参数：视频ID、别名列表,别名参与全文检索）
+		adminGroup.POST("/video/episodes", c.catalogHandler.UpdateEpisodes) // 替换剧集列表（参数：视频ID、按播放顺序排列的集数和播放地址）
+
+		// ================== 剧集播放地址管理 ==================
+		adminGroup.GET("/video/episode-url", c.catalogHandler.GetEpisodeURL)     // 查看一集的播放地址（参数：视频ID、集数,包含数据库保存的地址、爬取结果和当前缓存）
+		adminGroup.POST("/video/episode-url", c.catalogHandler.UpdateEpisodeURL) // 指定一集的播放地址（参数：视频ID、集数、播放地址,地址为空时取消指定）
+
+		// ================== 视频目录批量导入导出 ==================
+		adminGroup.POST("/catalog/import", c.catalogHandler.ImportCatalog) // 批量导入视频目录（参数：CSV或JSON文件、格式、是否试运行,返回差异报告）
+		adminGroup.GET("/catalog/export", c.catalogHandler.ExportCatalog)  // 导出视频目录（参数：格式,csv或json）
+
+		// ================== 搜索统计模块 ==================
+		adminGroup.GET("/search/zero-results", c.searchHandler.GetZeroResultSearches) // 无结果搜索词报表（参数：页码,按近期搜索热度排序）
+
+		// ================== 播放地址健康检查模块 ==================
+		adminGroup.GET("/video/link-health", c.videoHandler.GetLinkHealthStats) // 各来源站点的播放地址探测统计（按可用率从低到高排序）
+	}
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"gateService/internal/domain/entity"
 	"gateService/internal/interfaces/http/middleware/auth"
 )
 
@@ -17,76 +18,127 @@ import (
 func (c *Controller) setupAPIRoutes() {
 	// 创建API路由组，所有路由需要JWT认证
 	apiGroup := c.engine.Group("/api")
-	apiGroup.Use(auth.JWTAuthMiddleware(c.jwtManager, c.cookieManager, c.userRepository)) // 注入JWT和Cookie管理器
+	apiGroup.Use(auth.JWTAuthMiddleware(c.jwtManager, c.cookieManager, c.userRepository, c.tokenRepository, c.personalAccessTokenRepository)) // 注入JWT、Cookie管理器及个人访问令牌仓储
 
 	// 路由分组注册
+	// 个人访问令牌按路由分组校验授权范围,账号安全相关路由仅允许浏览器登录访问
 	{
 		// ================== 评论管理模块 ==================
 		// 功能：处理视频相关评论的提交和获取
-		apiGroup.POST("/movie/submit", c.commentHandler.SubmitComment)    // 提交新评论（需包含：视频ID、评论内容、父评论ID）
-		apiGroup.GET("/movie/comments", c.commentHandler.GetComments)     // 获取视频评论列表（参数：视频ID、分页信息）
-		apiGroup.POST("/movie/submitReply", c.commentHandler.SubmitReply) // 提交回复（需包含：视频ID、回复内容、父评论ID、被回复用户ID）
-		apiGroup.GET("/movie/replies", c.commentHandler.GetReply)         // 获取回复列表（参数：根评论ID、分页信息）
+		commentGroup := apiGroup.Group("", auth.RequireScope(entity.ScopeComment))
+		commentGroup.POST("/movie/submit", c.commentHandler.SubmitComment)    // 提交新评论（需包含：视频ID、评论内容、父评论ID）
+		commentGroup.GET("/movie/comments", c.commentHandler.GetComments)     // 获取视频评论列表（参数：视频ID、分页信息）
+		commentGroup.POST("/movie/submitReply", c.commentHandler.SubmitReply) // 提交回复（需包含：视频ID、回复内容、父评论ID、被回复用户ID）
+		commentGroup.GET("/movie/replies", c.commentHandler.GetReply)         // 获取回复列表（参数：根评论ID、分页信息）
 
 		// ================== 视频服务模块 ==================
 		// 功能：提供视频资源访问和元数据查询
-		apiGroup.GET("/video-resource", c.videoHandler.GetVideoURL)   // 获取视频播放地址（参数：视频ID、集数）
-		apiGroup.GET("/video-info", c.videoHandler.GetVideoInfo)      // 获取视频详细信息（参数：视频ID）
-		apiGroup.GET("/animeFilters", c.videoHandler.GetVideoFilters) // 获取动漫筛选条件（地区/年份/类型等）
-		apiGroup.GET("/animeLibrary", c.videoHandler.GetVideoLibrary) // 获取动漫库列表（支持分页和条件过滤）
-		apiGroup.GET("/getHomeAnime", c.videoHandler.GetHomeAnimes)   // 获取首页推荐动漫列表（根据用户ID）
-		apiGroup.GET("/movie/recommend", c.videoHandler.GetRecommend) // 获取推荐动漫列表（根据当前动漫类型）
+		videoGroup := apiGroup.Group("", auth.RequireScope(entity.ScopeVideo))
+		videoGroup.GET("/video-resource", c.videoHandler.GetVideoURL)                    // 获取视频播放地址（参数：视频ID、集数,返回按评分排序的全部播放源）
+		videoGroup.POST("/video-resource/report", c.videoHandler.ReportVideoSource)      // 上报播放失败的播放源（参数：视频ID、集数、播放地址,返回降级后重新排序的播放源）
+		videoGroup.POST("/video-resource/broken", c.videoHandler.ReportBrokenLink)       // 上报播放地址失效（参数：视频ID、集数,删除缓存并重新爬取后返回新的播放源）
+		videoGroup.GET("/video-info", c.videoHandler.GetVideoInfo)                       // 获取视频详细信息（参数：视频ID）
+		videoGroup.GET("/animeFilters", c.videoHandler.GetVideoFilters)                  // 获取动漫筛选条件（地区/年份/类型等）
+		videoGroup.GET("/animeLibrary", c.videoHandler.GetVideoLibrary)                  // 获取动漫库列表（支持分页和条件过滤）
+		videoGroup.GET("/getHomeAnime", c.videoHandler.GetHomeAnimes)                    // 获取首页推荐动漫列表（根据用户ID）
+		videoGroup.GET("/movie/recommend", c.videoHandler.GetRecommend)                  // 获取推荐动漫列表（根据当前动漫类型）
+		videoGroup.POST("/user/update-collection", c.videoHandler.UpdateAnimeCollection) // 更新动漫收藏状态（参数：视频ID、用户ID、收藏状态）
+		videoGroup.GET("/user/collection", c.videoHandler.GetAnimeCollection)            // 获取动漫收藏列表（参数：用户ID、页码、每页数量）
 
 		// ================== 订单处理模块 ==================
 		// 功能：处理商品购买和订单管理
-		apiGroup.POST("/order", c.orderHandler.CreateOrder)           // 创建新订单（参数：商品ID、支付方式）
-		apiGroup.GET("/get-products", c.productHandler.GetProducts)   // 获取可购商品列表
-		apiGroup.POST("/call-pay", c.orderHandler.CallbackPay)        // 支付结果回调接口（第三方支付平台调用）
-		apiGroup.GET("/get-orders", c.orderHandler.GetOrdersByUserID) // 获取用户历史订单（带分页）
+		orderGroup := apiGroup.Group("", auth.RequireScope(entity.ScopeOrder))
+		orderGroup.POST("/order", c.orderHandler.CreateOrder)           // 创建新订单（参数：商品ID、支付方式）
+		orderGroup.GET("/get-products", c.productHandler.GetProducts)   // 获取可购商品列表
+		orderGroup.POST("/call-pay", c.orderHandler.CallbackPay)        // 支付结果回调接口（第三方支付平台调用）
+		orderGroup.GET("/get-orders", c.orderHandler.GetOrdersByUserID) // 获取用户历史订单（带分页）
 
 		// ================== 搜索模块 ==================
 		// 功能：提供动漫搜索服务
-		apiGroup.GET("/search", c.searchHandler.SearchAnime)             // 动漫关键词搜索（参数：关键词、分页）
-		apiGroup.GET("/searchDetail", c.searchHandler.SearchAnimeDetail) // 动漫详情搜索（参数：精确ID）
+		searchGroup := apiGroup.Group("", auth.RequireScope(entity.ScopeSearch))
+		searchGroup.GET("/search", c.searchHandler.SearchAnime)             // 动漫关键词搜索（参数：关键词、分页）
+		searchGroup.GET("/searchDetail", c.searchHandler.SearchAnimeDetail) // 动漫详情搜索（参数：精确ID）
+		searchGroup.GET("/search/suggest", c.searchHandler.SuggestAnime)    // 输入提示（参数：已输入的名称、拼音或首字母、返回数量）
 
 		// ================== 用户认证模块 ==================
-		apiGroup.GET("/verify-token", c.userHandler.VerifyUser) // 令牌验证接口（返回最新用户信息）
-		apiGroup.GET("/logout", c.userHandler.Logout)           // 用户登出（清除认证信息）
+		accountGroup := apiGroup.Group("", auth.DenyPersonalAccessToken())
+		accountGroup.GET("/logout", c.userHandler.Logout)                                 // 用户登出（清除认证信息）
+		accountGroup.GET("/logout-all", c.userHandler.LogoutAll)                          // 退出全部设备（吊销该用户此前签发的全部令牌）
+		accountGroup.POST("/user/test-account/convert", c.userHandler.ConvertTestAccount) // 体验账号转为正式账号（参数：邮箱、邮箱验证码、密码,保留体验期间的数据）
+
+		// ================== 登录会话模块 ==================
+		accountGroup.GET("/user/sessions", c.sessionHandler.ListSessions)                       // 获取当前用户的登录会话（设备、IP、登录及最近活跃时间）
+		accountGroup.POST("/user/sessions/revoke", c.sessionHandler.RevokeSession)              // 移除指定登录会话（参数：会话ID）
+		accountGroup.POST("/user/sessions/revoke-others", c.sessionHandler.RevokeOtherSessions) // 移除除当前会话外的全部登录会话
+
+		// ================== 两步验证模块 ==================
+		accountGroup.GET("/user/mfa/status", c.mfaHandler.GetMfaStatus) // 获取两步验证开启状态
+		accountGroup.POST("/user/mfa/setup", c.mfaHandler.SetupMfa)     // 开始绑定两步验证（返回TOTP密钥和otpauth地址）
+		accountGroup.POST("/user/mfa/confirm", c.mfaHandler.ConfirmMfa) // 确认绑定两步验证（参数：动态验证码,返回一次性恢复码）
+		accountGroup.POST("/user/mfa/disable", c.mfaHandler.DisableMfa) // 关闭两步验证（参数：密码、动态验证码或恢复码）
+
+		// ================== 个人访问令牌模块 ==================
+		accountGroup.GET("/user/tokens", c.patHandler.ListTokens)          // 获取个人访问令牌列表（名称、授权范围、过期及最近使用时间）
+		accountGroup.POST("/user/tokens", c.patHandler.CreateToken)        // 创建个人访问令牌（参数：名称、授权范围、有效天数,令牌明文仅返回一次）
+		accountGroup.POST("/user/tokens/revoke", c.patHandler.RevokeToken) // 吊销个人访问令牌（参数：令牌ID）
+
+		// ================== 账号数据模块 ==================
+		accountGroup.POST("/user/export", c.accountHandler.RequestDataExport)          // 发起个人数据导出（异步生成ZIP,返回导出任务）
+		accountGroup.GET("/user/export/status", c.accountHandler.GetDataExport)        // 查询导出任务状态（参数：任务ID）
+		accountGroup.GET("/user/export/download", c.accountHandler.DownloadDataExport) // 下载导出文件（参数：任务ID）
+		accountGroup.POST("/user/delete", c.accountHandler.DeleteAccount)              // 注销账号（参数：密码、两步验证码,匿名化或删除全部个人数据）
 
 		// ================== 观看进度模块 ==================
-		apiGroup.GET("/load-progress", c.progressHandler.LoadProgress)  // 加载观看进度（参数：视频ID）
-		apiGroup.POST("/save-progress", c.progressHandler.SaveProgress) // 保存观看进度（参数：视频ID、时间点）
-		apiGroup.GET("/watch-history", c.progressHandler.WatchHistory)  // 获取用户观看历史记录（参数：用户ID、页码、每页数量）
+		progressGroup := apiGroup.Group("", auth.RequireScope(entity.ScopeProgress))
+		progressGroup.GET("/load-progress", c.progressHandler.LoadProgress)  // 加载观看进度（参数：视频ID）
+		progressGroup.POST("/save-progress", c.progressHandler.SaveProgress) // 保存观看进度（参数：视频ID、时间点）
+		progressGroup.GET("/watch-history", c.progressHandler.WatchHistory)  // 获取用户观看历史记录（参数：用户ID、页码、每页数量）
 
 		// ================== 社区互动模块 ==================
 		// 功能：处理用户发帖和评论互动
-		apiGroup.POST("/create-post", c.postHandler.CreatePost)                 // 创建新帖子（参数：标题、内容、标签）
-		apiGroup.GET("/post/comments", c.postHandler.GetPostComments)           // 获取帖子主评论列表（参数：帖子ID）
-		apiGroup.GET("/comment/replies", c.postHandler.GetPostCommentsByRootID) // 获取评论回复（参数：根评论ID）
-		apiGroup.POST("/comment/submit", c.postHandler.SubmitComment)           // 提交帖子评论（参数：帖子ID、内容）
-		apiGroup.POST("/comment/reply", c.postHandler.SubmitPostReply)          // 提交帖子回复（参数：帖子ID、内容）
-		apiGroup.POST("/comment/like", c.postHandler.CommentLike)               // 提交帖子点赞（参数：帖子ID、用户ID）
-		apiGroup.GET("/post/categories", c.postHandler.GetPostCategoryList)     // 获取帖子分类列表
-		apiGroup.GET("/post/list", c.postHandler.GetPostsByCategoryID)          // 获取帖子列表（参数：分类ID、页码、每页数量）
-		apiGroup.GET("/post/detail", c.postHandler.GetPostByPostID)             // 获取帖子详情（参数：帖子ID）
-		apiGroup.POST("/post/like", c.postHandler.PostLike)                     // 提交帖子点赞（参数：帖子ID、用户ID）
-		apiGroup.POST("/post/favorite", c.postHandler.PostFavorite)             // 提交帖子收藏（参数：帖子ID、用户ID）
-		apiGroup.GET("/post/recent", c.postHandler.RecentPosts)                 // 获取用户最近发布的帖子列表（参数：用户ID，默认返回最近5条）
+		postGroup := apiGroup.Group("", auth.RequireScope(entity.ScopePost))
+		postGroup.POST("/create-post", c.postHandler.CreatePost)                 // 创建新帖子（参数：标题、内容、标签）
+		postGroup.GET("/post/comments", c.postHandler.GetPostComments)           // 获取帖子主评论列表（参数：帖子ID）
+		postGroup.GET("/comment/replies", c.postHandler.GetPostCommentsByRootID) // 获取评论回复（参数：根评论ID）
+		postGroup.POST("/comment/submit", c.postHandler.SubmitComment)           // 提交帖子评论（参数：帖子ID、内容）
+		postGroup.POST("/comment/reply", c.postHandler.SubmitPostReply)          // 提交帖子回复（参数：帖子ID、内容）
+		postGroup.POST("/comment/like", c.postHandler.CommentLike)               // 提交帖子点赞（参数：帖子ID、用户ID）
+		postGroup.GET("/post/categories", c.postHandler.GetPostCategoryList)     // 获取帖子分类列表
+		postGroup.GET("/post/list", c.postHandler.GetPostsByCategoryID)          // 获取帖子列表（参数：分类ID、页码、每页数量）
+		postGroup.GET("/post/detail", c.postHandler.GetPostByPostID)             // 获取帖子详情（参数：帖子ID）
+		postGroup.POST("/post/like", c.postHandler.PostLike)                     // 提交帖子点赞（参数：帖子ID、用户ID）
+		postGroup.POST("/post/favorite", c.postHandler.PostFavorite)             // 提交帖子收藏（参数：帖子ID、用户ID）
+		postGroup.GET("/post/recent", c.postHandler.RecentPosts)                 // 获取用户最近发布的帖子列表（参数：用户ID，默认返回最近5条）
 
 		// ================== 用户信息模块 ==================
-		apiGroup.GET("/user/current", c.userHandler.GetUserInfo)                       // 获取当前用户信息
-		apiGroup.POST("/user/update-collection", c.videoHandler.UpdateAnimeCollection) // 更新动漫收藏状态（参数：视频ID、用户ID、收藏状态）
-		apiGroup.GET("/user/collection", c.videoHandler.GetAnimeCollection)            // 获取动漫收藏列表（参数：用户ID、页码、每页数量）
-		apiGroup.GET("/user/profile", c.userHandler.GetUserProfile)                    // 获取用户详细信息（参数：用户ID）
-		apiGroup.POST("/user/update", c.userHandler.UpdateUserProfile)                 // 更新用户个人信息（参数：用户ID、用户名、邮箱、性别、个性签名、头像URL）
-		apiGroup.GET("/user/stats", c.userHandler.GetUserStats)                        // 获取用户个人主页计数信息（参数：用户ID）
-		apiGroup.POST("/user/upload-avatar", c.userHandler.UploadAvatar)               // 上传用户头像（参数：用户ID、头像文件）
-		apiGroup.GET("/user/notifications", c.userHandler.GetUserNotifications)        // 获取用户通知（参数：用户ID、通知类型、页码、每页数量）
+		userGroup := apiGroup.Group("", auth.RequireScope(entity.ScopeUser))
+		userGroup.GET("/verify-token", c.userHandler.VerifyUser)                 // 令牌验证接口（返回最新用户信息）
+		userGroup.GET("/user/current", c.userHandler.GetUserInfo)                // 获取当前用户信息
+		userGroup.GET("/user/profile", c.userHandler.GetUserProfile)             // 获取用户详细信息（参数：用户ID）
+		userGroup.POST("/user/update", c.userHandler.UpdateUserProfile)          // 更新用户个人信息（参数：用户ID、用户名、邮箱、性别、个性签名、头像URL）
+		userGroup.GET("/user/stats", c.userHandler.GetUserStats)                 // 获取用户个人主页计数信息（参数：用户ID）
+		userGroup.POST("/user/upload-avatar", c.userHandler.UploadAvatar)        // 上传用户头像（参数：用户ID、头像文件）
+		userGroup.GET("/user/notifications", c.userHandler.GetUserNotifications) // 获取用户通知（参数：用户ID、通知类型、页码、每页数量）
+
+		// ================== 用户关注模块 ==================
+		userGroup.POST("/user/follow", c.followHandler.Follow)         // 关注用户（参数：被关注的用户ID,首次关注时通知对方）
+		userGroup.POST("/user/unfollow", c.followHandler.Unfollow)     // 取消关注用户（参数：被关注的用户ID）
+		userGroup.GET("/user/followers", c.followHandler.GetFollowers) // 获取粉丝列表（参数：查询的用户ID,默认当前用户、页码、每页数量）
+		userGroup.GET("/user/following", c.followHandler.GetFollowing) // 获取关注列表（参数：查询的用户ID,默认当前用户、页码、每页数量）
+
+		// ================== 用户拉黑与静音模块 ==================
+		userGroup.POST("/user/block", c.blockHandler.Block)            // 拉黑用户（参数：目标用户ID,同时解除双方关注关系）
+		userGroup.POST("/user/unblock", c.blockHandler.Unblock)        // 取消拉黑用户（参数：目标用户ID）
+		userGroup.POST("/user/mute", c.blockHandler.Mute)              // 静音用户（参数：目标用户ID,不再接收对方的通知）
+		userGroup.POST("/user/unmute", c.blockHandler.Unmute)          // 取消静音用户（参数：目标用户ID）
+		userGroup.GET("/user/blocked", c.blockHandler.GetBlockedUsers) // 获取拉黑列表（参数：页码、每页数量）
+		userGroup.GET("/user/muted", c.blockHandler.GetMutedUsers)     // 获取静音列表（参数：页码、每页数量）
 	}
 
 	// 创建连接路由组，所有路由需要JWT认证
 	conGroup := c.engine.Group("/conn")
-	conGroup.Use(auth.JWTAuthMiddleware(c.jwtManager, c.cookieManager, c.userRepository))
+	conGroup.Use(auth.JWTAuthMiddleware(c.jwtManager, c.cookieManager, c.userRepository, c.tokenRepository, c.personalAccessTokenRepository))
+	conGroup.Use(auth.DenyPersonalAccessToken())
 	{
 		// ================== WebSocket模块 ==================
 		// 功能：建立实时通信连接
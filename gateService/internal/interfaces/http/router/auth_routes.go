@@ -2,9 +2,21 @@ package router
 
 func (c *Controller) setupAuthRoutes() {
 	// 认证API
-	// c.engine.POST("/api/signInfo", c.userHandler.Register)
-	// c.engine.POST("/api/sendVerificationCode", nil)
+	c.engine.POST("/api/signInfo", c.userHandler.Register)                         // 用户注册（参数：邮箱、密码、邮箱验证码）
+	c.engine.POST("/api/sendVerificationCode", c.userHandler.SendVerificationCode) // 发送注册验证码（参数：邮箱）
 	c.engine.POST("/api/loginInfo", c.userHandler.Login)
+	c.engine.POST("/api/login/mfa", c.userHandler.LoginMfa)             // 两步验证登录（参数：登录返回的待确认令牌、动态验证码或恢复码）
+	c.engine.POST("/api/token/refresh", c.userHandler.RefreshToken)     // 刷新访问令牌（使用刷新令牌Cookie,访问令牌过期后仍可调用）
+	c.engine.POST("/api/password/forgot", c.userHandler.ForgotPassword) // 申请密码重置（参数：邮箱,重置链接通过邮件发送）
+	c.engine.POST("/api/password/reset", c.userHandler.ResetPassword)   // 重置密码（参数：重置令牌、新密码）
+
+	c.engine.GET("/api/oauth/providers", c.oauthHandler.GetProviders)      // 获取已启用的第三方登录方式
+	c.engine.GET("/api/oauth/:provider/login", c.oauthHandler.Login)       // 发起第三方登录（参数：登录后跳转的站内路径,重定向到身份提供方）
+	c.engine.GET("/api/oauth/:provider/callback", c.oauthHandler.Callback) // 第三方登录回调（身份提供方重定向调用,成功后重定向回前端）
+
+	c.engine.POST("/api/oauth/link/confirm", c.oauthHandler.ConfirmLink) // 确认绑定已有账号（参数：回调地址片段中的link_token、本地账号密码）
 
 	c.engine.GET("/api/user/test-account", c.userHandler.GetTestAccount) // 获取体验账号（参数：用户IP地址）
+
+	c.engine.GET("/api/search/trending", c.searchHandler.GetTrendingSearches) // 热门搜索词（参数：返回数量,按近期搜索热度排序,无需登录）
 }
@@ -35,17 +35,28 @@ type Controller struct {
 	jwtManager    *auth.JWTManager    // JWT令牌管理器，负责令牌的生成与验证
 	cookieManager *auth.CookieManager // Cookie管理器，处理Cookie的加密和验证
 
-	userRepository repository.UserRepository // 用户仓储实例
+	userRepository  repository.UserRepository  // 用户仓储实例
+	tokenRepository repository.TokenRepository // 令牌仓储实例
+
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository // 个人访问令牌仓储实例
 
 	// 业务模块处理器（接口处理层）
-	progressHandler *handler.ProgressHandler // 用户观看进度处理器
-	postHandler     *handler.PostHandler     // 帖子管理处理器
-	commentHandler  *handler.CommentHandler  // 评论管理处理器
-	searchHandler   *handler.SearchHandler   // 搜索功能处理器
-	userHandler     *handler.UserHandler     // 用户管理处理器
-	productHandler  *handler.ProductHandler  // 商品管理处理器
-	orderHandler    *handler.OrderHandler    // 订单管理处理器
-	videoHandler    *handler.VideoHandler    // 视频服务处理器
+	progressHandler *handler.ProgressHandler            // 用户观看进度处理器
+	postHandler     *handler.PostHandler                // 帖子管理处理器
+	commentHandler  *handler.CommentHandler             // 评论管理处理器
+	searchHandler   *handler.SearchHandler              // 搜索功能处理器
+	userHandler     *handler.UserHandler                // 用户管理处理器
+	mfaHandler      *handler.MfaHandler                 // 两步验证处理器
+	oauthHandler    *handler.OAuthHandler               // 第三方登录处理器
+	sessionHandler  *handler.SessionHandler             // 登录会话处理器
+	patHandler      *handler.PersonalAccessTokenHandler // 个人访问令牌处理器
+	accountHandler  *handler.AccountHandler             // 账号数据导出与注销处理器
+	followHandler   *handler.FollowHandler              // 用户关注处理器
+	blockHandler    *handler.BlockHandler               // 用户拉黑与静音处理器
+	productHandler  *handler.ProductHandler             // 商品管理处理器
+	orderHandler    *handler.OrderHandler               // 订单管理处理器
+	videoHandler    *handler.VideoHandler               // 视频服务处理器
+	catalogHandler  *handler.CatalogHandler             // 视频目录管理处理器
 
 	// WebSocket通信处理器
 	// 功能包括：
@@ -61,6 +72,8 @@ type Controller struct {
 //   - jwtManager: JWT认证管理器实例
 //   - cookieManager: Cookie管理实例
 //   - userRepository: 用户仓储实例
+//   - tokenRepository: 令牌仓储实例
+//   - personalAccessTokenRepository: 个人访问令牌仓储实例
 //   - progressService ~ websocketService: 各业务领域服务实现
 //
 // 返回值说明：
@@ -70,31 +83,51 @@ func NewController(
 	jwtManager *auth.JWTManager,
 	cookieManager *auth.CookieManager,
 	userRepository repository.UserRepository,
+	tokenRepository repository.TokenRepository,
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository,
 	progressService service.ProgressService,
 	postService service.PostService,
 	commentService service.CommentService,
 	searchService service.SearchService,
 	userService service.UserService,
+	mfaService service.MfaService,
+	oauthService service.OAuthService,
+	sessionService service.SessionService,
+	personalAccessTokenService service.PersonalAccessTokenService,
+	accountService service.AccountService,
+	followService service.FollowService,
+	blockService service.BlockService,
 	productService service.ProductService,
 	orderService service.OrderService,
 	videoService service.VideoService,
+	catalogService service.CatalogService,
 	websocketService service.WebSocketService,
 ) *Controller {
 	return &Controller{
-		cfg:              cfg,
-		engine:           gin.Default(), // 使用Gin默认配置初始化路由引擎
-		jwtManager:       jwtManager,
-		cookieManager:    cookieManager,
-		userRepository:   userRepository,
-		progressHandler:  handler.NewProgressHandler(progressService),   // 初始化进度处理器
-		postHandler:      handler.NewPostHandler(postService),           // 初始化帖子处理器
-		commentHandler:   handler.NewCommentHandler(commentService),     // 初始化评论处理器
-		searchHandler:    handler.NewSearchHandler(searchService),       // 初始化搜索处理器
-		userHandler:      handler.NewUserHandler(userService),           // 初始化用户处理器
-		productHandler:   handler.NewProductHandler(productService),     // 初始化商品处理器
-		orderHandler:     handler.NewOrderHandler(orderService),         // 初始化订单处理器
-		videoHandler:     handler.NewVideoHandler(videoService),         // 初始化视频处理器
-		websocketHandler: handler.NewWebSocketHandler(websocketService), // 初始化WebSocket处理器
+		cfg:                           cfg,
+		engine:                        gin.Default(), // 使用Gin默认配置初始化路由引擎
+		jwtManager:                    jwtManager,
+		cookieManager:                 cookieManager,
+		userRepository:                userRepository,
+		tokenRepository:               tokenRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		progressHandler:               handler.NewProgressHandler(progressService),                       // 初始化进度处理器
+		postHandler:                   handler.NewPostHandler(postService),                               // 初始化帖子处理器
+		commentHandler:                handler.NewCommentHandler(commentService),                         // 初始化评论处理器
+		searchHandler:                 handler.NewSearchHandler(searchService),                           // 初始化搜索处理器
+		userHandler:                   handler.NewUserHandler(userService),                               // 初始化用户处理器
+		mfaHandler:                    handler.NewMfaHandler(mfaService),                                 // 初始化两步验证处理器
+		oauthHandler:                  handler.NewOAuthHandler(oauthService),                             // 初始化第三方登录处理器
+		sessionHandler:                handler.NewSessionHandler(sessionService),                         // 初始化登录会话处理器
+		patHandler:                    handler.NewPersonalAccessTokenHandler(personalAccessTokenService), // 初始化个人访问令牌处理器
+		accountHandler:                handler.NewAccountHandler(accountService),                         // 初始化账号数据处理器
+		followHandler:                 handler.NewFollowHandler(followService),                           // 初始化用户关注处理器
+		blockHandler:                  handler.NewBlockHandler(blockService),                             // 初始化用户拉黑与静音处理器
+		productHandler:                handler.NewProductHandler(productService),                         // 初始化商品处理器
+		orderHandler:                  handler.NewOrderHandler(orderService),                             // 初始化订单处理器
+		videoHandler:                  handler.NewVideoHandler(videoService),                             // 初始化视频处理器
+		catalogHandler:                handler.NewCatalogHandler(catalogService),                         // 初始化视频目录管理处理器
+		websocketHandler:              handler.NewWebSocketHandler(websocketService),                     // 初始化WebSocket处理器
 	}
 }
 
@@ -142,6 +175,7 @@ func (c *Controller) initMiddlewares() {
 func (c *Controller) setupRoutes() {
 	c.setupAuthRoutes()
 	c.setupAPIRoutes()
+	c.setupAdminRoutes()
 }
 
 func (c *Controller) setupMiddleHandler(f func(*gin.Context)) {
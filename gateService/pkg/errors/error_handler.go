@@ -47,6 +47,7 @@ const (
 	ErrCodeTokenInvalid   = 402
 	ErrCodeForbidden      = 403
 	ErrCodeNotFound       = 404
+	ErrCodeTooManyRequest = 429
 	ErrCodeInternalError  = 500
 	ErrCodeBusinessError  = 1001
 	ErrCodeDatabaseError  = 1002
@@ -211,6 +212,7 @@ var (
 	ErrTokenInvalid    = NewAppError(ErrCodeTokenInvalid, "令牌无效", nil)
 	ErrForbidden       = NewAppError(ErrCodeForbidden, "禁止访问", nil)
 	ErrNotFound        = NewAppError(ErrCodeNotFound, "资源不存在", nil)
+	ErrTooManyRequest  = NewAppError(ErrCodeTooManyRequest, "请求过于频繁", nil)
 	ErrInternalError   = NewAppError(ErrCodeInternalError, "服务器内部错误", nil)
 	ErrDatabaseError   = NewAppError(ErrCodeDatabaseError, "数据库操作失败", nil)
 	ErrValidationFail  = NewAppError(ErrCodeValidationFail, "数据验证失败", nil)
@@ -0,0 +1,353 @@
+package fulltext
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Field 文档字段及其在打分中的权重
+type Field struct {
+	Name   string
+	Weight float64
+}
+
+// Options 索引配置选项
+type Options struct {
+	Fields []Field // 参与检索的字段,按权重加权合并各字段的BM25得分
+
+	K1 float64 // 词频饱和参数,默认1.2
+	B  float64 // 文档长度归一化参数,默认0.75
+}
+
+// Document 待索引的文档,Fields按字段名存放文本,未配置的字段会被忽略
+type Document struct {
+	ID     int
+	Fields map[string][]string
+}
+
+// Hit 检索命中的文档
+type Hit struct {
+	ID    int
+	Score float64
+}
+
+// Index 内存倒排索引,可并发读写
+type Index struct {
+	fields []Field
+	k1     float64
+	b      float64
+
+	mu   sync.RWMutex
+	data *indexData
+}
+
+// indexData 倒排索引数据,整体重建时替换为新的实例
+type indexData struct {
+	postings     map[string]map[int][]int // 检索词 -> 文档ID -> 各字段词频
+	docs         map[int]*docStats
+	totalLengths []int // 各字段检索词总数,用于计算平均长度
+}
+
+// docStats 文档的字段长度及包含的检索词,删除文档时用于清理倒排表
+type docStats struct {
+	lengths []int
+	terms   []string
+}
+
+// 拼写纠错命中的检索词得分折扣
+const fuzzyWeight = 0.7
+
+// NewIndex 创建倒排索引
+func NewIndex(opts *Options) *Index {
+	k1, b := opts.K1, opts.B
+	if k1 <= 0 {
+		k1 = 1.2
+	}
+	if b <= 0 || b > 1 {
+		b = 0.75
+	}
+	return &Index{
+		fields: opts.Fields,
+		k1:     k1,
+		b:      b,
+		data:   newIndexData(len(opts.Fields)),
+	}
+}
+
+func newIndexData(fieldCount int) *indexData {
+	return &indexData{
+		postings:     make(map[string]map[int][]int),
+		docs:         make(map[int]*docStats),
+		totalLengths: make([]int, fieldCount),
+	}
+}
+
+// Reset 使用给定文档重建索引,重建期间检索仍使用旧索引
+func (i *Index) Reset(docs []*Document) {
+	data := newIndexData(len(i.fields))
+	for _, doc := range docs {
+		data.add(i.tokenize(doc), doc.ID)
+	}
+
+	i.mu.Lock()
+	i.data = data
+	i.mu.Unlock()
+}
+
+// Put 添加文档,文档已存在时替换
+func (i *Index) Put(doc *Document) {
+	tokens := i.tokenize(doc)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.data.remove(doc.ID)
+	i.data.add(tokens, doc.ID)
+}
+
+// Delete 删除文档,文档不存在时忽略
+func (i *Index) Delete(id int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.data.remove(id)
+}
+
+// Len 返回索引中的文档数量
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.data.docs)
+}
+
+// Search 检索文档,按得分从高到低排序,得分相同时按文档ID升序
+// 查询词不在索引中时,对字母或数字组成的查询词按编辑距离匹配相近的检索词
+// 中日韩文字按二元组索引,单字查询词匹配包含该字的全部检索词
+// 文档至少需要命中一半的查询词
+// 参数:
+// - query: 查询文本
+// - offset: 跳过的结果数
+// - limit: 返回的最大结果数
+// 返回:
+// - []Hit: 当前页的命中文档
+// - int: 命中文档总数
+func (i *Index) Search(query string, offset, limit int) ([]Hit, int) {
+	terms := unique(Tokenize(query))
+	if len(terms) == 0 {
+		return nil, 0
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	data := i.data
+	if len(data.docs) == 0 {
+		return nil, 0
+	}
+
+	docCount := float64(len(data.docs))
+	avgLengths := make([]float64, len(i.fields))
+	for f, total := range data.totalLengths {
+		avgLengths[f] = float64(total) / docCount
+	}
+
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	for _, term := range terms {
+		expansions := map[string]float64{term: 1}
+		if isSingleCJK(term) {
+			expansions = data.cjkTerms(term)
+		} else if _, ok := data.postings[term]; !ok {
+			expansions = data.fuzzyTerms(term)
+		}
+
+		termMatched := make(map[int]bool)
+		for expansion, weight := range expansions {
+			postings := data.postings[expansion]
+			df := float64(len(postings))
+			idf := math.Log(1 + (docCount-df+0.5)/(df+0.5))
+
+			for id, freqs := range postings {
+				lengths := data.docs[id].lengths
+				score := 0.0
+				for f, tf := range freqs {
+					if tf == 0 {
+						continue
+					}
+					norm := 1 - i.b
+					if avgLengths[f] > 0 {
+						norm += i.b * float64(lengths[f]) / avgLengths[f]
+					}
+					score += i.fields[f].Weight * float64(tf) * (i.k1 + 1) / (float64(tf) + i.k1*norm)
+				}
+				scores[id] += idf * weight * score
+				termMatched[id] = true
+			}
+		}
+		for id := range termMatched {
+			matched[id]++
+		}
+	}
+
+	required := (len(terms) + 1) / 2
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		if matched[id] >= required {
+			hits = append(hits, Hit{ID: id, Score: score})
+		}
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].ID < hits[b].ID
+	})
+
+	total := len(hits)
+	if offset >= total {
+		return []Hit{}, total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return hits[offset:end], total
+}
+
+// tokenize 按字段切分文档文本,返回各字段的检索词
+func (i *Index) tokenize(doc *Document) [][]string {
+	tokens := make([][]string, len(i.fields))
+	for f, field := range i.fields {
+		for _, text := range doc.Fields[field.Name] {
+			tokens[f] = append(tokens[f], Tokenize(text)...)
+		}
+	}
+	return tokens
+}
+
+func (d *indexData) add(tokens [][]string, id int) {
+	stats := &docStats{lengths: make([]int, len(tokens))}
+	for f, fieldTokens := range tokens {
+		stats.lengths[f] = len(fieldTokens)
+		d.totalLengths[f] += len(fieldTokens)
+
+		for _, token := range fieldTokens {
+			docs, ok := d.postings[token]
+			if !ok {
+				docs = make(map[int][]int)
+				d.postings[token] = docs
+			}
+			freqs, ok := docs[id]
+			if !ok {
+				freqs = make([]int, len(tokens))
+				docs[id] = freqs
+				stats.terms = append(stats.terms, token)
+			}
+			freqs[f]++
+		}
+	}
+	d.docs[id] = stats
+}
+
+func (d *indexData) remove(id int) {
+	stats, ok := d.docs[id]
+	if !ok {
+		return
+	}
+	for f, length := range stats.lengths {
+		d.totalLengths[f] -= length
+	}
+	for _, term := range stats.terms {
+		delete(d.postings[term], id)
+		if len(d.postings[term]) == 0 {
+			delete(d.postings, term)
+		}
+	}
+	delete(d.docs, id)
+}
+
+// cjkTerms 查找包含单字查询词的中日韩检索词,包括单字本身和含有该字的二元组
+// 与拼写纠错相同需要遍历全部检索词
+func (d *indexData) cjkTerms(term string) map[string]float64 {
+	result := make(map[string]float64)
+	for candidate := range d.postings {
+		if !isWordToken(candidate) && strings.Contains(candidate, term) {
+			result[candidate] = 1
+		}
+	}
+	return result
+}
+
+// fuzzyTerms 查找与查询词编辑距离相近的检索词
+// 长度不足4的查询词不纠错,长度不足8的允许1处差异,更长的允许2处差异
+// 需要遍历全部检索词,适用于词表规模有限的场景
+func (d *indexData) fuzzyTerms(term string) map[string]float64 {
+	result := make(map[string]float64)
+	length := utf8.RuneCountInString(term)
+	if length < 4 || !isWordToken(term) {
+		return result
+	}
+	maxDistance := 1
+	if length >= 8 {
+		maxDistance = 2
+	}
+
+	source := []rune(term)
+	for candidate := range d.postings {
+		target := []rune(candidate)
+		if abs(len(target)-len(source)) > maxDistance || !isWordToken(candidate) {
+			continue
+		}
+		if editDistance(source, target, maxDistance) <= maxDistance {
+			result[candidate] = fuzzyWeight
+		}
+	}
+	return result
+}
+
+// editDistance 计算两个字符串的编辑距离,超过上限时提前返回上限加一
+func editDistance(a, b []rune, limit int) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func unique(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	result := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !seen[token] {
+			seen[token] = true
+			result = append(result, token)
+		}
+	}
+	return result
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
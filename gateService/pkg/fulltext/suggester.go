@@ -0,0 +1,166 @@
+package fulltext
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// SuggestItem 参与输入提示的条目
+type SuggestItem struct {
+	ID    int
+	Text  string
+	Score float64 // 热度,前缀相同时热度高的排在前面
+}
+
+// Suggester 基于前缀树的输入提示,构建后只读,可并发查询
+// 每个条目以原文、全拼和首字母三种形式写入前缀树,
+// 每个节点预先保存该前缀下热度最高的若干条目,查询耗时只与前缀长度有关
+type Suggester struct {
+	root  *suggestNode
+	limit int
+}
+
+type suggestNode struct {
+	children map[rune]*suggestNode
+	top      []int // 该前缀下热度最高的条目ID,按热度从高到低排列
+}
+
+var pinyinArgs = pinyin.NewArgs()
+
+// NewSuggester 构建输入提示
+// 参数:
+// - items: 全部条目
+// - limit: 每个前缀保存的最大条目数,即单次查询可返回的最大条目数
+func NewSuggester(items []SuggestItem, limit int) *Suggester {
+	sorted := make([]SuggestItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if sorted[a].Score != sorted[b].Score {
+			return sorted[a].Score > sorted[b].Score
+		}
+		return sorted[a].ID < sorted[b].ID
+	})
+
+	s := &Suggester{root: &suggestNode{}, limit: limit}
+	// 按热度从高到低插入,每个节点先收到的条目即为热度最高的条目
+	for _, item := range sorted {
+		for _, key := range SuggestKeys(item.Text) {
+			s.insert(key, item.ID)
+		}
+	}
+	return s
+}
+
+func (s *Suggester) insert(key string, id int) {
+	node := s.root
+	for _, r := range key {
+		if node.children == nil {
+			node.children = make(map[rune]*suggestNode)
+		}
+		child, ok := node.children[r]
+		if !ok {
+			child = &suggestNode{}
+			node.children[r] = child
+		}
+		node = child
+
+		// 同一条目的多个形式可能有相同前缀,只保存一次
+		if len(node.top) < s.limit && !containsID(node.top, id) {
+			node.top = append(node.top, id)
+		}
+	}
+}
+
+// Suggest 返回以给定文本为前缀的条目ID,按热度从高到低排列
+func (s *Suggester) Suggest(prefix string, n int) []int {
+	key := normalizeKey(prefix)
+	if key == "" {
+		return []int{}
+	}
+
+	node := s.root
+	for _, r := range key {
+		child, ok := node.children[r]
+		if !ok {
+			return []int{}
+		}
+		node = child
+	}
+
+	if n <= 0 || n > len(node.top) {
+		n = len(node.top)
+	}
+	result := make([]int, n)
+	copy(result, node.top[:n])
+	return result
+}
+
+// SuggestKeys 生成文本用于前缀匹配的形式,包括原文、全拼和首字母
+// 例如"进击的巨人"生成"进击的巨人"、"jinjidejuren"、"jjdjr",
+// "Attack on Titan"生成"attackontitan"、"aot"
+func SuggestKeys(text string) []string {
+	var origin, full, initials strings.Builder
+	inWord := false
+	for _, r := range text {
+		r = normalizeRune(r)
+		switch {
+		case unicode.Is(unicode.Han, r):
+			origin.WriteRune(r)
+			inWord = false
+			if pys := pinyin.SinglePinyin(r, pinyinArgs); len(pys) > 0 && pys[0] != "" {
+				full.WriteString(pys[0])
+				initials.WriteByte(pys[0][0])
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			origin.WriteRune(r)
+			full.WriteRune(r)
+			if !inWord {
+				initials.WriteRune(r)
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+
+	keys := make([]string, 0, 3)
+	for _, key := range []string{origin.String(), full.String(), initials.String()} {
+		if key != "" && !containsKey(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// normalizeKey 按生成前缀时的规则处理查询文本,忽略空白和标点
+func normalizeKey(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		r = normalizeRune(r)
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, v := range keys {
+		if v == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"gateService/pkg/fulltext"
+	"reflect"
+	"testing"
+)
+
+func newTestIndex() *fulltext.Index {
+	index := fulltext.NewIndex(&fulltext.Options{
+		Fields: []fulltext.Field{
+			{Name: "name", Weight: 3},
+			{Name: "genres", Weight: 1.5},
+			{Name: "description", Weight: 1},
+		},
+	})
+	index.Reset([]*fulltext.Document{
+		{ID: 1, Fields: map[string][]string{"name": {"进击的巨人"}, "genres": {"热血", "奇幻"}, "description": {"人类与巨人的战斗"}}},
+		{ID: 2, Fields: map[string][]string{"name": {"Attack on Titan"}, "description": {"巨人来袭"}}},
+		{ID: 3, Fields: map[string][]string{"name": {"Naruto 疾风传"}, "genres": {"热血"}}},
+		{ID: 4, Fields: map[string][]string{"name": {"海贼王"}, "description": {"热血冒险"}}},
+	})
+	return index
+}
+
+func hitIDs(hits []fulltext.Hit) []int {
+	ids := make([]int, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func Test_Tokenize(t *testing.T) {
+	cases := map[string][]string{
+		"进击的巨人":           {"进击", "击的", "的巨", "巨人"},
+		"Attack on Titan": {"attack", "on", "titan"},
+		"ＮＡＲＵＴＯ疾风传":       {"naruto", "疾风", "风传"},
+		"海":               {"海"},
+		"第2季：完结篇":         {"第", "2", "季", "完结", "结篇"},
+	}
+	for text, want := range cases {
+		if got := fulltext.Tokenize(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("Tokenize(%q) = %v, 期望 %v", text, got, want)
+		}
+	}
+}
+
+func Test_Search(t *testing.T) {
+	index := newTestIndex()
+
+	cases := []struct {
+		query string
+		want  []int
+	}{
+		{"巨人", []int{1, 2}},        // 名称命中的排在简介命中之前
+		{"巨人进击", []int{1}},         // 词序不同
+		{"titan attack", []int{2}}, // 英文词序不同
+		{"atack", []int{2}},        // 拼写错误
+		{"热血", []int{3, 4, 1}},     // 字段越短得分越高
+		{"不存在的标题", []int{}},        // 无结果
+		{"naruto 火影", []int{3}},    // 命中一半的查询词
+		{"naruto 火影忍者", []int{}},   // 命中不足一半的查询词
+		{"海", []int{4}},            // 单字匹配包含该字的二元组
+		{"巨", []int{1, 2}},         // 单字同时匹配名称和简介
+	}
+	for _, c := range cases {
+		hits, total := index.Search(c.query, 0, 10)
+		if got := hitIDs(hits); !reflect.DeepEqual(got, c.want) || total != len(c.want) {
+			t.Errorf("Search(%q) = %v (共%d条), 期望 %v", c.query, got, total, c.want)
+		}
+	}
+}
+
+func Test_Update(t *testing.T) {
+	index := newTestIndex()
+
+	index.Put(&fulltext.Document{ID: 4, Fields: map[string][]string{"name": {"One Piece"}}})
+	if hits, _ := index.Search("海贼王", 0, 10); len(hits) != 0 {
+		t.Errorf("替换后仍能检索到旧内容: %v", hitIDs(hits))
+	}
+	if hits, _ := index.Search("one piece", 0, 10); !reflect.DeepEqual(hitIDs(hits), []int{4}) {
+		t.Errorf("替换后未检索到新内容: %v", hitIDs(hits))
+	}
+
+	index.Delete(1)
+	if hits, _ := index.Search("巨人", 0, 10); !reflect.DeepEqual(hitIDs(hits), []int{2}) {
+		t.Errorf("删除后仍能检索到文档: %v", hitIDs(hits))
+	}
+	if index.Len() != 3 {
+		t.Errorf("文档数量为%d, 期望3", index.Len())
+	}
+
+	hits, total := index.Search("热血", 1, 1)
+	if total != 1 || len(hits) != 0 {
+		t.Errorf("分页结果错误: %v (共%d条)", hitIDs(hits), total)
+	}
+}
+
+func Test_Suggest(t *testing.T) {
+	suggester := fulltext.NewSuggester([]fulltext.SuggestItem{
+		{ID: 1, Text: "进击的巨人", Score: 100},
+		{ID: 2, Text: "进击的巨人 最终季", Score: 300},
+		{ID: 3, Text: "Attack on Titan", Score: 50},
+		{ID: 4, Text: "鬼灭之刃", Score: 200},
+		{ID: 5, Text: "剑风传奇", Score: 10},
+	}, 10)
+
+	cases := []struct {
+		prefix string
+		want   []int
+	}{
+		{"进击", []int{2, 1}},     // 原文前缀,按热度排序
+		{"jinji", []int{2, 1}},  // 全拼前缀
+		{"JJDJR", []int{2, 1}},  // 首字母
+		{"jjdjrzzj", []int{2}},  // 首字母包含后续汉字
+		{"attack on", []int{3}}, // 忽略空白
+		{"aot", []int{3}},       // 英文单词首字母
+		{"j", []int{2, 1, 5}},   // 单个字母同时匹配全拼和首字母
+		{"gmzr", []int{4}},      // 鬼灭之刃
+		{"不存在", []int{}},        // 无结果
+		{"  ", []int{}},         // 空白查询
+	}
+	for _, c := range cases {
+		if got := suggester.Suggest(c.prefix, 5); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Suggest(%q) = %v, 期望 %v", c.prefix, got, c.want)
+		}
+	}
+
+	if got := suggester.Suggest("j", 2); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Errorf("Suggest限制数量 = %v, 期望 [2 1]", got)
+	}
+}
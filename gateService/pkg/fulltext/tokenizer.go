@@ -0,0 +1,91 @@
+// Package fulltext 提供进程内的全文检索能力
+// 使用倒排索引存储文档,中日韩文字按二元组切分,其他文字按单词切分,检索结果按BM25打分排序
+// 另提供基于前缀树的输入提示,支持拼音全拼和首字母匹配
+package fulltext
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenize 将文本切分为检索词
+// 规则:
+//   - 全角字符转换为半角,英文统一转为小写
+//   - 连续的字母或数字作为一个词
+//   - 连续的中日韩文字按相邻两个字切分为二元组,只有一个字时保留单字
+//   - 标点、空白等其他字符作为分隔符
+func Tokenize(text string) []string {
+	tokens := make([]string, 0)
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		switch len(cjk) {
+		case 0:
+			return
+		case 1:
+			tokens = append(tokens, string(cjk))
+		default:
+			for i := 0; i+1 < len(cjk); i++ {
+				tokens = append(tokens, string(cjk[i:i+2]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		r = normalizeRune(r)
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
+
+// normalizeRune 全角转半角并转为小写
+func normalizeRune(r rune) rune {
+	switch {
+	case r == '\u3000':
+		r = ' '
+	case r >= '\uff01' && r <= '\uff5e':
+		r -= 0xfee0
+	}
+	return unicode.ToLower(r)
+}
+
+// isCJK 判断是否为中日韩文字(汉字、平假名、片假名、谚文)
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r) ||
+		r == '\u30fc'
+}
+
+// isSingleCJK 判断检索词是否为单个中日韩文字
+func isSingleCJK(token string) bool {
+	r, size := utf8.DecodeRuneInString(token)
+	return size == len(token) && isCJK(r)
+}
+
+// isWordToken 判断检索词是否由字母或数字组成,只有这类检索词支持拼写纠错
+func isWordToken(token string) bool {
+	r, _ := utf8.DecodeRuneInString(token)
+	return r != utf8.RuneError && !isCJK(r)
+}
@@ -0,0 +1,376 @@
+package identity
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OIDCOptions 通用OpenID Connect身份提供方配置
+type OIDCOptions struct {
+	Name         string       // 身份提供方名称
+	Issuer       string       // 签发方地址,用于服务发现和校验ID令牌
+	ClientID     string       // 客户端ID
+	ClientSecret string       // 客户端密钥,为空时按公共客户端处理
+	RedirectURL  string       // 授权回调地址
+	Scopes       []string     // 申请的权限范围,默认openid email profile
+	HTTPClient   *http.Client // 访问身份提供方使用的HTTP客户端
+}
+
+// oidcDiscovery OpenID Connect服务发现文档
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// idTokenClaims ID令牌声明
+type idTokenClaims struct {
+	Nonce         string      `json:"nonce"`
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"` // 部分身份提供方以字符串形式返回
+	Name          string      `json:"name"`
+	Picture       string      `json:"picture"`
+	AuthorizedBy  string      `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// OIDCProvider 通用OpenID Connect身份提供方
+// 服务发现文档和签名公钥在首次使用时拉取并缓存,遇到未知kid时重新拉取公钥
+type OIDCProvider struct {
+	opts OIDCOptions
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+// NewOIDCProvider 创建通用OpenID Connect身份提供方
+func NewOIDCProvider(opts *OIDCOptions) (*OIDCProvider, error) {
+	if opts == nil || opts.Name == "" || opts.Issuer == "" || opts.ClientID == "" || opts.RedirectURL == "" {
+		return nil, errors.New("OIDC配置缺少name/issuer/client_id/redirect_url")
+	}
+	p := &OIDCProvider{opts: *opts}
+	p.opts.Issuer = strings.TrimSuffix(p.opts.Issuer, "/")
+	if len(p.opts.Scopes) == 0 {
+		p.opts.Scopes = []string{"openid", "email", "profile"}
+	}
+	if p.opts.HTTPClient == nil {
+		p.opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return p, nil
+}
+
+// Name 身份提供方名称
+func (p *OIDCProvider) Name() string {
+	return p.opts.Name
+}
+
+// AuthCodeURL 生成授权地址,使用S256方式的PKCE
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.opts.ClientID)
+	params.Set("redirect_uri", p.opts.RedirectURL)
+	params.Set("scope", strings.Join(p.opts.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange 使用授权码换取令牌,校验ID令牌的签名、签发方、受众、有效期和nonce
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.opts.RedirectURL)
+	form.Set("client_id", p.opts.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("创建令牌请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.opts.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.opts.ClientID), url.QueryEscape(p.opts.ClientSecret))
+	}
+
+	var tokenResp struct {
+		AccessToken      string `json:"access_token"`
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.doJSON(req, &tokenResp)
+	if err != nil {
+		return nil, fmt.Errorf("换取令牌失败: %v", err)
+	}
+	if status != http.StatusOK || tokenResp.Error != "" {
+		return nil, fmt.Errorf("换取令牌失败: status=%d error=%s %s", status, tokenResp.Error, tokenResp.ErrorDescription)
+	}
+	if tokenResp.IDToken == "" {
+		return nil, fmt.Errorf("换取令牌失败: 响应中缺少id_token")
+	}
+
+	claims, err := p.verifyIDToken(ctx, tokenResp.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	if nonce == "" || claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	identity := &Identity{
+		Provider:      p.opts.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: parseBool(claims.EmailVerified),
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+	}
+
+	// ID令牌未携带邮箱时,从用户信息接口补全
+	if identity.Email == "" && d.UserinfoEndpoint != "" && tokenResp.AccessToken != "" {
+		if err := p.fillUserInfo(ctx, d.UserinfoEndpoint, tokenResp.AccessToken, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	return identity, nil
+}
+
+// verifyIDToken 校验ID令牌
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, rawIDToken string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(p.opts.Issuer),
+		jwt.WithAudience(p.opts.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: 缺少sub", ErrInvalidIDToken)
+	}
+	// 存在多个受众时,授权方必须是本客户端
+	if len(claims.Audience) > 1 && claims.AuthorizedBy != p.opts.ClientID {
+		return nil, fmt.Errorf("%w: azp不匹配", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+// fillUserInfo 从用户信息接口补全邮箱等信息,sub必须与ID令牌一致
+func (p *OIDCProvider) fillUserInfo(ctx context.Context, endpoint, accessToken string, identity *Identity) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("创建用户信息请求失败: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var info struct {
+		Subject       string      `json:"sub"`
+		Email         string      `json:"email"`
+		EmailVerified interface{} `json:"email_verified"`
+		Name          string      `json:"name"`
+		Picture       string      `json:"picture"`
+	}
+	status, err := p.doJSON(req, &info)
+	if err != nil {
+		return fmt.Errorf("获取用户信息失败: %v", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("获取用户信息失败: status=%d", status)
+	}
+	if info.Subject != identity.Subject {
+		return fmt.Errorf("获取用户信息失败: sub不匹配")
+	}
+
+	identity.Email = info.Email
+	identity.EmailVerified = parseBool(info.EmailVerified)
+	if identity.Name == "" {
+		identity.Name = info.Name
+	}
+	if identity.Picture == "" {
+		identity.Picture = info.Picture
+	}
+	return nil
+}
+
+// getDiscovery 获取服务发现文档,成功后缓存
+func (p *OIDCProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.opts.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建服务发现请求失败: %v", err)
+	}
+	var d oidcDiscovery
+	status, err := p.doJSON(req, &d)
+	if err != nil {
+		return nil, fmt.Errorf("获取服务发现文档失败: %v", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("获取服务发现文档失败: status=%d", status)
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != p.opts.Issuer {
+		return nil, fmt.Errorf("服务发现文档签发方不匹配: %s", d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JwksURI == "" {
+		return nil, fmt.Errorf("服务发现文档缺少必要字段")
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// getKey 按kid获取签名公钥,本地缓存中不存在时重新拉取一次
+func (p *OIDCProvider) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key := p.lookupKey(kid); key != nil {
+		return key, nil
+	}
+
+	keys, err := p.fetchKeys(ctx, d.JwksURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	if key := p.lookupKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("未找到签名公钥: kid=%s", kid)
+}
+
+// lookupKey 在缓存中查找公钥,kid为空且只有一个公钥时直接使用该公钥
+func (p *OIDCProvider) lookupKey(kid string) *rsa.PublicKey {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+	return p.keys[kid]
+}
+
+// fetchKeys 拉取JWKS中的RSA签名公钥
+func (p *OIDCProvider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建公钥请求失败: %v", err)
+	}
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	status, err := p.doJSON(req, &jwks)
+	if err != nil {
+		return nil, fmt.Errorf("获取签名公钥失败: %v", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("获取签名公钥失败: status=%d", status)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// doJSON 发送请求并解析JSON响应,返回HTTP状态码
+func (p *OIDCProvider) doJSON(req *http.Request, v interface{}) (int, error) {
+	resp, err := p.opts.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, v); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("解析响应失败: %v", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// parseBool 解析布尔值,兼容字符串形式
+func parseBool(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return strings.EqualFold(b, "true")
+	default:
+		return false
+	}
+}
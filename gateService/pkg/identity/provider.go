@@ -0,0 +1,58 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("无效的ID令牌")
+	ErrNonceMismatch  = errors.New("ID令牌nonce不匹配")
+)
+
+// Identity 身份提供方返回的用户身份
+type Identity struct {
+	Provider      string // 身份提供方名称
+	Subject       string // 用户在身份提供方处的唯一标识
+	Email         string // 邮箱
+	EmailVerified bool   // 邮箱是否已由身份提供方验证
+	Name          string // 昵称
+	Picture       string // 头像地址
+}
+
+// IdentityProvider 第三方身份提供方
+// 采用授权码模式,调用方负责生成并保存state、nonce和PKCE校验码
+type IdentityProvider interface {
+	// Name 身份提供方名称,用于路由和账号绑定
+	Name() string
+
+	// AuthCodeURL 生成跳转到身份提供方的授权地址
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+
+	// Exchange 使用授权码换取并校验用户身份
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error)
+}
+
+// GenerateRandomString 生成n字节随机数并以base64url编码,用于state、nonce和PKCE校验码
+func GenerateRandomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("生成随机串失败: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// GenerateCodeVerifier 生成PKCE校验码(RFC 7636,43个字符)
+func GenerateCodeVerifier() (string, error) {
+	return GenerateRandomString(32)
+}
+
+// CodeChallengeS256 根据PKCE校验码计算S256质询值
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,237 @@
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"gateService/pkg/identity"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockIdP 进程内的OpenID Connect身份提供方,用于测试授权码+PKCE流程
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]url.Values // 授权码 -> 授权请求参数
+
+	// 以下字段用于构造异常场景
+	signKey  *rsa.PrivateKey // 签发ID令牌使用的私钥,默认与公布的公钥一致
+	audience string          // ID令牌受众,默认为请求的client_id
+	nonce    string          // ID令牌nonce,默认为授权请求中的nonce
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("生成RSA密钥失败: %v", err)
+	}
+	m := &mockIdP{key: key, signKey: key, codes: make(map[string]url.Values)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+			}},
+		})
+	})
+	// 模拟用户已登录并同意授权,直接携带授权码跳回回调地址
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		code := "code-" + q.Get("state")
+		m.mu.Lock()
+		m.codes[code] = q
+		m.mu.Unlock()
+		http.Redirect(w, r, q.Get("redirect_uri")+"?code="+code+"&state="+url.QueryEscape(q.Get("state")), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		m.mu.Lock()
+		auth, ok := m.codes[r.PostForm.Get("code")]
+		delete(m.codes, r.PostForm.Get("code"))
+		m.mu.Unlock()
+
+		clientID, clientSecret, _ := r.BasicAuth()
+		switch {
+		case !ok:
+			writeTokenError(w, "invalid_grant")
+			return
+		case clientID != "zanime" || clientSecret != "secret":
+			writeTokenError(w, "invalid_client")
+			return
+		case identity.CodeChallengeS256(r.PostForm.Get("code_verifier")) != auth.Get("code_challenge"):
+			writeTokenError(w, "invalid_grant")
+			return
+		}
+
+		audience, nonce := m.audience, m.nonce
+		if audience == "" {
+			audience = auth.Get("client_id")
+		}
+		if nonce == "" {
+			nonce = auth.Get("nonce")
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            m.server.URL,
+			"sub":            "user-42",
+			"aud":            audience,
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"iat":            time.Now().Unix(),
+			"nonce":          nonce,
+			"email":          "alice@example.com",
+			"email_verified": true,
+			"name":           "Alice",
+		})
+		token.Header["kid"] = "test-key"
+		idToken, err := token.SignedString(m.signKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		})
+	})
+
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func writeTokenError(w http.ResponseWriter, code string) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+// authorize 按授权地址访问模拟身份提供方,返回回调中的授权码和state
+func authorize(t *testing.T, authURL string) (string, string) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("访问授权地址失败: %v", err)
+	}
+	resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("解析回调地址失败: %v", err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+// login 执行一次完整的授权码+PKCE流程
+func login(t *testing.T, provider identity.IdentityProvider, verifierOverride string) (*identity.Identity, error) {
+	ctx := context.Background()
+	state, _ := identity.GenerateRandomString(16)
+	nonce, _ := identity.GenerateRandomString(16)
+	verifier, err := identity.GenerateCodeVerifier()
+	if err != nil {
+		t.Fatalf("生成PKCE校验码失败: %v", err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, identity.CodeChallengeS256(verifier))
+	if err != nil {
+		t.Fatalf("生成授权地址失败: %v", err)
+	}
+	code, gotState := authorize(t, authURL)
+	if gotState != state {
+		t.Fatalf("state不一致: 期望%s, 实际%s", state, gotState)
+	}
+
+	if verifierOverride != "" {
+		verifier = verifierOverride
+	}
+	return provider.Exchange(ctx, code, verifier, nonce)
+}
+
+func newProvider(t *testing.T, idp *mockIdP) identity.IdentityProvider {
+	provider, err := identity.NewOIDCProvider(&identity.OIDCOptions{
+		Name:         "mock",
+		Issuer:       idp.server.URL,
+		ClientID:     "zanime",
+		ClientSecret: "secret",
+		RedirectURL:  "http://gate.local/api/oauth/mock/callback",
+	})
+	if err != nil {
+		t.Fatalf("创建OIDC身份提供方失败: %v", err)
+	}
+	return provider
+}
+
+func Test_OIDCLogin(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newProvider(t, idp)
+
+	user, err := login(t, provider, "")
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if user.Provider != "mock" || user.Subject != "user-42" || user.Email != "alice@example.com" || !user.EmailVerified || user.Name != "Alice" {
+		t.Fatalf("身份信息不正确: %+v", user)
+	}
+}
+
+func Test_OIDCLoginRejectsWrongVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newProvider(t, idp)
+
+	wrong, _ := identity.GenerateCodeVerifier()
+	if _, err := login(t, provider, wrong); err == nil {
+		t.Fatal("PKCE校验码错误时应登录失败")
+	}
+}
+
+func Test_OIDCLoginRejectsInvalidIDToken(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("生成RSA密钥失败: %v", err)
+	}
+
+	cases := map[string]func(idp *mockIdP){
+		"签名密钥不匹配":  func(idp *mockIdP) { idp.signKey = otherKey },
+		"受众不匹配":    func(idp *mockIdP) { idp.audience = "other-client" },
+		"nonce不匹配": func(idp *mockIdP) { idp.nonce = "replayed-nonce" },
+	}
+	for name, setup := range cases {
+		t.Run(name, func(t *testing.T) {
+			idp := newMockIdP(t)
+			setup(idp)
+			provider := newProvider(t, idp)
+
+			_, err := login(t, provider, "")
+			if err == nil {
+				t.Fatal("ID令牌无效时应登录失败")
+			}
+			if !errors.Is(err, identity.ErrInvalidIDToken) && !errors.Is(err, identity.ErrNonceMismatch) {
+				t.Fatalf("错误类型不正确: %v", err)
+			}
+		})
+	}
+}
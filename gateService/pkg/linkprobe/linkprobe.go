@@ -0,0 +1,132 @@
+package linkprobe
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 默认单次探测超时时间
+const DefaultTimeout = 10 * time.Second
+
+// Result 表示一次探测结果
+type Result struct {
+	URL        string        // 探测的地址
+	StatusCode int           // 最终响应状态码,请求失败时为0
+	Latency    time.Duration // 探测耗时
+	Err        error         // 请求错误,如超时、连接失败
+}
+
+// Alive 判断地址是否可用
+// 被限流(429)时无法判断地址是否失效,按可用处理,避免误删
+func (r Result) Alive() bool {
+	if r.Err != nil {
+		return false
+	}
+	return r.StatusCode < 400 || r.StatusCode == http.StatusTooManyRequests
+}
+
+// Dead 判断地址是否可以确认失效
+// 请求失败可能只是网络波动,401和403可能是防盗链或签名校验拒绝了探测请求,限流同理,这些情况都无法确认地址失效
+func (r Result) Dead() bool {
+	if r.Err != nil {
+		return false
+	}
+	switch r.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return r.StatusCode >= 400
+}
+
+// Prober 通过HEAD请求或只读取首字节的范围请求探测视频地址是否可用
+type Prober struct {
+	client *http.Client
+}
+
+// NewProber 创建探测器
+// 参数:
+// - timeout: 单次探测超时时间,不大于0时使用DefaultTimeout
+// 返回:
+// - *Prober: 探测器
+func NewProber(timeout time.Duration) *Prober {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Prober{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Probe 探测单个地址
+// 先发送HEAD请求,部分视频站不支持HEAD或对HEAD返回错误,此时改用Range: bytes=0-0的GET请求再确认一次
+// 404和410表示资源已删除,不再重试
+func (p *Prober) Probe(ctx context.Context, url string) Result {
+	start := time.Now()
+	status, err := p.do(ctx, http.MethodHead, url)
+	if err == nil && status >= 400 && status != http.StatusNotFound && status != http.StatusGone {
+		status, err = p.do(ctx, http.MethodGet, url)
+	}
+	return Result{
+		URL:        url,
+		StatusCode: status,
+		Latency:    time.Since(start),
+		Err:        err,
+	}
+}
+
+// ProbeAll 并发探测多个地址,返回结果与地址一一对应
+// 参数:
+// - ctx: 上下文,取消后未开始的探测直接返回上下文错误
+// - urls: 待探测的地址
+// - concurrency: 最大并发数,不大于0时为1
+// 返回:
+// - []Result: 探测结果,顺序与urls一致
+func (p *Prober) ProbeAll(ctx context.Context, urls []string, concurrency int) []Result {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]Result, len(urls))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = Result{URL: url, Err: ctx.Err()}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = p.Probe(ctx, url)
+		}(i, url)
+	}
+	wg.Wait()
+	return results
+}
+
+// do 发送探测请求并返回状态码,GET请求只读取第一个字节
+func (p *Prober) do(ctx context.Context, method, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// 不支持范围请求的服务器会返回完整内容,只读少量数据后关闭连接
+	io.CopyN(io.Discard, resp.Body, 1)
+	return resp.StatusCode, nil
+}
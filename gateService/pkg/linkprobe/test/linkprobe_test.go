@@ -0,0 +1,120 @@
+package test
+
+import (
+	"context"
+	"gateService/pkg/linkprobe"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newVideoHost 模拟视频站,不同路径对应不同的响应行为
+func newVideoHost() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok.mp4", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	// 不支持HEAD,范围请求返回206
+	mux.HandleFunc("/nohead.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Range") != "bytes=0-0" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte("#"))
+	})
+	mux.HandleFunc("/gone.mp4", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/expired.mp4", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc("/redirect.mp4", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok.mp4", http.StatusFound)
+	})
+	mux.HandleFunc("/slow.mp4", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	return httptest.NewServer(mux)
+}
+
+func Test_Probe(t *testing.T) {
+	server := newVideoHost()
+	defer server.Close()
+
+	prober := linkprobe.NewProber(200 * time.Millisecond)
+	cases := []struct {
+		path   string
+		alive  bool
+		dead   bool
+		status int
+	}{
+		{"/ok.mp4", true, false, http.StatusOK},
+		{"/nohead.m3u8", true, false, http.StatusPartialContent},
+		{"/gone.mp4", false, true, http.StatusNotFound},
+		{"/expired.mp4", false, false, http.StatusForbidden},
+		{"/redirect.mp4", true, false, http.StatusOK},
+		{"/slow.mp4", false, false, 0},
+	}
+	for _, c := range cases {
+		result := prober.Probe(context.Background(), server.URL+c.path)
+		if result.Alive() != c.alive || result.Dead() != c.dead || result.StatusCode != c.status {
+			t.Errorf("%s: alive=%v dead=%v status=%d err=%v, 期望 alive=%v dead=%v status=%d", c.path, result.Alive(), result.Dead(), result.StatusCode, result.Err, c.alive, c.dead, c.status)
+		}
+	}
+}
+
+func Test_ProbeAll(t *testing.T) {
+	var current, peak int32
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		mu.Lock()
+		if n > peak {
+			peak = n
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+
+		if r.URL.Path == "/dead" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	urls := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		if i%5 == 0 {
+			urls = append(urls, server.URL+"/dead")
+		} else {
+			urls = append(urls, server.URL+"/alive")
+		}
+	}
+
+	results := linkprobe.NewProber(time.Second).ProbeAll(context.Background(), urls, 3)
+	if len(results) != len(urls) {
+		t.Fatalf("结果数量 %d, 期望 %d", len(results), len(urls))
+	}
+	for i, result := range results {
+		if result.URL != urls[i] {
+			t.Errorf("第%d个结果地址 %s, 期望 %s", i, result.URL, urls[i])
+		}
+		if result.Alive() != (i%5 != 0) {
+			t.Errorf("第%d个结果 alive=%v status=%d", i, result.Alive(), result.StatusCode)
+		}
+	}
+	if peak > 3 {
+		t.Errorf("最大并发 %d, 超过限制 3", peak)
+	}
+}
@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileMailer 将邮件写入文件或标准输出,用于本地开发调试
+type FileMailer struct {
+	mu   sync.Mutex
+	from string
+	path string
+}
+
+// NewFileMailer 创建文件邮件发送器,path为空时输出到标准输出
+func NewFileMailer(from, path string) *FileMailer {
+	return &FileMailer{
+		from: from,
+		path: path,
+	}
+}
+
+// Send 将邮件内容追加写入文件
+func (m *FileMailer) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var w io.Writer = os.Stdout
+	if m.path != "" {
+		f, err := os.OpenFile(m.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("打开邮件输出文件失败: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	_, err := fmt.Fprintf(w, "----- %s -----\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), m.from, to, subject, body)
+	if err != nil {
+		return fmt.Errorf("写入邮件失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+)
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	// Send 发送纯文本邮件
+	// 参数:
+	// - ctx: 上下文
+	// - to: 收件人地址
+	// - subject: 邮件主题
+	// - body: 邮件正文
+	// 返回:
+	// - error: 错误信息
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// 支持的邮件发送方式
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+)
+
+// Options 邮件发送配置
+type Options struct {
+	Driver   string // 发送方式: smtp/file
+	Host     string // SMTP服务器地址
+	Port     int    // SMTP服务器端口
+	Username string // SMTP认证用户名
+	Password string // SMTP认证密码
+	From     string // 发件人地址
+	FilePath string // file方式下的输出文件路径,为空时输出到标准输出
+}
+
+// New 根据配置创建邮件发送器
+func New(opts *Options) (Mailer, error) {
+	switch opts.Driver {
+	case DriverSMTP:
+		return NewSMTPMailer(opts), nil
+	case DriverFile, "":
+		return NewFileMailer(opts.From, opts.FilePath), nil
+	default:
+		return nil, fmt.Errorf("不支持的邮件发送方式: %s", opts.Driver)
+	}
+}
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPMailer 通过SMTP服务器发送邮件
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer 创建SMTP邮件发送器
+func NewSMTPMailer(opts *Options) *SMTPMailer {
+	var auth smtp.Auth
+	if opts.Username != "" {
+		auth = smtp.PlainAuth("", opts.Username, opts.Password, opts.Host)
+	}
+	return &SMTPMailer{
+		addr: fmt.Sprintf("%s:%d", opts.Host, opts.Port),
+		auth: auth,
+		from: opts.From,
+	}
+}
+
+// Send 发送纯文本邮件
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("收件人地址非法: %q", to)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("发送邮件失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 支持的哈希算法
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// 哈希格式前缀,用于识别存储值所使用的算法
+const argon2idPrefix = "$argon2id$"
+
+// bcrypt哈希的完整格式: $2a$10$ 后跟22位盐和31位哈希
+// 只匹配前缀会把以"$2"开头的历史明文密码误判为bcrypt,导致该用户无法登录
+var bcryptPattern = regexp.MustCompile(`^\$2[abxy]?\$\d\d\$[./A-Za-z0-9]{53}$`)
+
+// HashOptions 密码哈希参数
+type HashOptions struct {
+	Algorithm     string // 哈希算法: argon2id/bcrypt
+	BcryptCost    int    // bcrypt计算成本
+	Argon2Time    uint32 // argon2id迭代次数
+	Argon2Memory  uint32 // argon2id内存占用(KB)
+	Argon2Threads uint8  // argon2id并行度
+	Argon2KeyLen  uint32 // argon2id输出长度
+	Argon2SaltLen uint32 // argon2id盐长度
+}
+
+// Hasher 密码哈希器
+// 新密码统一使用配置的算法生成,校验时根据前缀自动识别算法,
+// 未带前缀的旧数据按明文比较,并提示调用方重新哈希
+type Hasher struct {
+	opts HashOptions
+}
+
+// NewHasher 创建密码哈希器,未设置的参数使用默认值
+func NewHasher(opts *HashOptions) *Hasher {
+	h := &Hasher{}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Algorithm == "" {
+		h.opts.Algorithm = AlgorithmArgon2id
+	}
+	if h.opts.BcryptCost == 0 {
+		h.opts.BcryptCost = bcrypt.DefaultCost
+	}
+	if h.opts.Argon2Time == 0 {
+		h.opts.Argon2Time = 3
+	}
+	if h.opts.Argon2Memory == 0 {
+		h.opts.Argon2Memory = 64 * 1024
+	}
+	if h.opts.Argon2Threads == 0 {
+		h.opts.Argon2Threads = 2
+	}
+	if h.opts.Argon2KeyLen == 0 {
+		h.opts.Argon2KeyLen = 32
+	}
+	if h.opts.Argon2SaltLen == 0 {
+		h.opts.Argon2SaltLen = 16
+	}
+	return h
+}
+
+// Hash 使用配置的算法生成密码哈希
+func (h *Hasher) Hash(plain string) (string, error) {
+	switch h.opts.Algorithm {
+	case AlgorithmArgon2id:
+		salt := make([]byte, h.opts.Argon2SaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", fmt.Errorf("生成盐失败: %v", err)
+		}
+		key := argon2.IDKey([]byte(plain), salt, h.opts.Argon2Time, h.opts.Argon2Memory, h.opts.Argon2Threads, h.opts.Argon2KeyLen)
+		return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2idPrefix, argon2.Version, h.opts.Argon2Memory, h.opts.Argon2Time, h.opts.Argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	case AlgorithmBcrypt:
+		hashed, err := bcrypt.GenerateFromPassword([]byte(plain), h.opts.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("生成bcrypt哈希失败: %v", err)
+		}
+		return string(hashed), nil
+	default:
+		return "", fmt.Errorf("不支持的哈希算法: %s", h.opts.Algorithm)
+	}
+}
+
+// Verify 校验明文密码与存储值是否匹配
+func (h *Hasher) Verify(encoded, plain string) (bool, error) {
+	switch {
+	case strings.HasPrefix(encoded, argon2idPrefix):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, err
+		}
+		other := argon2.IDKey([]byte(plain), salt, params.Argon2Time, params.Argon2Memory, params.Argon2Threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1, nil
+	case bcryptPattern.MatchString(encoded):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(plain))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("校验bcrypt哈希失败: %v", err)
+		}
+		return true, nil
+	default:
+		// 历史明文密码
+		return subtle.ConstantTimeCompare([]byte(encoded), []byte(plain)) == 1, nil
+	}
+}
+
+// NeedsRehash 判断存储值是否需要按当前配置重新哈希
+// 明文、算法不一致或参数已变更时返回true
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	switch {
+	case strings.HasPrefix(encoded, argon2idPrefix):
+		if h.opts.Algorithm != AlgorithmArgon2id {
+			return true
+		}
+		params, _, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return true
+		}
+		return params.Argon2Time != h.opts.Argon2Time ||
+			params.Argon2Memory != h.opts.Argon2Memory ||
+			params.Argon2Threads != h.opts.Argon2Threads ||
+			uint32(len(key)) != h.opts.Argon2KeyLen
+	case bcryptPattern.MatchString(encoded):
+		if h.opts.Algorithm != AlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost != h.opts.BcryptCost
+	default:
+		return true
+	}
+}
+
+// IsHashed 判断存储值是否为已知格式的哈希
+func IsHashed(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix) || bcryptPattern.MatchString(encoded)
+}
+
+// decodeArgon2id 解析argon2id编码串
+// 格式: $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func decodeArgon2id(encoded string) (*HashOptions, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return nil, nil, nil, errors.New("argon2id哈希格式错误")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, fmt.Errorf("解析argon2id版本失败: %v", err)
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("不支持的argon2id版本: %d", version)
+	}
+
+	params := &HashOptions{Algorithm: AlgorithmArgon2id}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Argon2Memory, &params.Argon2Time, &params.Argon2Threads); err != nil {
+		return nil, nil, nil, fmt.Errorf("解析argon2id参数失败: %v", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("解析argon2id盐失败: %v", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("解析argon2id哈希失败: %v", err)
+	}
+	return params, salt, key, nil
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"gateService/pkg/password"
+	"strings"
+	"testing"
+)
+
+func Test_HashPassword(t *testing.T) {
+	argon := password.NewHasher(&password.HashOptions{Algorithm: password.AlgorithmArgon2id, Argon2Memory: 1024, Argon2Time: 1})
+	bcrypt := password.NewHasher(&password.HashOptions{Algorithm: password.AlgorithmBcrypt, BcryptCost: 4})
+
+	for name, hasher := range map[string]*password.Hasher{"argon2id": argon, "bcrypt": bcrypt} {
+		hashed, err := hasher.Hash("Abc123456!")
+		if err != nil {
+			t.Fatalf("%s: 生成哈希失败: %v", name, err)
+		}
+		if !password.IsHashed(hashed) || strings.Contains(hashed, "Abc123456!") {
+			t.Fatalf("%s: 哈希格式错误: %s", name, hashed)
+		}
+		if ok, err := hasher.Verify(hashed, "Abc123456!"); err != nil || !ok {
+			t.Fatalf("%s: 正确密码校验失败: %v", name, err)
+		}
+		if ok, _ := hasher.Verify(hashed, "Abc123456?"); ok {
+			t.Fatalf("%s: 错误密码校验通过", name)
+		}
+		if hasher.NeedsRehash(hashed) {
+			t.Fatalf("%s: 当前配置生成的哈希不应需要重新哈希", name)
+		}
+	}
+
+	// 切换算法后旧哈希仍可校验,但需要重新哈希
+	hashed, _ := bcrypt.Hash("Abc123456!")
+	if ok, _ := argon.Verify(hashed, "Abc123456!"); !ok {
+		t.Fatal("跨算法校验失败")
+	}
+	if !argon.NeedsRehash(hashed) {
+		t.Fatal("跨算法哈希应需要重新哈希")
+	}
+
+	// 历史明文密码
+	if ok, _ := argon.Verify("Abc123456!", "Abc123456!"); !ok {
+		t.Fatal("明文密码校验失败")
+	}
+	if !argon.NeedsRehash("Abc123456!") {
+		t.Fatal("明文密码应需要重新哈希")
+	}
+
+	// 以"$2"开头的历史明文密码不能被当作bcrypt哈希
+	if password.IsHashed("$2bigsecret") {
+		t.Fatal("明文密码被识别为哈希")
+	}
+	if ok, err := argon.Verify("$2bigsecret", "$2bigsecret"); err != nil || !ok {
+		t.Fatalf("以$2开头的明文密码校验失败: %v", err)
+	}
+}
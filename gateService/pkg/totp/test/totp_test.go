@@ -0,0 +1,47 @@
+package test
+
+import (
+	"encoding/base32"
+	"gateService/pkg/totp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_TOTP(t *testing.T) {
+	// RFC 6238 附录B测试向量(SHA1),取后6位
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	cases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for ts, want := range cases {
+		code, err := totp.GenerateCode(secret, time.Unix(ts, 0))
+		if err != nil {
+			t.Fatalf("生成验证码失败: %v", err)
+		}
+		if code != want {
+			t.Fatalf("时间%d: 期望%s, 实际%s", ts, want, code)
+		}
+	}
+
+	// 允许前后一个时间步的偏差
+	now := time.Unix(1111111111, 0)
+	prev, _ := totp.GenerateCode(secret, now.Add(-totp.Period))
+	if ok, _, _ := totp.Validate(secret, prev, now); !ok {
+		t.Fatal("上一时间步的验证码应校验通过")
+	}
+	old, _ := totp.GenerateCode(secret, now.Add(-3*totp.Period))
+	if ok, _, _ := totp.Validate(secret, old, now); ok {
+		t.Fatal("超出偏差范围的验证码不应校验通过")
+	}
+
+	uri := totp.ProvisioningURI("Zanime", "user@example.com", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Zanime:user@example.com?") || !strings.Contains(uri, "secret="+secret) {
+		t.Fatalf("otpauth地址格式错误: %s", uri)
+	}
+}
@@ -0,0 +1,24 @@
+package test
+
+import (
+	"gateService/pkg/useragent"
+	"testing"
+)
+
+func Test_Parse(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36":                     "Chrome / Windows",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0":       "Edge / Windows",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15":               "Safari / macOS",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 MicroMessenger/8.0.42": "微信 / iOS",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36":               "Chrome / Android",
+		"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0":                                                      "Firefox / Linux",
+		"curl/8.4.0": "未知设备",
+		"":           "未知设备",
+	}
+	for ua, want := range cases {
+		if got := useragent.Parse(ua); got != want {
+			t.Fatalf("User-Agent %q: 期望%s, 实际%s", ua, want, got)
+		}
+	}
+}
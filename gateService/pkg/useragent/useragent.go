@@ -0,0 +1,60 @@
+package useragent
+
+import "strings"
+
+// rule User-Agent中包含token时识别为name
+type rule struct {
+	token string
+	name  string
+}
+
+// 浏览器识别规则,按顺序匹配,基于Chromium的浏览器需排在Chrome之前
+var browsers = []rule{
+	{"MicroMessenger", "微信"},
+	{"Edg", "Edge"},
+	{"OPR", "Opera"},
+	{"Firefox", "Firefox"},
+	{"FxiOS", "Firefox"},
+	{"CriOS", "Chrome"},
+	{"Chrome", "Chrome"},
+	{"Safari", "Safari"},
+}
+
+// 操作系统识别规则,按顺序匹配,Android需排在Linux之前
+var systems = []rule{
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// Parse 将User-Agent解析为便于展示的设备描述,例如"Chrome / Windows"
+// 仅识别常见浏览器和操作系统,无法识别时返回"未知设备"
+func Parse(ua string) string {
+	browser := match(ua, browsers)
+	system := match(ua, systems)
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " / " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "未知设备"
+	}
+}
+
+// match 返回第一条匹配规则的名称
+func match(ua string, rules []rule) string {
+	for _, rule := range rules {
+		if strings.Contains(ua, rule.token) {
+			return rule.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,308 @@
+package linkHealthChecker
+
+import (
+	"context"
+	"encoding/json"
+	"gateService/internal/application/consumer"
+	"gateService/internal/domain/entity"
+	"gateService/internal/domain/repository"
+	"gateService/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 与检查器中连续失败的移除阈值一致
+const failThreshold = 3
+
+// fakeVideoRepository 在内存中保存播放源缓存和数据库中保存的爬取结果
+type fakeVideoRepository struct {
+	repository.VideoRepository
+	cached  map[string][]*entity.VideoSource
+	stored  map[string][]string // 播放源key到已从数据库移除的地址
+	fetched []string            // 按顺序记录读取过的播放源key
+}
+
+func (r *fakeVideoRepository) GetVideoSources(ctx context.Context, key string) ([]*entity.VideoSource, error) {
+	r.fetched = append(r.fetched, key)
+	return r.cached[key], nil
+}
+
+func (r *fakeVideoRepository) RemoveVideoSources(ctx context.Context, key string, urls []string) (int64, error) {
+	remaining := make([]*entity.VideoSource, 0)
+	for _, source := range r.cached[key] {
+		removed := false
+		for _, url := range urls {
+			if source.URL == url {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			remaining = append(remaining, source)
+		}
+	}
+	r.cached[key] = remaining
+	return int64(len(remaining)), nil
+}
+
+func (r *fakeVideoRepository) RemoveStoredVideoSources(ctx context.Context, videoID int, episode string, urls []string) error {
+	key := entity.VideoSourceKey(videoID, episode)
+	r.stored[key] = append(r.stored[key], urls...)
+	return nil
+}
+
+// fakeProgressRepository 返回固定的最近观看剧集
+type fakeProgressRepository struct {
+	repository.ProgressRepository
+	recent []*entity.WatchedEpisode
+}
+
+func (r *fakeProgressRepository) ListRecentlyWatchedEpisodes(ctx context.Context, window time.Duration, limit int) ([]*entity.WatchedEpisode, error) {
+	return r.recent, nil
+}
+
+// fakeHealthRepository 在内存中记录探测统计和连续失败次数,每轮都能获取执行权
+type fakeHealthRepository struct {
+	sampled []string
+	alive   map[string]int
+	dead    map[string]int
+	streaks map[string]int64
+}
+
+func (r *fakeHealthRepository) AcquireCheckRound(ctx context.Context, interval time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeHealthRepository) SampleVideoSourceKeys(ctx context.Context, count int) ([]string, error) {
+	return r.sampled, nil
+}
+
+func (r *fakeHealthRepository) RecordProbeResults(ctx context.Context, alive, dead map[string]int) error {
+	for origin, count := range alive {
+		r.alive[origin] += count
+	}
+	for origin, count := range dead {
+		r.dead[origin] += count
+	}
+	return nil
+}
+
+func (r *fakeHealthRepository) UpdateFailureStreaks(ctx context.Context, failed, recovered []string) (map[string]int64, error) {
+	for _, url := range recovered {
+		delete(r.streaks, url)
+	}
+	streaks := make(map[string]int64, len(failed))
+	for _, url := range failed {
+		r.streaks[url]++
+		streaks[url] = r.streaks[url]
+	}
+	return streaks, nil
+}
+
+func (r *fakeHealthRepository) GetLinkHealthStats(ctx context.Context) ([]*entity.LinkHealthStat, error) {
+	return nil, nil
+}
+
+// fakePublisher 记录投递的重新爬取任务
+type fakePublisher struct {
+	mu   sync.Mutex
+	jobs []*entity.VideoScrapeJob
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, topic string, msg []byte) error {
+	var job entity.VideoScrapeJob
+	if err := json.Unmarshal(msg, &job); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.jobs = append(p.jobs, &job)
+	p.mu.Unlock()
+	return nil
+}
+
+// newVideoHost 模拟视频站,不同路径对应不同的响应行为
+func newVideoHost() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok.mp4", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/gone.mp4", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/gone2.mp4", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	// 防盗链拒绝探测请求,无法确认地址失效
+	mux.HandleFunc("/forbidden.mp4", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	return httptest.NewServer(mux)
+}
+
+type fixture struct {
+	checker  *consumer.LinkHealthChecker
+	videos   *fakeVideoRepository
+	health   *fakeHealthRepository
+	producer *fakePublisher
+}
+
+func newFixture(cached map[string][]*entity.VideoSource, recent []*entity.WatchedEpisode, sampled []string) *fixture {
+	logger.Log = zap.NewNop()
+
+	f := &fixture{
+		videos: &fakeVideoRepository{cached: cached, stored: make(map[string][]string)},
+		health: &fakeHealthRepository{
+			sampled: sampled,
+			alive:   make(map[string]int),
+			dead:    make(map[string]int),
+			streaks: make(map[string]int64),
+		},
+		producer: &fakePublisher{},
+	}
+	f.checker = consumer.NewLinkHealthChecker(f.videos, &fakeProgressRepository{recent: recent}, f.health, f.producer)
+	return f
+}
+
+// checkRounds 连续执行多轮检查
+func (f *fixture) checkRounds(t *testing.T, rounds int) {
+	for i := 0; i < rounds; i++ {
+		if err := f.checker.Check(context.Background()); err != nil {
+			t.Fatalf("第%d轮检查失败: %v", i+1, err)
+		}
+	}
+}
+
+func Test_CheckSamplesRecentlyWatchedFirst(t *testing.T) {
+	server := newVideoHost()
+	defer server.Close()
+
+	watched := entity.VideoSourceKey(2, "第02集")
+	other := entity.VideoSourceKey(1, "第01集")
+	f := newFixture(
+		map[string][]*entity.VideoSource{
+			watched: {{URL: server.URL + "/ok.mp4"}},
+			other:   {{URL: server.URL + "/ok.mp4"}},
+		},
+		[]*entity.WatchedEpisode{{VideoID: 2, Episode: "第02集"}},
+		// 抽样结果中同样包含最近观看的剧集,只检查一次
+		[]string{other, watched},
+	)
+	f.checkRounds(t, 1)
+
+	if len(f.videos.fetched) != 2 || f.videos.fetched[0] != watched || f.videos.fetched[1] != other {
+		t.Errorf("检查顺序: %v, 期望 [%s %s]", f.videos.fetched, watched, other)
+	}
+}
+
+func Test_CheckEvictsDeadSourcesFromBothTiers(t *testing.T) {
+	server := newVideoHost()
+	defer server.Close()
+
+	// 关闭后的地址请求失败,模拟网络错误
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL + "/ok.mp4"
+	closed.Close()
+
+	key := entity.VideoSourceKey(1, "第01集")
+	f := newFixture(
+		map[string][]*entity.VideoSource{
+			key: {
+				{URL: server.URL + "/ok.mp4"},
+				{URL: server.URL + "/gone.mp4"},
+				{URL: server.URL + "/forbidden.mp4"},
+				{URL: closedURL},
+			},
+		},
+		nil,
+		[]string{key},
+	)
+
+	// 未达到连续失败次数时不移除
+	f.checkRounds(t, failThreshold-1)
+	if len(f.videos.cached[key]) != 4 || len(f.videos.stored[key]) != 0 {
+		t.Fatalf("连续失败%d轮后缓存剩余%d个播放源、数据库移除%v, 期望不移除", failThreshold-1, len(f.videos.cached[key]), f.videos.stored[key])
+	}
+
+	f.checkRounds(t, 1)
+	remaining := make(map[string]bool)
+	for _, source := range f.videos.cached[key] {
+		remaining[source.URL] = true
+	}
+	if len(remaining) != 3 || remaining[server.URL+"/gone.mp4"] {
+		t.Errorf("缓存剩余播放源: %v, 期望移除 /gone.mp4", remaining)
+	}
+	if !remaining[server.URL+"/forbidden.mp4"] || !remaining[closedURL] {
+		t.Errorf("缓存剩余播放源: %v, 期望保留403和网络错误的地址", remaining)
+	}
+	if stored := f.videos.stored[key]; len(stored) != 1 || stored[0] != server.URL+"/gone.mp4" {
+		t.Errorf("数据库移除的播放源: %v, 期望 [%s]", stored, server.URL+"/gone.mp4")
+	}
+}
+
+func Test_CheckRescrapesOnlyWhenNoSourcesRemain(t *testing.T) {
+	server := newVideoHost()
+	defer server.Close()
+
+	partial := entity.VideoSourceKey(1, "第01集")
+	allDead := entity.VideoSourceKey(2, "第02集")
+	f := newFixture(
+		map[string][]*entity.VideoSource{
+			partial: {{URL: server.URL + "/ok.mp4"}, {URL: server.URL + "/gone.mp4"}},
+			allDead: {{URL: server.URL + "/gone2.mp4"}},
+		},
+		nil,
+		[]string{partial, allDead},
+	)
+	f.checkRounds(t, failThreshold)
+
+	if len(f.producer.jobs) != 1 {
+		t.Fatalf("投递了%d个重新爬取任务, 期望1个", len(f.producer.jobs))
+	}
+	job := f.producer.jobs[0]
+	if job.VideoID != 2 || job.Episode != "第02集" || !job.Replace || job.Reason != "link_check" {
+		t.Errorf("重新爬取任务: %+v, 期望视频2第02集并忽略已保存的结果", job)
+	}
+}
+
+func Test_CheckRecordsStatsPerOrigin(t *testing.T) {
+	server := newVideoHost()
+	defer server.Close()
+
+	key := entity.VideoSourceKey(1, "第01集")
+	f := newFixture(
+		map[string][]*entity.VideoSource{
+			key: {
+				{URL: server.URL + "/ok.mp4", Origin: "siteA"},
+				{URL: server.URL + "/gone.mp4", Origin: "siteA"},
+				{URL: server.URL + "/forbidden.mp4", Origin: "siteB"},
+				// 未返回来源站点时按域名统计
+				{URL: server.URL + "/ok.mp4?v=2"},
+			},
+		},
+		nil,
+		[]string{key},
+	)
+	f.checkRounds(t, 1)
+
+	host := server.Listener.Addr().String()
+	expectAlive := map[string]int{"siteA": 1, host: 1}
+	expectDead := map[string]int{"siteA": 1, "siteB": 1}
+	for origin, count := range expectAlive {
+		if f.health.alive[origin] != count {
+			t.Errorf("%s 可用次数: %d, 期望 %d", origin, f.health.alive[origin], count)
+		}
+	}
+	for origin, count := range expectDead {
+		if f.health.dead[origin] != count {
+			t.Errorf("%s 失效次数: %d, 期望 %d", origin, f.health.dead[origin], count)
+		}
+	}
+	if len(f.health.alive) != len(expectAlive) || len(f.health.dead) != len(expectDead) {
+		t.Errorf("统计: alive=%v dead=%v, 期望 alive=%v dead=%v", f.health.alive, f.health.dead, expectAlive, expectDead)
+	}
+}
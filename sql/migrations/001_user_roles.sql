@@ -0,0 +1,3 @@
+-- 用户角色：user-普通用户，moderator-版主，admin-管理员
+ALTER TABLE `user_infos`
+  ADD COLUMN `role` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT 'user' COMMENT '用户角色：user/moderator/admin' AFTER `status`;
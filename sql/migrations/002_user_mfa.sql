@@ -0,0 +1,21 @@
+-- 用户两步验证(TOTP)配置
+CREATE TABLE `user_mfa`  (
+  `user_id` int NOT NULL COMMENT '用户ID',
+  `secret` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'TOTP密钥(base32)',
+  `enabled` tinyint NOT NULL DEFAULT 0 COMMENT '是否已启用：0-待确认，1-已启用',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`) USING BTREE,
+  CONSTRAINT `user_mfa_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `user_infos` (`user_id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci ROW_FORMAT = DYNAMIC;
+
+-- 两步验证恢复码，仅保存哈希，每个恢复码只能使用一次
+CREATE TABLE `user_mfa_recovery_codes`  (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL COMMENT '用户ID',
+  `code_hash` char(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '恢复码SHA-256哈希',
+  `used_at` timestamp NULL DEFAULT NULL COMMENT '使用时间，为空表示未使用',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_user_code`(`user_id` ASC, `code_hash` ASC) USING BTREE,
+  CONSTRAINT `user_mfa_recovery_codes_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `user_infos` (`user_id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci ROW_FORMAT = DYNAMIC;
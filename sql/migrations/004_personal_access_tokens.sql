@@ -0,0 +1,16 @@
+-- 个人访问令牌，供脚本通过 Authorization: Bearer 请求头调用接口，仅保存令牌哈希
+CREATE TABLE `personal_access_tokens`  (
+  `token_id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL COMMENT '用户ID',
+  `name` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '令牌名称',
+  `token_hash` char(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '令牌SHA-256哈希',
+  `token_prefix` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '令牌前缀，仅用于列表中识别令牌',
+  `scopes` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '授权范围，逗号分隔',
+  `expires_at` timestamp NULL DEFAULT NULL COMMENT '过期时间，为空表示永不过期',
+  `last_used_at` timestamp NULL DEFAULT NULL COMMENT '最近使用时间，为空表示从未使用',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`token_id`) USING BTREE,
+  UNIQUE INDEX `idx_token_hash`(`token_hash` ASC) USING BTREE,
+  INDEX `idx_user_id`(`user_id` ASC) USING BTREE,
+  CONSTRAINT `personal_access_tokens_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `user_infos` (`user_id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci ROW_FORMAT = DYNAMIC;
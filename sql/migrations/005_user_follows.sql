@@ -0,0 +1,11 @@
+-- 用户关注关系，follower_id 关注 followee_id
+CREATE TABLE `user_follows`  (
+  `follower_id` int NOT NULL COMMENT '关注者用户ID',
+  `followee_id` int NOT NULL COMMENT '被关注者用户ID',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '关注时间',
+  PRIMARY KEY (`follower_id`, `followee_id`) USING BTREE,
+  INDEX `idx_followee_time`(`followee_id` ASC, `created_at` ASC) USING BTREE COMMENT '粉丝列表索引',
+  INDEX `idx_follower_time`(`follower_id` ASC, `created_at` ASC) USING BTREE COMMENT '关注列表索引',
+  CONSTRAINT `user_follows_ibfk_1` FOREIGN KEY (`follower_id`) REFERENCES `user_infos` (`user_id`) ON DELETE CASCADE ON UPDATE RESTRICT,
+  CONSTRAINT `user_follows_ibfk_2` FOREIGN KEY (`followee_id`) REFERENCES `user_infos` (`user_id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '用户关注表' ROW_FORMAT = DYNAMIC;
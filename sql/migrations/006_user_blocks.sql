@@ -0,0 +1,13 @@
+-- 用户屏蔽关系，user_id 拉黑或静音 target_user_id
+-- block_type: 1 拉黑（对方不能回复、关注自己）, 2 静音（不再接收对方的通知）
+CREATE TABLE `user_blocks`  (
+  `user_id` int NOT NULL COMMENT '发起屏蔽的用户ID',
+  `target_user_id` int NOT NULL COMMENT '被屏蔽的用户ID',
+  `block_type` tinyint NOT NULL COMMENT '屏蔽类型: 1拉黑 2静音',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '屏蔽时间',
+  PRIMARY KEY (`user_id`, `block_type`, `target_user_id`) USING BTREE,
+  INDEX `idx_user_type_time`(`user_id` ASC, `block_type` ASC, `created_at` ASC) USING BTREE COMMENT '屏蔽列表索引',
+  INDEX `idx_target`(`target_user_id` ASC) USING BTREE COMMENT '注销时清理索引',
+  CONSTRAINT `user_blocks_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `user_infos` (`user_id`) ON DELETE CASCADE ON UPDATE RESTRICT,
+  CONSTRAINT `user_blocks_ibfk_2` FOREIGN KEY (`target_user_id`) REFERENCES `user_infos` (`user_id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '用户屏蔽表' ROW_FORMAT = DYNAMIC;
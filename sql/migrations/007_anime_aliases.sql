@@ -0,0 +1,10 @@
+-- 视频别名（译名、简称、原名等），参与全文检索
+CREATE TABLE `anime_aliases`  (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `anime_id` int NOT NULL COMMENT '视频ID',
+  `alias` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '别名',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_anime_alias`(`anime_id` ASC, `alias` ASC) USING BTREE,
+  CONSTRAINT `fk_anime_aliases_video` FOREIGN KEY (`anime_id`) REFERENCES `anime_videos` (`video_id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '视频别名表' ROW_FORMAT = DYNAMIC;
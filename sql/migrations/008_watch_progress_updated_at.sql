@@ -0,0 +1,2 @@
+-- 播放地址健康检查按最近观看时间抽样剧集
+ALTER TABLE `user_watch_progress` ADD INDEX `idx_updated_at`(`updated_at` ASC) USING BTREE;
@@ -0,0 +1,5 @@
+-- 爬取到的播放源持久化到video_urls,作为Redis之后的第二级缓存,Redis清空后无需重新爬取全部剧集
+-- video_url仍为管理员指定的播放地址,不为空时优先于爬取结果
+ALTER TABLE `video_urls`
+  ADD COLUMN `sources` json NULL COMMENT '最近一次爬取到的播放源,按评分从高到低排列' AFTER `video_url`,
+  ADD COLUMN `scraped_at` timestamp NULL DEFAULT NULL COMMENT '最近一次爬取时间,超过有效期后重新爬取' AFTER `sources`;